@@ -0,0 +1,316 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+)
+
+// runAnalysis answers a single question about a saved game and prints the
+// answer as plain text so that it can be piped into other tools.
+func runAnalysis(out io.Writer, cmd string) error {
+	switch cmd {
+	case "prob":
+		gs, err := loadSave(*probSave)
+		if err != nil {
+			return err
+		}
+		city, err := getCityByPrefix(*probCity, gs)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%v\t%.3f%v\n", city, gs.ProbabilityOfCity(city), positionBadge(gs, city))
+	case "threats":
+		gs, err := loadSave(*threatsSave)
+		if err != nil {
+			return err
+		}
+		for _, city := range gs.SortByProbability(gs.Cities.CityNames()) {
+			prob := gs.ProbabilityOfCity(city)
+			spillover := gs.NeighborSpillover(city)
+			if prob == 0.0 && spillover == 0.0 {
+				continue
+			}
+			data, _ := gs.GetCity(city)
+			outbreak := ""
+			if gs.CanOutbreak(city) {
+				outbreak = "\toutbreak"
+			}
+			fmt.Fprintf(out, "%v\t%v\t%v\t%.3f\t%.3f%v%v\n", city, data.Disease, data.NumInfections, prob, spillover, outbreak, positionBadge(gs, city))
+		}
+	case "forecast":
+		gs, err := loadSave(*forecastSave)
+		if err != nil {
+			return err
+		}
+		analysis := gs.CityDeck.EpidemicAnalysis()
+		fmt.Fprintf(out, "epidemic this turn\t%.3f\n", analysis.FirstCardProbability+analysis.SecondCardProbability)
+		fmt.Fprintf(out, "epidemic on first card\t%.3f\n", analysis.FirstCardProbability)
+		fmt.Fprintf(out, "epidemic on second card\t%.3f\n", analysis.SecondCardProbability)
+		fmt.Fprintf(out, "second after first\t%.3f\n", analysis.SecondCardEpiAfterFirstEpi)
+		fmt.Fprintf(out, "guaranteed safe draws\t%v\n", analysis.ComingDrawsWith0)
+		fmt.Fprintf(out, "scenarios guaranteeing an epidemic\t%v of %v\n", analysis.ScenariosWith100, analysis.PossibleScenarios)
+		if *forecastPhases > 0 {
+			printPhases(out, fmt.Sprintf, gs, *forecastPhases)
+		}
+	case "worstcase":
+		gs, err := loadSave(*worstCaseSave)
+		if err != nil {
+			return err
+		}
+		printWorstCase(out, fmt.Sprintf, gs)
+	case "eventodds":
+		gs, err := loadSave(*eventOddsSave)
+		if err != nil {
+			return err
+		}
+		return printEventOdds(out, fmt.Sprintf, gs, *eventOddsEvent)
+	case "verify":
+		gs, err := loadSave(*verifySave)
+		if err != nil {
+			return err
+		}
+		return printVerify(out, fmt.Sprintf, gs)
+	case "dump":
+		gs, err := loadSave(*dumpSave)
+		if err != nil {
+			return err
+		}
+		return gs.Dump(out)
+	default:
+		return fmt.Errorf("%v is not an analysis command", cmd)
+	}
+	return nil
+}
+
+func printWorstCase(out io.Writer, t translator, gs *pandemic.GameState) {
+	worst := gs.WorstCase()
+	if worst.EpidemicPossible {
+		fmt.Fprintln(out, t("including an epidemic"))
+	}
+	fmt.Fprint(out, t("outbreaks\t%v\t%v\n", worst.Outbreaks, worst.OutbreakCities))
+	diseases := []string{}
+	for dt := range worst.Cubes {
+		diseases = append(diseases, string(dt))
+	}
+	sort.Strings(diseases)
+	for _, dt := range diseases {
+		fmt.Fprint(out, t("%v cubes\t%v\n", dt, worst.Cubes[pandemic.DiseaseType(dt)]))
+	}
+	if lost, reason := gs.GuaranteedLoss(); lost {
+		fmt.Fprint(out, t("guaranteed loss\t%v\n", reason))
+	}
+}
+
+// printWhatIf lays out the whole threat panel as it would be with the event
+// played, striation by striation and then the discard pile, each city's odds
+// now next to its odds then, so the table can see what spending it would
+// buy. Cities the event changes are starred. The cities are sorted like the
+// panel, by how likely they are to be infected or by how bad it would be.
+func printWhatIf(out io.Writer, t translator, gs *pandemic.GameState, event pandemic.FundedEventName, city pandemic.CityName, byProbability bool) error {
+	then, err := gs.WhatIf(event, city)
+	if err != nil {
+		return err
+	}
+	sorted := func(cities []pandemic.CityName) []pandemic.CityName {
+		if byProbability {
+			return then.SortByProbability(cities)
+		}
+		return then.SortBySeverity(cities)
+	}
+	changed := 0
+	row := func(city pandemic.CityName) {
+		probNow, probThen := gs.ProbabilityOfCity(city), then.ProbabilityOfCity(city)
+		spillNow, spillThen := gs.NeighborSpillover(city), then.NeighborSpillover(city)
+		mark, outbreak := "  ", ""
+		if probNow != probThen || spillNow != spillThen {
+			mark = "* "
+			changed++
+		}
+		if gs.CanOutbreak(city) && !then.CanOutbreak(city) {
+			outbreak = "\t" + t("no longer outbreaks")
+		}
+		data, _ := then.GetCity(city)
+		fmt.Fprintf(out, "%v%v\t%v\t%.3f\t%.3f\t%.3f\t%.3f%v\n", mark, city, data.NumInfections, probNow, probThen, spillNow, spillThen, outbreak)
+	}
+	fmt.Fprintln(out, t("  city\tcubes\tnow\tthen\tspillover now\tthen"))
+	deck := then.InfectionDeck
+	for i := len(deck.Striations) - 1; i >= 0; i-- {
+		fmt.Fprintln(out, t("Infection %v", i))
+		for _, city := range sorted(deck.CitiesInStriation(i)) {
+			row(city)
+		}
+	}
+	fmt.Fprintln(out, t("Infection Discard (newest first)"))
+	for _, city := range deck.DiscardPile() {
+		row(city)
+	}
+	if removed := deck.Removed.Members(); len(removed) > 0 {
+		fmt.Fprint(out, t("Removed: %v\n", strings.Join(removed, ", ")))
+	}
+	if changed == 0 {
+		fmt.Fprint(out, t("%v would not change the odds of any city\n", event))
+	}
+	return nil
+}
+
+// printEventOdds gives the chance of drawing the event before the next
+// epidemic, or of every event still in the city deck when none is named.
+func printEventOdds(out io.Writer, t translator, gs *pandemic.GameState, prefix string) error {
+	events := []pandemic.CardName{}
+	if prefix != "" {
+		card, err := gs.CityDeck.GetCardByPrefix(prefix)
+		if err != nil {
+			return err
+		}
+		events = append(events, card.Name())
+	} else {
+		for _, card := range gs.CityDeck.All {
+			if card.IsFundedEvent() && gs.CityDeck.Location(card.Name()) == pandemic.InDeck {
+				events = append(events, card.Name())
+			}
+		}
+		if len(events) == 0 {
+			return errors.New(t("There are no events left in the city deck"))
+		}
+	}
+	for _, event := range events {
+		odds, err := gs.CityDeck.ProbabilityOfEventBeforeEpidemic(event)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(out, t("%v before the next epidemic\t%.3f\n", event, odds))
+	}
+	return nil
+}
+
+// printEventLedger lists every event card with who holds it or whether it
+// was played.
+func printEventLedger(out io.Writer, t translator, gs *pandemic.GameState) {
+	ledger := gs.EventLedger()
+	if len(ledger) == 0 {
+		fmt.Fprintln(out, t("There are no event cards in this game"))
+		return
+	}
+	for _, event := range ledger {
+		where := t("in the %v", event.Location)
+		switch {
+		case event.Stored:
+			where = t("stored by %v", event.Holder.HumanName)
+		case event.Holder != nil:
+			where = t("held by %v", event.Holder.HumanName)
+		case event.Played():
+			where = t("played")
+		}
+		fmt.Fprintf(out, "%v\t%v\n", event.Name, where)
+	}
+}
+
+// printAudit lists every city deck draw with the piles it could be from, to
+// help find an entry that was missed or made twice.
+func printAudit(out io.Writer, t translator, gs *pandemic.GameState) {
+	fmt.Fprintln(out, t("City deck draws (pile):"))
+	for _, entry := range gs.CityDeck.Audit() {
+		pile := fmt.Sprintf("%v", entry.MinPile+1)
+		if entry.MaxPile != entry.MinPile {
+			pile = fmt.Sprintf("%v-%v", entry.MinPile+1, entry.MaxPile+1)
+		}
+		fmt.Fprintf(out, "%3v (%v) %v\n", entry.Draw, pile, entry.Card)
+	}
+	if gs.CityDeck.UnknownDraws > 0 {
+		fmt.Fprint(out, t("plus %v unknown draws\n", gs.CityDeck.UnknownDraws))
+	}
+	fmt.Fprintln(out, t("If a card was drawn but never entered, enter it with city-draw or unknown-draw, then the epidemic again."))
+	if gs.Log == nil || gs.GameTurns == nil {
+		return
+	}
+	fmt.Fprintln(out, t("This turn's events:"))
+	for _, event := range gs.Log.Events {
+		if event.Turn == gs.GameTurns.CurTurn {
+			fmt.Fprintf(out, "  %v\n", event.Message)
+		}
+	}
+	fmt.Fprintln(out, t("If cubes or markers differ from the board, correct them with set infections or set outbreaks."))
+}
+
+// printFlightRestrictions lists the flights that panic rules out, which
+// routes and advice already avoid.
+func printFlightRestrictions(out io.Writer, t translator, gs *pandemic.GameState) {
+	restrictions := gs.FlightRestrictions()
+	if len(restrictions) > 0 {
+		fmt.Fprint(out, t("Panic: %v\n", strings.Join(restrictions, ", ")))
+	}
+}
+
+// printVerify checks the odds shown against the reference engine, listing
+// any that disagree.
+func printVerify(out io.Writer, t translator, gs *pandemic.GameState) error {
+	discrepancies, err := gs.Verify()
+	for _, discrepancy := range discrepancies {
+		fmt.Fprintln(out, discrepancy)
+	}
+	if err != nil {
+		return errors.New(t("Could not finish checking: %v", err))
+	}
+	if len(discrepancies) > 0 {
+		return errors.New(t("%v odds disagree with the reference engine", len(discrepancies)))
+	}
+	fmt.Fprintln(out, t("All odds match the reference engine"))
+	return nil
+}
+
+const (
+	adviceTrials = 200
+	adviceShown  = 5
+)
+
+// printAdvice lists the best few actions for the mode. Without a mode the
+// advisor plays to win, unless the game is already lost, when it switches
+// to protecting the campaign. A cautious table has them ranked by their
+// worst case.
+func printAdvice(out io.Writer, t translator, gs *pandemic.GameState, risk RiskProfile, args []string) error {
+	mode := pandemic.WinMode
+	if len(args) > 0 {
+		var err error
+		if mode, err = pandemic.ParseAdvisorMode(args[0]); err != nil {
+			return err
+		}
+	} else if lost, _ := gs.GuaranteedLoss(); lost {
+		mode = pandemic.ConserveMode
+	}
+	advice, err := gs.Advise(mode, risk.WorstCase, adviceTrials, rand.New(rand.NewSource(time.Now().UnixNano())))
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(out, t("Advice to %v:\n", mode))
+	printFlightRestrictions(out, t, gs)
+	for i, a := range advice {
+		if i == adviceShown {
+			break
+		}
+		fmt.Fprint(out, t("%v\toutbreaks %.2f (worst %v)\tcampaign cost %.2f\t%v\n", a.Action, a.ExpectedOutbreaks, a.WorstOutbreaks, a.ExpectedCampaignCost, a.Cost))
+	}
+	return nil
+}
+
+// printDiscardAdvice ranks the player's hand, safest card to discard first.
+func printDiscardAdvice(out io.Writer, t translator, gs *pandemic.GameState, player *pandemic.Player) {
+	advice := gs.DiscardAdvice(player)
+	if len(advice) == 0 {
+		fmt.Fprint(out, t("%v has no recorded cards to discard\n", player.HumanName))
+		return
+	}
+	fmt.Fprint(out, t("Safest for %v to discard first:\n", player.HumanName))
+	for _, option := range advice {
+		fmt.Fprintf(out, "%v\t%+d\t%v\n", option.Card, option.Safety, strings.Join(option.Reasons, "; "))
+	}
+	if player.UnknownCards > 0 {
+		fmt.Fprint(out, t("%v more cards weren't recorded\n", player.UnknownCards))
+	}
+}
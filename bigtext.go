@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// bigFont draws letters five rows tall so that a card's name can be read
+// from across the table.
+var bigFont = map[rune][5]string{
+	'A': {" ### ", "#   #", "#####", "#   #", "#   #"},
+	'B': {"#### ", "#   #", "#### ", "#   #", "#### "},
+	'C': {" ####", "#    ", "#    ", "#    ", " ####"},
+	'D': {"#### ", "#   #", "#   #", "#   #", "#### "},
+	'E': {"#####", "#    ", "#### ", "#    ", "#####"},
+	'F': {"#####", "#    ", "#### ", "#    ", "#    "},
+	'G': {" ####", "#    ", "#  ##", "#   #", " ####"},
+	'H': {"#   #", "#   #", "#####", "#   #", "#   #"},
+	'I': {"#####", "  #  ", "  #  ", "  #  ", "#####"},
+	'J': {"#####", "   # ", "   # ", "#  # ", " ##  "},
+	'K': {"#   #", "#  # ", "###  ", "#  # ", "#   #"},
+	'L': {"#    ", "#    ", "#    ", "#    ", "#####"},
+	'M': {"#   #", "## ##", "# # #", "#   #", "#   #"},
+	'N': {"#   #", "##  #", "# # #", "#  ##", "#   #"},
+	'O': {" ### ", "#   #", "#   #", "#   #", " ### "},
+	'P': {"#### ", "#   #", "#### ", "#    ", "#    "},
+	'Q': {" ### ", "#   #", "# # #", "#  # ", " ## #"},
+	'R': {"#### ", "#   #", "#### ", "#  # ", "#   #"},
+	'S': {" ####", "#    ", " ### ", "    #", "#### "},
+	'T': {"#####", "  #  ", "  #  ", "  #  ", "  #  "},
+	'U': {"#   #", "#   #", "#   #", "#   #", " ### "},
+	'V': {"#   #", "#   #", "#   #", " # # ", "  #  "},
+	'W': {"#   #", "#   #", "# # #", "## ##", "#   #"},
+	'X': {"#   #", " # # ", "  #  ", " # # ", "#   #"},
+	'Y': {"#   #", " # # ", "  #  ", "  #  ", "  #  "},
+	'Z': {"#####", "   # ", "  #  ", " #   ", "#####"},
+	' ': {"     ", "     ", "     ", "     ", "     "},
+}
+
+// bigText renders the text in bigFont, or returns nil when it would be
+// wider than the given width. Letters the font lacks are left out.
+func bigText(text string, width int) []string {
+	rows := make([]string, 5)
+	for _, r := range strings.ToUpper(text) {
+		glyph, ok := bigFont[unicode.ToUpper(r)]
+		if !ok {
+			continue
+		}
+		for i := range rows {
+			rows[i] += glyph[i] + " "
+		}
+	}
+	if len(rows[0]) > width {
+		return nil
+	}
+	return rows
+}
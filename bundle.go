@@ -0,0 +1,186 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+)
+
+// The files in a session bundle. The config is the exporting player's
+// config file. On import it's laid under the campaign's own config
+// overrides rather than written over the new host's config file.
+const (
+	bundleSave     = "game.json"
+	bundleEvents   = "events.csv"
+	bundleCampaign = "campaign.json"
+	bundleConfig   = "config.toml"
+)
+
+// writeBundle writes everything needed to carry on the game on another
+// machine as a gzipped tar archive.
+func (p *PandemicView) writeBundle(out io.Writer, gameState *pandemic.GameState) error {
+	files := map[string][]byte{}
+	var err error
+	if files[bundleSave], err = json.Marshal(gameState); err != nil {
+		return errors.New(p.t("Could not marshal gamestate as JSON: %v", err))
+	}
+	events := &bytes.Buffer{}
+	if err := gameState.WriteEventsCSV(events, pandemic.TurnRange{}); err != nil {
+		return errors.New(p.t("Could not export the event log: %v", err))
+	}
+	files[bundleEvents] = events.Bytes()
+	if files[bundleCampaign], err = json.MarshalIndent(p.campaign, "", "  "); err != nil {
+		return errors.New(p.t("Could not marshal the campaign as JSON: %v", err))
+	}
+	if p.config.userFile != "" {
+		data, err := ioutil.ReadFile(p.config.userFile)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err == nil {
+			files[bundleConfig] = data
+		}
+	}
+
+	zipped := gzip.NewWriter(out)
+	archive := tar.NewWriter(zipped)
+	now := time.Now()
+	for _, name := range []string{bundleSave, bundleEvents, bundleCampaign, bundleConfig} {
+		data, ok := files[name]
+		if !ok {
+			continue
+		}
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: now}
+		if err := archive.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := archive.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return zipped.Close()
+}
+
+func readBundle(path string) (map[string][]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	zipped, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("%v is not a session bundle: %v", path, err)
+	}
+	archive := tar.NewReader(zipped)
+	files := map[string][]byte{}
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%v is not a session bundle: %v", path, err)
+		}
+		if files[header.Name], err = ioutil.ReadAll(archive); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := files[bundleSave]; !ok {
+		return nil, fmt.Errorf("%v has no %v to import", path, bundleSave)
+	}
+	return files, nil
+}
+
+// importBundle restores a bundle written by export bundle. The game is
+// checked like any save and written to the save folder, to be loaded from
+// there, and the campaign replaces this one once its file is backed up. It
+// returns the game imported and where the old campaign file was kept.
+func (p *PandemicView) importBundle(path string) (*pandemic.GameState, string, error) {
+	files, err := readBundle(path)
+	if err != nil {
+		return nil, "", err
+	}
+	checked, err := ioutil.TempFile("", "bundle")
+	if err != nil {
+		return nil, "", err
+	}
+	defer os.Remove(checked.Name())
+	_, err = checked.Write(files[bundleSave])
+	checked.Close()
+	if err != nil {
+		return nil, "", err
+	}
+	game, err := loadSave(checked.Name())
+	if err != nil {
+		return nil, "", errors.New(p.t("The bundle's game can't be loaded: %v", err))
+	}
+
+	backup := ""
+	if data, ok := files[bundleCampaign]; ok {
+		if config, ok := files[bundleConfig]; ok {
+			if data, err = layConfigUnder(data, config); err != nil {
+				return nil, "", err
+			}
+		}
+		if backup, err = p.campaign.Backup(time.Now()); err != nil {
+			return nil, "", errors.New(p.t("Could not back up the campaign: %v", err))
+		}
+		if err := p.campaign.Restore(data); err != nil {
+			return nil, "", err
+		}
+		if err := p.campaign.Save(); err != nil {
+			return nil, "", errors.New(p.t("Could not save campaign: %v", err))
+		}
+	}
+	if err := p.saveGame(game, "import"); err != nil {
+		return nil, "", err
+	}
+	return game, backup, nil
+}
+
+// machineSettings belong to the machine rather than the group, so they are
+// left out of an imported config.
+var machineSettings = []string{"save_dir", "speech_command", "keys", "hidden_panels", "telemetry", "integrations"}
+
+// layConfigUnder adds the settings in config to the campaign file's config
+// overrides, keeping any the campaign already overrides.
+func layConfigUnder(campaign, config []byte) ([]byte, error) {
+	var file map[string]json.RawMessage
+	if err := json.Unmarshal(campaign, &file); err != nil {
+		return nil, fmt.Errorf("Invalid campaign file in the bundle: %v", err)
+	}
+	config, err := tomlToJSON(config)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid config file in the bundle: %v", err)
+	}
+	settings := map[string]json.RawMessage{}
+	if err := json.Unmarshal(config, &settings); err != nil {
+		return nil, fmt.Errorf("Invalid config file in the bundle: %v", err)
+	}
+	for _, setting := range machineSettings {
+		delete(settings, setting)
+	}
+	if overrides, ok := file["config"]; ok {
+		if err := json.Unmarshal(overrides, &settings); err != nil {
+			return nil, fmt.Errorf("Invalid config in the bundle's campaign file: %v", err)
+		}
+	}
+	merged, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	file["config"] = merged
+	return json.Marshal(file)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+)
+
+func printFinalScore(out io.Writer, t translator, campaign *pandemic.Campaign) {
+	if !campaign.Complete() {
+		fmt.Fprintln(out, t("The campaign is not over yet, this is the score so far."))
+	}
+	score := campaign.FinalScore()
+	for _, line := range score.Lines {
+		fmt.Fprintf(out, "%6d  %v\n", line.Points, line.Description)
+	}
+	fmt.Fprint(out, t("%6d  Final score\n", score.Total))
+}
+
+func printCampaigns(out io.Writer, store *pandemic.CampaignStore) {
+	if len(store.Campaigns) == 0 {
+		fmt.Fprintln(out, "No campaigns yet, campaign switch <name> starts one")
+		return
+	}
+	for _, name := range store.Campaigns {
+		marker := " "
+		if name == store.Active {
+			marker = "*"
+		}
+		fmt.Fprintf(out, "%v %v\t%v\n", marker, name, store.Dir(name))
+	}
+}
+
+// confirmInfectionDeck lists where a new game's infection deck differs from
+// the campaign and asks whether to start anyway.
+func confirmInfectionDeck(in io.Reader, out io.Writer, problems []string) bool {
+	fmt.Fprintln(out, "The infection deck doesn't match the campaign file:")
+	for _, problem := range problems {
+		fmt.Fprintf(out, "  %v\n", problem)
+	}
+	fmt.Fprint(out, "Start anyway? [y/N] ")
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	return strings.ToLower(strings.TrimSpace(answer)) == "y"
+}
+
+// campaignGames loads the latest save of every game in the save folder. The
+// game in progress, if any, stands in for its own saves.
+func campaignGames(saveDir string, current *pandemic.GameState) []*pandemic.GameState {
+	games := []*pandemic.GameState{}
+	if current != nil {
+		games = append(games, current)
+	}
+	dirs, err := ioutil.ReadDir(saveDir)
+	if err != nil {
+		return games
+	}
+	for _, dir := range dirs {
+		if !dir.IsDir() || (current != nil && dir.Name() == current.GameName) {
+			continue
+		}
+		save, err := latestSave(filepath.Join(saveDir, dir.Name()))
+		if err != nil {
+			continue
+		}
+		if game, err := pandemic.LoadGame(save); err == nil {
+			games = append(games, game)
+		}
+	}
+	return games
+}
+
+func printCityStats(out io.Writer, t translator, record *pandemic.CityRecord, label string) {
+	fmt.Fprintf(out, "%v\n", label)
+	if record == nil || record.FirstInfected == "" {
+		fmt.Fprintln(out, t("  never infected"))
+	} else {
+		fmt.Fprint(out, t("  first infected in %v\n", record.FirstInfected))
+	}
+	if record != nil {
+		fmt.Fprint(out, t("  %v outbreaks, fell in %v games\n", record.Outbreaks, record.TimesFallen))
+	}
+}
+
+const cityRecordsShown = 5
+
+// printCityRecords lists the cities that suffered most over the campaign.
+func printCityRecords(out io.Writer, t translator, records map[pandemic.CityName]*pandemic.CityRecord) {
+	worst := []*pandemic.CityRecord{}
+	for _, record := range records {
+		if record.Outbreaks > 0 || record.TimesFallen > 0 {
+			worst = append(worst, record)
+		}
+	}
+	if len(worst) == 0 {
+		return
+	}
+	sort.Sort(byOutbreaks(worst))
+	fmt.Fprintln(out, t("\nMost outbreaks"))
+	for i, record := range worst {
+		if i == cityRecordsShown {
+			break
+		}
+		fmt.Fprint(out, t("%6d  %v (first infected in %v, fell in %v games)\n", record.Outbreaks, record.City, record.FirstInfected, record.TimesFallen))
+	}
+}
+
+type byOutbreaks []*pandemic.CityRecord
+
+func (b byOutbreaks) Len() int      { return len(b) }
+func (b byOutbreaks) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byOutbreaks) Less(i, j int) bool {
+	if b[i].Outbreaks != b[j].Outbreaks {
+		return b[i].Outbreaks > b[j].Outbreaks
+	}
+	if b[i].TimesFallen != b[j].TimesFallen {
+		return b[i].TimesFallen > b[j].TimesFallen
+	}
+	return b[i].City < b[j].City
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+	"github.com/jroimartin/gocui"
+)
+
+const checkpointFile = "checkpoint.json"
+
+// A checkpoint is written when a session starts and again whenever the
+// tracker sits idle, and removed when the session quits cleanly. Finding
+// one at startup means the last session was lost, along with anything it
+// had staged.
+type checkpoint struct {
+	Time   time.Time           `json:"time"`
+	Game   *pandemic.GameState `json:"game"`
+	Staged []string            `json:"staged,omitempty"`
+}
+
+func checkpointPath(saveDir, gameName string) string {
+	return filepath.Join(saveDir, gameName, checkpointFile)
+}
+
+func (p *PandemicView) writeCheckpoint(game *pandemic.GameState) error {
+	data, err := json.Marshal(checkpoint{Time: time.Now(), Game: game, Staged: p.staged})
+	if err != nil {
+		return err
+	}
+	path := checkpointPath(p.config.SaveDir, game.GameName)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+func (p *PandemicView) removeCheckpoint(game *pandemic.GameState) {
+	err := os.Remove(checkpointPath(p.config.SaveDir, game.GameName))
+	if err != nil && !os.IsNotExist(err) {
+		p.logger.Errorf("Could not remove checkpoint: %v", err)
+	}
+}
+
+// watchIdle checkpoints the game once nobody has entered a command for the
+// configured number of minutes.
+func (p *PandemicView) watchIdle(game *pandemic.GameState, gui *gocui.Gui) {
+	idle := time.Duration(p.config.IdleSnapshotMinutes) * time.Minute
+	if idle <= 0 {
+		return
+	}
+	p.lastCommand = time.Now()
+	go func() {
+		for range time.Tick(time.Minute) {
+			gui.Execute(func(gui *gocui.Gui) error {
+				if p.checkpointed || time.Since(p.lastCommand) < idle {
+					return nil
+				}
+				if err := p.writeCheckpoint(game); err != nil {
+					p.logger.Errorf("Could not write checkpoint: %v", err)
+					return nil
+				}
+				p.checkpointed = true
+				return nil
+			})
+		}
+	}()
+}
+
+// offerRecovery looks for a checkpoint of the game being started or loaded,
+// left behind by a session that did not quit cleanly, and asks whether to
+// pick up from it. The checkpoint is only removed once restored, so
+// quitting at the question keeps it for the next start.
+func offerRecovery(in io.Reader, out io.Writer, saveDir, gameName string) *checkpoint {
+	path := checkpointPath(saveDir, gameName)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var found checkpoint
+	if json.Unmarshal(data, &found) != nil || found.Game == nil || found.Game.GameName != gameName {
+		return nil
+	}
+	if found.Game.Log == nil {
+		found.Game.Log = &pandemic.EventLog{}
+	}
+	fmt.Fprintln(out, strings.Join(describeCheckpoint(&found), "\n"))
+	fmt.Fprint(out, "Restore it? [y/N] ")
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	if strings.ToLower(strings.TrimSpace(answer)) != "y" {
+		return nil
+	}
+	os.Remove(path)
+	return &found
+}
+
+func describeCheckpoint(c *checkpoint) []string {
+	game := c.Game
+	lines := []string{
+		fmt.Sprintf("The last session of %v did not quit cleanly. Its checkpoint from %v has:", game.GameName, c.Time.Format("Jan 2 15:04")),
+	}
+	if turn, err := game.GameTurns.CurrentTurn(); err == nil {
+		lines = append(lines, fmt.Sprintf("  turn %v, %v to play", game.GameTurns.CurTurn+1, turn.Player.HumanName))
+	}
+	lines = append(lines,
+		fmt.Sprintf("  %v outbreaks, infection rate %v", game.Outbreaks, game.InfectionRate),
+		fmt.Sprintf("  %v city cards drawn, %v cards in the infection discard", len(game.CityDeck.Drawn), game.InfectionDeck.DrawnCount()),
+	)
+	if len(c.Staged) > 0 {
+		lines = append(lines, fmt.Sprintf("  %v staged commands: %v", len(c.Staged), strings.Join(c.Staged, "; ")))
+	}
+	return lines
+}
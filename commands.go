@@ -2,7 +2,9 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -11,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Sirupsen/logrus"
 	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
 	"github.com/jroimartin/gocui"
 )
@@ -23,6 +26,21 @@ func getCardByPrefix(entry string, gs *pandemic.GameState) (pandemic.CardName, e
 	return card.Name(), nil
 }
 
+// parseFraction reads a rough fraction such as 1/3 or 0.25.
+func parseFraction(entry string) (float64, error) {
+	parts := strings.Split(entry, "/")
+	if len(parts) == 2 {
+		num, err := strconv.ParseFloat(parts[0], 64)
+		den, err2 := strconv.ParseFloat(parts[1], 64)
+		if err == nil && err2 == nil && den != 0 {
+			return num / den, nil
+		}
+	} else if f, err := strconv.ParseFloat(entry, 64); err == nil {
+		return f, nil
+	}
+	return 0.0, fmt.Errorf("%v is not a fraction like 1/3 or 0.25", entry)
+}
+
 func getCityByPrefix(entry string, gs *pandemic.GameState) (pandemic.CityName, error) {
 	card, err := gs.CityDeck.GetCardByPrefix(entry)
 	if err != nil {
@@ -34,6 +52,15 @@ func getCityByPrefix(entry string, gs *pandemic.GameState) (pandemic.CityName, e
 	return card.CityName, nil
 }
 
+func getDiseaseByPrefix(entry string, gs *pandemic.GameState) (pandemic.DiseaseType, error) {
+	for _, data := range gs.DiseaseData {
+		if strings.HasPrefix(strings.ToLower(string(data.Type)), strings.ToLower(entry)) {
+			return data.Type, nil
+		}
+	}
+	return "", fmt.Errorf("No disease matches %v", entry)
+}
+
 func getPlayerByPrefix(entry string, gs *pandemic.GameState) (*pandemic.Player, error) {
 	var ret *pandemic.Player
 	for _, player := range gs.GameTurns.PlayerOrder {
@@ -48,16 +75,107 @@ func getPlayerByPrefix(entry string, gs *pandemic.GameState) (*pandemic.Player,
 	return ret, nil
 }
 
-func (p *PandemicView) runCommand(gameState *pandemic.GameState, consoleView *gocui.View, commandView *gocui.View) error {
+// handOwner is the player named by the optional argument, or the current
+// player.
+func handOwner(args []string, curPlayer *pandemic.Player, gs *pandemic.GameState) (*pandemic.Player, error) {
+	if len(args) == 0 {
+		return curPlayer, nil
+	}
+	player, err := getPlayerByPrefix(args[0], gs)
+	if err != nil {
+		return nil, err
+	}
+	if player == nil {
+		return nil, fmt.Errorf("No player's name starts with %v", args[0])
+	}
+	return player, nil
+}
+
+// commandPhases lists the turn phases each command belongs to. Commands that
+// are not listed may be run at any time.
+var commandPhases = map[string][]pandemic.Phase{
+	"infect":    {pandemic.InfectPhase},
+	"i":         {pandemic.InfectPhase},
+	"city-draw": {pandemic.ActionPhase, pandemic.DrawPhase},
+	"c":         {pandemic.ActionPhase, pandemic.DrawPhase},
+	"draw":      {pandemic.ActionPhase, pandemic.DrawPhase},
+	"epidemic":  {pandemic.ActionPhase, pandemic.DrawPhase},
+	"e":         {pandemic.ActionPhase, pandemic.DrawPhase},
+	"next-turn": {pandemic.InfectPhase},
+	"n":         {pandemic.InfectPhase},
+	"action":    {pandemic.ActionPhase},
+	"a":         {pandemic.ActionPhase},
+	"treat":     {pandemic.ActionPhase},
+	"give":      {pandemic.ActionPhase},
+	"move":      {pandemic.ActionPhase},
+	"fly":       {pandemic.ActionPhase},
+	"charter":   {pandemic.ActionPhase},
+	"shuttle":   {pandemic.ActionPhase},
+}
+
+// pawnMoves are the commands that move a pawn for an action.
+var pawnMoves = map[string]pandemic.MoveKind{
+	"move":    pandemic.Drive,
+	"fly":     pandemic.DirectFlight,
+	"charter": pandemic.CharterFlight,
+	"shuttle": pandemic.ShuttleFlight,
+}
+
+// autoAdvanceCommands can be the last thing a turn needs, after which the
+// next player's turn starts without a next-turn.
+var autoAdvanceCommands = map[string]bool{
+	"infect":       true,
+	"i":            true,
+	"epidemic":     true,
+	"e":            true,
+	"city-draw":    true,
+	"c":            true,
+	"draw":         true,
+	"unknown-draw": true,
+	"discard":      true,
+	"d":            true,
+	"quiet-night":  true,
+	"play-event":   true,
+}
+
+func (p *PandemicView) checkPhase(cmd string, turn *pandemic.Turn) error {
+	phases, ok := commandPhases[cmd]
+	if !ok {
+		return nil
+	}
+	for _, phase := range phases {
+		if turn.Phase() == phase {
+			return nil
+		}
+	}
+	return errors.New(p.t("%v does not belong in the %v phase of %v's turn, use %v! to run it anyway", cmd, turn.Phase(), turn.Player.HumanName, cmd))
+}
+
+func (p *PandemicView) runCommand(gameState *pandemic.GameState, consoleView io.Writer, commandView *gocui.View) error {
 	commandBuffer := strings.Trim(commandView.Buffer(), "\n\t\r ")
 	if commandBuffer == "" {
 		return nil
 	}
 	defer commandView.SetCursor(commandView.Origin())
 	defer commandView.Clear()
+	p.lastCommand = time.Now()
+	return p.execute(gameState, consoleView, commandBuffer)
+}
 
-	commandArgs := strings.Split(commandBuffer, " ")
-	cmd := commandArgs[0]
+// execute runs a single command line, saving the game afterwards unless the
+// command only reads the game state.
+func (p *PandemicView) execute(gameState *pandemic.GameState, consoleView io.Writer, commandBuffer string) error {
+	commandArgs := p.config.locale.canonicalArgs(strings.Split(commandBuffer, " "), gameState)
+	cmd := strings.TrimSuffix(commandArgs[0], "!")
+	force := cmd != commandArgs[0]
+	// commit runs commands of its own, which set the level they need
+	defer func(verbosity string) { p.verbosity = verbosity }(p.verbosity)
+	commandArgs, p.verbosity = p.commandVerbosity(commandArgs)
+	// announcements after the command and save errors always get through
+	console := consoleView
+	if p.verbosity == terseOutput && !readOnlyCommands[cmd] && cmd != "commit" {
+		consoleView = &terseWriter{out: consoleView}
+	}
 
 	curTurn, err := gameState.GameTurns.CurrentTurn()
 	if err != nil {
@@ -65,10 +183,97 @@ func (p *PandemicView) runCommand(gameState *pandemic.GameState, consoleView *go
 	}
 	curPlayer := curTurn.Player
 
+	if err := p.checkLock(cmd, commandArgs[1:]); err != nil {
+		fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+		return nil
+	}
+	if err := gameState.CheckPlaying(); err != nil && !readOnlyCommands[cmd] && cmd != "end-game" {
+		fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("%v, only analysis commands and end-game run now", err)))
+		return nil
+	}
+	// a next-turn typed out of habit after the turn moved on by itself
+	// would otherwise skip the next player
+	advanced := p.autoAdvanced
+	if !readOnlyCommands[cmd] {
+		p.autoAdvanced = false
+	}
+	if (cmd == "next-turn" || cmd == "n") && advanced && !force {
+		fmt.Fprint(consoleView, p.t("Already moved on to %v's turn\n", curPlayer.HumanName))
+		return nil
+	}
+	if err := p.checkPhase(cmd, curTurn); err != nil && !force {
+		fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+		return nil
+	}
+	// the hand limit applies at once, so the turn can't go on until every
+	// hand is back down to it. The player still finishes drawing, e.g. an
+	// epidemic as their second card, and discards before infecting.
+	drawing := curTurn.Phase() == pandemic.DrawPhase && p.checkPhase(cmd, curTurn) == nil
+	if _, turnCommand := commandPhases[cmd]; turnCommand && !drawing && !force {
+		if err := gameState.CheckHandLimits(); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			return nil
+		}
+	}
+	if p.config.HotSeat {
+		if err := p.checkHotSeat(cmd, force, commandArgs, curTurn, gameState); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			return nil
+		}
+	}
+
 	switch cmd {
+	case "lock":
+		if err := p.lock(commandArgs[1:]); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			return nil
+		}
+		fmt.Fprintln(consoleView, p.t("Locked, only analysis commands will run until unlock"))
+		return nil
+	case "unlock":
+		if err := p.unlock(commandArgs[1:]); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			return nil
+		}
+		fmt.Fprintln(consoleView, p.t("Unlocked"))
+		return nil
+	case "stage":
+		if len(commandArgs) < 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("stage must be called with the command to run later")))
+			return nil
+		}
+		staged := strings.Join(commandArgs[1:], " ")
+		p.staged = append(p.staged, staged)
+		p.checkpointed = false
+		fmt.Fprint(consoleView, p.t("Staged %q, %v commands waiting for commit\n", staged, len(p.staged)))
+		return nil
+	case "staged":
+		if len(p.staged) == 0 {
+			fmt.Fprintln(consoleView, p.t("Nothing is staged"))
+		}
+		for i, staged := range p.staged {
+			fmt.Fprintf(consoleView, "%v. %v\n", i+1, staged)
+		}
+		return nil
+	case "commit":
+		staged := p.staged
+		p.staged = nil
+		p.checkpointed = false
+		for _, command := range staged {
+			fmt.Fprintf(consoleView, "> %v\n", command)
+			if err := p.execute(gameState, consoleView, command); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "unstage":
+		fmt.Fprint(consoleView, p.t("Discarded %v staged commands\n", len(p.staged)))
+		p.staged = nil
+		p.checkpointed = false
+		return nil
 	case "infect", "i":
 		if len(commandArgs) != 2 {
-			fmt.Fprintln(consoleView, p.colorWarning("You must pass a city to the infect command."))
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("You must pass a city to the infect command.")))
 			break
 		}
 		city, err := getCityByPrefix(commandArgs[1], gameState)
@@ -76,30 +281,41 @@ func (p *PandemicView) runCommand(gameState *pandemic.GameState, consoleView *go
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		}
-		err = gameState.Infect(city)
+		events := len(gameState.Log.Events)
+		chance := gameState.ProbabilityOfCity(city)
+		pending := p.predict(func(c *pandemic.Calibration) { gameState.RecordInfection(c, city) })
+		chain, err := gameState.Infect(city)
 		if err != nil {
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 		} else {
-			fmt.Fprintf(consoleView, "Infected %v\n", city)
+			p.keepPrediction(pending)
+			fmt.Fprint(consoleView, p.t("Infected %v\n", p.cityLabel(city)))
+			p.printOutbreaks(console, gameState, chain)
+			if data, err := gameState.GetCity(city); err == nil {
+				p.explain(consoleView, "The tracker gave it a %.1f%% chance this phase, it now has %v cubes", chance*100, data.NumInfections)
+			}
+			p.showDraw(gameState, "Infected", city, events)
+			if gameState.InfectionsLeft() < 0 {
+				fmt.Fprintln(consoleView, p.colorWarning("%v", gameState.CheckInfections()))
+			}
+			if gameState.InfectionsLeft() == 0 && p.config.BoardCheck {
+				p.printBoardCheck(console, gameState)
+			}
 		}
 	case "next-turn", "n":
+		if err := gameState.CheckInfections(); err != nil && !force {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("%v, use next-turn! to move on anyway", err)))
+			return nil
+		}
 		turn, err := gameState.NextTurn()
 		if err != nil {
-			fmt.Fprintln(consoleView, p.colorWarning("Could not move on to next turn: %v", err))
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not move on to next turn: %v", err)))
 		} else {
-			fmt.Fprintf(consoleView, "It is now %v's turn\n", turn.Player.HumanName)
-			message := []string{turn.Player.HumanName}
-			if turn.Player.Character != nil && turn.Player.Character.TurnMessage != "" {
-				message = append(message, strings.Split(turn.Player.Character.TurnMessage, " ")...)
-			}
-			err = exec.Command("say", message...).Run()
-			if err != nil {
-				fmt.Fprintln(consoleView, p.colorOhFuck("Could not say message out loud: %v", strings.Join(message, " ")))
-			}
+			p.announceTurn(gameState, consoleView, turn)
 		}
 	case "give-card", "g":
 		if len(commandArgs) != 3 {
-			fmt.Fprintln(consoleView, p.colorWarning("Usage: give-card <human-prefix> <city-prefix>"))
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: give-card <human-prefix> <city-prefix>")))
 			break
 		}
 		from, err := gameState.GameTurns.CurrentTurn()
@@ -107,7 +323,7 @@ func (p *PandemicView) runCommand(gameState *pandemic.GameState, consoleView *go
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		}
-		to, err := getPlayerByPrefix(commandArgs[1], gameState)
+		to, err := handOwner(commandArgs[1:2], curPlayer, gameState)
 		if err != nil {
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
@@ -117,16 +333,58 @@ func (p *PandemicView) runCommand(gameState *pandemic.GameState, consoleView *go
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		}
-		err = gameState.ExchangeCard(from.Player, to, cardName)
-		if err != nil {
+		err = gameState.ShareKnowledge(cardName, from.Player, to, force)
+		if _, unplaced := err.(*pandemic.SharingPositionError); unplaced {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("%v, or use %v! to give it anyway", err, cmd)))
+			break
+		} else if err != nil {
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		} else {
-			fmt.Fprintf(consoleView, "%v gave %v to %v\n", from.Player.HumanName, cardName, to.HumanName)
+			fmt.Fprint(consoleView, p.t("%v gave %v to %v\n", from.Player.HumanName, p.cityLabel(pandemic.CityName(cardName)), to.HumanName))
+			p.takeAction(gameState, consoleView, "share")
+		}
+	case "setup":
+		if err := p.setup(consoleView, gameState, commandArgs[1:]); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			return nil
+		}
+	case "give":
+		if len(commandArgs) != 4 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: give <city-prefix> <from-human-prefix> <to-human-prefix>")))
+			break
+		}
+		cardName, err := getCardByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		from, err := handOwner(commandArgs[2:3], curPlayer, gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		to, err := handOwner(commandArgs[3:], curPlayer, gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		if err := gameState.ShareKnowledge(cardName, from, to, force); err != nil {
+			if _, unplaced := err.(*pandemic.SharingPositionError); unplaced {
+				fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("%v, or use give! to give it anyway", err)))
+			} else {
+				fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			}
+			break
+		}
+		fmt.Fprint(consoleView, p.t("%v gave %v to %v\n", from.HumanName, p.cityLabel(pandemic.CityName(cardName)), to.HumanName))
+		if err := gameState.CheckHandLimits(); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 		}
+		p.takeAction(gameState, consoleView, "share")
 	case "epidemic", "e":
 		if len(commandArgs) != 2 {
-			fmt.Fprintln(consoleView, p.colorWarning("You must pass a city to the epidemic command."))
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("You must pass a city to the epidemic command.")))
 			break
 		}
 		city, err := getCityByPrefix(commandArgs[1], gameState)
@@ -134,33 +392,56 @@ func (p *PandemicView) runCommand(gameState *pandemic.GameState, consoleView *go
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		}
-		err = gameState.Epidemic(city)
+		events := len(gameState.Log.Events)
+		pending := p.predict(func(c *pandemic.Calibration) { gameState.RecordCityDraw(c, true) })
+		if impossible, ok := gameState.CityDeck.CanDrawEpidemic().(*pandemic.ImpossibleEpidemicError); ok {
+			fmt.Fprintln(consoleView, p.colorOhFuck("%v", impossible))
+			printAudit(consoleView, p.t, gameState)
+			if !force {
+				fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Enter the missing draws, or use epidemic! to enter the epidemic anyway")))
+				return nil
+			}
+		}
+		result, err := gameState.Epidemic(city, force)
 		if err != nil {
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		} else {
-			fmt.Fprintf(consoleView, "Epidemic in %v. Please update the infect rate (infect-rate N)\n", city)
+			p.keepPrediction(pending)
+			// the double epidemic warning comes first so terse output keeps it
+			if gameState.EpidemicsThisTurn() > 1 {
+				fmt.Fprintln(consoleView, p.colorOhFuck("%v", p.t("Double epidemic! The infect rate goes up for both, and %v is alone on top of the infection deck, so it is the first card infected", p.cityLabel(city))))
+			}
+			fmt.Fprint(consoleView, p.t("Epidemic in %v. The infect rate is now %v\n", p.cityLabel(city), gameState.InfectionRate))
+			p.printEpidemic(consoleView, result)
+			p.printOutbreaks(console, gameState, result.Chain)
+			p.explain(consoleView, "The %v cards in the discard pile were shuffled back on top of the infection deck and will be drawn before any others", result.Intensified)
+			p.showDraw(gameState, "Epidemic", city, events)
+			if p.config.BoardCheck {
+				p.printBoardCheck(console, gameState)
+			}
 		}
 	case "infect-rate", "r":
 		if len(commandArgs) != 2 {
-			fmt.Fprintln(consoleView, p.colorWarning("You must pass an integer value to the infect rate\n"))
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("You must pass an integer value to the infect rate\n")))
 			break
 		}
 		ir, err := strconv.ParseInt(commandArgs[1], 10, 32)
 		if err != nil {
-			fmt.Fprintf(consoleView, p.colorWarning(fmt.Sprintf("%v is not a valid infection rate\n", commandArgs[1])))
+			fmt.Fprint(consoleView, p.colorWarning("%v", p.t("%v is not a valid infection rate\n", commandArgs[1])))
+		} else if err := gameState.SetInfectionRate(int(ir)); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 		} else {
-			fmt.Fprintf(consoleView, "infection rate now %v\n", ir)
-			gameState.InfectionRate = int(ir)
+			fmt.Fprint(consoleView, p.t("infection rate now %v\n", ir))
 		}
 	case "city-infect-level", "l":
 		if len(commandArgs) != 3 {
-			fmt.Fprintln(consoleView, p.colorWarning("You must pass a city and infection value"))
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("You must pass a city and infection value")))
 			break
 		}
 		il, err := strconv.ParseInt(commandArgs[2], 10, 32)
 		if err != nil {
-			fmt.Fprintf(consoleView, p.colorWarning(fmt.Sprintf("%v is not a valid infection level\n", commandArgs[1])))
+			fmt.Fprint(consoleView, p.colorWarning("%v", p.t("%v is not a valid infection level\n", commandArgs[1])))
 			break
 		}
 		cityName, err := getCityByPrefix(commandArgs[1], gameState)
@@ -168,16 +449,14 @@ func (p *PandemicView) runCommand(gameState *pandemic.GameState, consoleView *go
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		}
-		city, err := gameState.GetCity(cityName)
-		if err != nil {
-			fmt.Fprintf(consoleView, p.colorWarning(fmt.Sprintf("Could not get city %v: %v\n", cityName, err)))
+		if err := gameState.SetInfections(cityName, int(il)); err != nil {
+			fmt.Fprint(consoleView, p.colorWarning("%v", p.t("Could not set the infection level in %v: %v\n", cityName, err)))
 			break
 		}
-		city.SetInfections(int(il))
-		fmt.Fprintf(consoleView, "Set infection level in %v to %v\n", city.Name, city.NumInfections)
-	case "city-draw", "c":
-		if len(commandArgs) != 2 {
-			fmt.Fprintln(consoleView, p.colorWarning("You must pass a city or funded event name to draw\n"))
+		fmt.Fprint(consoleView, p.t("Set infection level in %v to %v\n", cityName, il))
+	case "city-draw", "c", "draw":
+		if len(commandArgs) != 2 && len(commandArgs) != 3 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: city-draw <city or funded event> [human-prefix]")))
 			break
 		}
 		cardName, err := getCardByPrefix(commandArgs[1], gameState)
@@ -185,15 +464,59 @@ func (p *PandemicView) runCommand(gameState *pandemic.GameState, consoleView *go
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		}
-		err = gameState.DrawCard(cardName)
+		player, err := p.drawOwner(commandArgs[2:], curTurn, gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		pending := p.predict(func(c *pandemic.Calibration) { gameState.RecordCityDraw(c, false) })
+		err = gameState.DrawCardTo(cardName, player)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		p.keepPrediction(pending)
+		fmt.Fprint(consoleView, p.t("%v drew %v from city deck\n", player.HumanName, p.cityLabel(pandemic.CityName(cardName))))
+		if err := gameState.CheckHandLimits(); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+		}
+		p.explain(consoleView, "The next city card has a %.1f%% chance of being an epidemic", gameState.CityDeck.EpidemicAnalysis().FirstCardProbability*100)
+	case "unknown-draw":
+		player, err := p.drawOwner(commandArgs[1:], curTurn, gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		err = gameState.DrawUnknown(player)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		fmt.Fprint(consoleView, p.t("%v drew a card nobody saw, %v unknown cards in hand\n", player.HumanName, player.UnknownCards))
+	case "reveal":
+		if len(commandArgs) != 3 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: reveal <human-prefix> <card-prefix>")))
+			break
+		}
+		player, err := handOwner(commandArgs[1:2], curPlayer, gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		cardName, err := getCardByPrefix(commandArgs[2], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		err = gameState.RevealCard(player, cardName)
 		if err != nil {
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		}
-		fmt.Fprintf(consoleView, "%v drew %v from city deck\n", curPlayer.HumanName, cardName)
+		fmt.Fprint(consoleView, p.t("%v's unknown card was %v\n", player.HumanName, cardName))
 	case "quarantine", "q":
 		if len(commandArgs) != 2 {
-			fmt.Fprintln(consoleView, p.colorWarning("quarantine must be called with a city name"))
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("quarantine must be called with a city name")))
 			break
 		}
 		cityName, err := getCityByPrefix(commandArgs[1], gameState)
@@ -203,61 +526,1123 @@ func (p *PandemicView) runCommand(gameState *pandemic.GameState, consoleView *go
 		}
 		err = gameState.Quarantine(cityName)
 		if err != nil {
-			fmt.Fprintln(consoleView, p.colorWarning(fmt.Sprintf("Could not quarantine %v: %v", cityName, err)))
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not quarantine %v: %v", cityName, err)))
 		} else {
-			fmt.Fprintf(consoleView, "Quarantined %v\n", cityName)
+			fmt.Fprint(consoleView, p.t("Quarantined %v, %v quarantine markers left\n", cityName, gameState.QuarantineMarkersLeft()))
 		}
-	case "discard", "d":
-		if len(commandArgs) != 2 {
-			fmt.Fprintln(consoleView, p.colorWarning("discard must be called with a city name"))
+	case "shuffle":
+		usage := "Usage: shuffle <striation> weak|fair|cut <fraction>"
+		if len(commandArgs) < 3 {
+			fmt.Fprintln(consoleView, p.colorWarning(usage))
 			break
 		}
-		cardName, err := getCardByPrefix(commandArgs[1], gameState)
+		striation, err := strconv.Atoi(commandArgs[1])
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("%v is not a striation number", commandArgs[1])))
+			break
+		}
+		how := commandArgs[2]
+		switch {
+		case how == "weak" && len(commandArgs) == 3:
+			err = gameState.MarkWeaklyShuffled(striation)
+		case how == "fair" && len(commandArgs) == 3:
+			err = gameState.MarkFairlyShuffled(striation)
+		case how == "cut" && len(commandArgs) == 4:
+			var fraction float64
+			fraction, err = parseFraction(commandArgs[3])
+			if err == nil {
+				err = gameState.MarkCut(striation, fraction)
+			}
+		default:
+			fmt.Fprintln(consoleView, p.colorWarning(usage))
+			return nil
+		}
 		if err != nil {
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		}
-		err = curPlayer.Discard(cardName)
+		if how == "cut" {
+			fmt.Fprint(consoleView, p.t("Infection %v was shuffled weakly and cut %v from the top\n", striation, commandArgs[3]))
+			break
+		}
+		fmt.Fprint(consoleView, p.t("Infection %v was shuffled %vly\n", striation, how))
+	case "forecast":
+		usage := "Usage: forecast <top city> <next city>... [/ <cities put back in no known order>...]"
+		seen := []pandemic.CityName{}
+		ordered := -1
+		for _, arg := range commandArgs[1:] {
+			if arg == "/" {
+				ordered = len(seen)
+				continue
+			}
+			city, err := getCityByPrefix(arg, gameState)
+			if err != nil {
+				fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+				return nil
+			}
+			seen = append(seen, city)
+		}
+		if len(seen) == 0 {
+			if len(commandArgs) > 1 {
+				fmt.Fprintln(consoleView, p.colorWarning(usage))
+				return nil
+			}
+			p.printForecastPreview(consoleView, gameState)
+			return nil
+		}
+		if ordered == -1 {
+			ordered = len(seen)
+		}
+		if err := gameState.Forecast(seen, ordered); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			return nil
+		}
+		fmt.Fprint(consoleView, p.t("Forecast put back %v cards, %v in a known order\n", len(seen), ordered))
+		p.explain(consoleView, "The infection odds now use the places the forecast put the cards in")
+	case "supply":
+		if len(commandArgs) != 3 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("supply must be called with a city name and a number of cubes")))
+			break
+		}
+		cityName, err := getCityByPrefix(commandArgs[1], gameState)
 		if err != nil {
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		}
-		fmt.Fprintf(consoleView, "%v discarded %v\n", curPlayer.HumanName, cardName)
-	case "remove-quarantine", "rq":
+		cubes, err := strconv.Atoi(commandArgs[2])
+		if err != nil || cubes < 1 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("%v is not a number of cubes", commandArgs[2])))
+			break
+		}
+		err = gameState.PlaceSupplies(cityName, cubes)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not supply %v: %v", cityName, err)))
+		} else {
+			fmt.Fprint(consoleView, p.t("Placed %v supply cubes in %v, %v left\n", cubes, cityName, gameState.Supply.SupplyCubes))
+		}
+	case "explore":
+		if len(commandArgs) != 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("explore must be called with a city name")))
+			break
+		}
+		// unexplored cities have no cards, so they are looked up on the map
+		city, err := gameState.Cities.GetCityByPrefix(commandArgs[1])
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		if err := gameState.Explore(city.Name); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not explore %v: %v", city.Name, err)))
+		} else {
+			fmt.Fprint(consoleView, p.t("Explored %v, its cards join the decks next game\n", city.Name))
+		}
+	case "haven":
 		if len(commandArgs) != 2 {
-			fmt.Fprintf(consoleView, p.colorWarning("remove-quarantine must be called with a city name"))
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("haven must be called with a city name")))
+			break
 		}
 		cityName, err := getCityByPrefix(commandArgs[1], gameState)
 		if err != nil {
 			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
 			break
 		}
-		err = gameState.RemoveQuarantine(cityName)
+		err = gameState.BuildHaven(cityName)
 		if err != nil {
-			fmt.Fprintln(consoleView, p.colorWarning(fmt.Sprintf("Could not remove quarantine from %v: %v", cityName, err)))
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not build a haven in %v: %v", cityName, err)))
 		} else {
-			fmt.Fprintf(consoleView, "Removed quarantine from %v\n", cityName)
+			fmt.Fprint(consoleView, p.t("Built a haven in %v\n", cityName))
 		}
-	default:
-		fmt.Fprintf(consoleView, p.colorWarning(fmt.Sprintf("Unrecognized command %v\n", cmd)))
-		return nil
-	}
-
-	filename := filepath.Join(gameState.GameName, fmt.Sprintf("game_%v_%v.json", time.Now().UnixNano(), cmd))
-	err = os.MkdirAll(gameState.GameName, 0755)
-	if err != nil {
-		fmt.Fprintf(consoleView, p.colorOhFuck(fmt.Sprintf("Could not create a game name folder: %v", err)))
-	}
-	data, err := json.Marshal(gameState)
-	if err != nil {
-		fmt.Fprintf(consoleView, p.colorOhFuck(fmt.Sprintf("Could not marshal gamestate as JSON: %v\n", err)))
-		return nil
-	}
-	err = ioutil.WriteFile(filename, data, 0644)
-	if err != nil {
-		fmt.Fprintf(consoleView, p.colorOhFuck(fmt.Sprintf("Could not save gamestate: %v\n", err)))
-		return nil
-	}
-
-	return nil
+	case "purify":
+		if len(commandArgs) != 3 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("purify must be called with a region and a number of tokens")))
+			break
+		}
+		region, err := gameState.GetRegionByPrefix(commandArgs[1])
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		tokens, err := strconv.Atoi(commandArgs[2])
+		if err != nil || tokens < 1 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("%v is not a number of tokens", commandArgs[2])))
+			break
+		}
+		err = gameState.Purify(region, tokens)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not purify %v: %v", region.Name, err)))
+		} else {
+			fmt.Fprint(consoleView, p.t("%v now has %v purification tokens\n", region.Name, region.PurificationTokens))
+		}
+	case "railroad":
+		if len(commandArgs) != 3 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("railroad must be called with two neighboring cities")))
+			break
+		}
+		from, err := getCityByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		to, err := getCityByPrefix(commandArgs[2], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		err = gameState.BuildRailroad(from, to)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not build a railroad: %v", err)))
+		} else {
+			fmt.Fprint(consoleView, p.t("Built a railroad from %v to %v\n", from, to))
+		}
+	case "cure":
+		if len(commandArgs) != 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("cure must be called with a disease")))
+			break
+		}
+		disease, err := getDiseaseByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		err = gameState.Cure(disease)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not cure %v: %v", disease, err)))
+		} else {
+			fmt.Fprint(consoleView, p.t("Cured %v\n", disease))
+			p.takeAction(gameState, consoleView, "cure")
+		}
+	case "treat":
+		if len(commandArgs) < 2 || len(commandArgs) > 4 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: treat <city-prefix> [times] [disease]")))
+			break
+		}
+		city, err := getCityByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		data, _ := gameState.GetCity(city)
+		times, disease := 1, data.Disease
+		if len(commandArgs) >= 3 {
+			if times, err = strconv.Atoi(commandArgs[2]); err != nil {
+				fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("%v is not a number", commandArgs[2])))
+				break
+			}
+		}
+		if len(commandArgs) == 4 {
+			if disease, err = getDiseaseByPrefix(commandArgs[3], gameState); err != nil {
+				fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+				break
+			}
+		}
+		removed, actions, err := gameState.TreatDisease(city, disease, times, curPlayer)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not treat %v: %v", city, err)))
+			break
+		}
+		fmt.Fprint(consoleView, p.t("Treated %v cubes in %v, %v left\n", removed, p.cityLabel(city), data.Cubes(disease)))
+		for i := 0; i < actions; i++ {
+			p.takeAction(gameState, consoleView, "treat")
+		}
+	case "move", "fly", "charter", "shuttle":
+		if len(commandArgs) != 2 && len(commandArgs) != 3 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: %v <city-prefix> [player]", cmd)))
+			break
+		}
+		city, err := getCityByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		player, err := handOwner(commandArgs[2:], curPlayer, gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		logged := len(gameState.Log.Events)
+		move, err := gameState.MovePawn(player, pawnMoves[cmd], city)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		// the pawn arriving can set off its role, e.g. the Medic treating
+		for _, event := range gameState.Log.Events[logged:] {
+			fmt.Fprintln(consoleView, event.Message)
+		}
+		if !move.Card.Empty() {
+			p.explain(consoleView, "%v discarded %v", player.HumanName, move.Card)
+		}
+		p.takeAction(gameState, consoleView, cmd)
+	case "eradicate":
+		if len(commandArgs) != 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("eradicate must be called with a disease")))
+			break
+		}
+		disease, err := getDiseaseByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		if err := gameState.Eradicate(disease); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not eradicate %v: %v", disease, err)))
+			break
+		}
+		fmt.Fprint(consoleView, p.t("Eradicated %v, its infection cards no longer place cubes\n", disease))
+	case "discard", "d":
+		if len(commandArgs) != 2 && len(commandArgs) != 3 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: discard <card> [human-prefix]")))
+			break
+		}
+		cardName, err := getCardByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		player, err := handOwner(commandArgs[2:], curPlayer, gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		err = gameState.Discard(player, cardName)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		fmt.Fprint(consoleView, p.t("%v discarded %v, %v cards in hand\n", player.HumanName, cardName, player.HandSize()))
+	case "remove-card":
+		if len(commandArgs) != 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("remove-card must be called with a card name")))
+			break
+		}
+		cardName, err := getCardByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		err = gameState.RemoveCard(curPlayer, cardName)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		fmt.Fprint(consoleView, p.t("%v removed %v from the game\n", curPlayer.HumanName, cardName))
+	case "remove-quarantine", "rq":
+		if len(commandArgs) != 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("remove-quarantine must be called with a city name")))
+			break
+		}
+		cityName, err := getCityByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		err = gameState.RemoveQuarantine(cityName)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not remove quarantine from %v: %v", cityName, err)))
+		} else {
+			fmt.Fprint(consoleView, p.t("Removed quarantine from %v\n", cityName))
+		}
+	case "station":
+		if len(commandArgs) != 2 && len(commandArgs) != 3 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: station <city> [human-prefix]")))
+			break
+		}
+		cityName, err := getCityByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		player, err := handOwner(commandArgs[2:], curPlayer, gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		cost, err := gameState.StationCost(player, cityName)
+		if err != nil && !force {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not build a research station in %v: %v, use station! to build it without discarding", cityName, err)))
+			break
+		}
+		if force {
+			player, cost = nil, ""
+		}
+		if err := gameState.BuildStation(player, cityName); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not build a research station in %v: %v", cityName, err)))
+			break
+		}
+		if !cost.Empty() {
+			fmt.Fprint(consoleView, p.t("%v discarded %v\n", player.HumanName, p.cityLabel(pandemic.CityName(cost))))
+		}
+		fmt.Fprint(consoleView, p.t("Built a research station in %v, %v left\n", cityName, gameState.ResearchStationsLeft()))
+		p.takeAction(gameState, consoleView, "build")
+	case "move-station":
+		if len(commandArgs) != 3 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: move-station <from> <to>")))
+			break
+		}
+		from, err := getCityByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		to, err := getCityByPrefix(commandArgs[2], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		if err := gameState.MoveStation(from, to); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not move the research station: %v", err)))
+			break
+		}
+		fmt.Fprint(consoleView, p.t("Moved the research station in %v to %v\n", from, to))
+		p.takeAction(gameState, consoleView, "build")
+	case "remove-infection":
+		if len(commandArgs) != 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("remove-infection must be called with a city name")))
+			break
+		}
+		cityName, err := getCityByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		if err := gameState.RemoveInfectionCard(cityName); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not remove %v's infection card: %v", cityName, err)))
+			break
+		}
+		fmt.Fprint(consoleView, p.t("Removed %v's infection card from the game\n", cityName))
+		p.explain(consoleView, "It can't be drawn again this game, so its odds are now zero")
+	case "log-level":
+		if len(commandArgs) != 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: log-level <debug|info|warning|error>")))
+			return nil
+		}
+		level, err := logrus.ParseLevel(commandArgs[1])
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			return nil
+		}
+		// the logger has to be at least as verbose for the entries to reach the viewer
+		p.logLevel = level
+		p.logger.Level = level
+		fmt.Fprint(consoleView, p.t("Showing %v logs and above\n", level))
+		return nil
+	case "store-event":
+		if len(commandArgs) != 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: store-event <event-prefix>")))
+			break
+		}
+		cardName, err := getCardByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		err = gameState.StoreEvent(curPlayer, cardName)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		fmt.Fprint(consoleView, p.t("%v stored %v\n", curPlayer.HumanName, cardName))
+	case "play-event":
+		if len(commandArgs) != 2 && len(commandArgs) != 3 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: play-event <event-prefix> [human-prefix]")))
+			break
+		}
+		cardName, err := getCardByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		player, err := handOwner(commandArgs[2:], curPlayer, gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		err = gameState.PlayEvent(player, cardName)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		fmt.Fprint(consoleView, p.t("%v played %v\n", player.HumanName, cardName))
+		if cardName == pandemic.CardName(pandemic.OneQuietNightEvent) {
+			p.explain(consoleView, "This turn's infection step is skipped, so this turn's odds are now zero")
+			if p.config.BoardCheck {
+				p.printBoardCheck(console, gameState)
+			}
+		}
+	case "action", "a":
+		if len(commandArgs) > 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: action [move|treat|...]")))
+			break
+		}
+		action := "action"
+		if len(commandArgs) == 2 {
+			action = commandArgs[1]
+		}
+		p.takeAction(gameState, consoleView, action)
+	case "end-game":
+		if err := gameState.EndGame(force); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("%v, use end-game! to concede", err)))
+			return nil
+		}
+		fmt.Fprint(consoleView, p.t("Game over: %v\n", gameState.Ended))
+		if p.sandbox {
+			break
+		}
+		if path, err := p.archiveGame(gameState); err != nil {
+			fmt.Fprintln(console, p.colorOhFuck("%v", err))
+		} else {
+			fmt.Fprint(consoleView, p.t("Archived the final save to %v, record the month with transition\n", path))
+		}
+	case "win":
+		for _, status := range gameState.WinProgress() {
+			if status.Met() {
+				fmt.Fprintln(consoleView, p.colorAllGood("%v", status))
+			} else {
+				fmt.Fprintln(consoleView, status)
+			}
+		}
+		return nil
+	case "mark":
+		if len(commandArgs) < 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: mark <win-condition>")))
+			return nil
+		}
+		if err := gameState.Mark(strings.Join(commandArgs[1:], " ")); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			return nil
+		}
+		fmt.Fprint(consoleView, p.t("Manual override: %v\n", gameState.Log.Events[len(gameState.Log.Events)-1].Message))
+	case "quiet-night":
+		if err := gameState.SkipInfectionStep(); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		fmt.Fprintln(consoleView, p.t("Skipping this turn's infection step"))
+		if p.config.BoardCheck {
+			p.printBoardCheck(console, gameState)
+		}
+	case "route":
+		if len(commandArgs) != 2 && len(commandArgs) != 3 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: route <to-city-prefix> [from-city-prefix]")))
+			return nil
+		}
+		to, err := getCityByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			return nil
+		}
+		from := curPlayer.Location
+		if len(commandArgs) == 3 {
+			from, err = getCityByPrefix(commandArgs[2], gameState)
+			if err != nil {
+				fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+				return nil
+			}
+		}
+		fastest, cheapest, err := gameState.PlanRoutes(curPlayer, from, to)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			return nil
+		}
+		printFlightRestrictions(consoleView, p.t, gameState)
+		fmt.Fprint(consoleView, p.t("Fastest: %v\n  %v\n", fastest, gameState.Cost(curPlayer, fastest)))
+		if cheapest.Actions != fastest.Actions || len(cheapest.Cards) != len(fastest.Cards) {
+			fmt.Fprint(consoleView, p.t("Cheapest: %v\n  %v\n", cheapest, gameState.Cost(curPlayer, cheapest)))
+		}
+		if unplanned := fastest.Unplanned; len(unplanned) > 0 {
+			names := []string{}
+			for _, card := range unplanned {
+				names = append(names, string(card))
+			}
+			fmt.Fprint(consoleView, p.t("+%v more cards not planned with: %v\n", len(unplanned), strings.Join(names, ", ")))
+		}
+		return nil
+	case "set":
+		logged := len(gameState.Log.Events)
+		err := p.runSetCommand(gameState, commandArgs[1:])
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			return nil
+		}
+		// a pawn set down can set off its role, e.g. the Medic treating
+		for i, event := range gameState.Log.Events[logged:] {
+			if i == 0 {
+				fmt.Fprint(consoleView, p.t("Manual override: %v\n", event.Message))
+			} else {
+				fmt.Fprintln(consoleView, event.Message)
+			}
+		}
+	case "worstcase":
+		printWorstCase(consoleView, p.t, gameState)
+		return nil
+	case "telemetry":
+		if err := p.telemetry(consoleView, commandArgs[1:]); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+		}
+		return nil
+	case "audit":
+		printAudit(consoleView, p.t, gameState)
+		return nil
+	case "rules":
+		if err := p.lookUpRules(consoleView, gameState, commandArgs[1:]); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+		}
+		return nil
+	case "rewind":
+		if err := p.openRewind(gameState); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+		}
+		return nil
+	case "verify":
+		if err := printVerify(consoleView, p.t, gameState); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+		}
+		return nil
+	case "events":
+		printEventLedger(consoleView, p.t, gameState)
+		return nil
+	case "dump":
+		if len(commandArgs) > 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: dump [file]")))
+			return nil
+		}
+		if len(commandArgs) == 1 {
+			if err := gameState.Dump(consoleView); err != nil {
+				fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not dump the game: %v", err)))
+			}
+			return nil
+		}
+		file, err := os.Create(commandArgs[1])
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			return nil
+		}
+		defer file.Close()
+		if err := gameState.Dump(file); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not dump the game: %v", err)))
+			return nil
+		}
+		fmt.Fprint(consoleView, p.t("Dumped the game to %v\n", commandArgs[1]))
+		return nil
+	case "whatif":
+		usage := "Usage: whatif resilient <city-prefix> | whatif quietnight"
+		if len(commandArgs) < 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t(usage)))
+			return nil
+		}
+		var err error
+		var event pandemic.FundedEventName
+		var city pandemic.CityName
+		switch {
+		case commandArgs[1] == "resilient" && len(commandArgs) == 3:
+			event = pandemic.ResilientPopulationEvent
+			city, err = getCityByPrefix(commandArgs[2], gameState)
+		case commandArgs[1] == "quietnight" && len(commandArgs) == 2:
+			event = pandemic.OneQuietNightEvent
+		default:
+			err = errors.New(p.t(usage))
+		}
+		if err == nil {
+			err = printWhatIf(consoleView, p.t, gameState, event, city, p.config.risk().SortThreatsByProbability)
+		}
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+		}
+		return nil
+	case "eventodds":
+		if len(commandArgs) > 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: eventodds [event-prefix]")))
+			return nil
+		}
+		prefix := ""
+		if len(commandArgs) == 2 {
+			prefix = commandArgs[1]
+		}
+		if err := printEventOdds(consoleView, p.t, gameState, prefix); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+		}
+		return nil
+	case "phases":
+		phases := defaultForecastPhases
+		if len(commandArgs) > 1 {
+			var err error
+			if phases, err = strconv.Atoi(commandArgs[1]); err != nil || phases <= 0 {
+				fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: phases [count]")))
+				return nil
+			}
+		}
+		printPhases(consoleView, p.t, gameState, phases)
+		return nil
+	case "simulate":
+		if err := p.simulate(consoleView, gameState, commandArgs[1:]); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+		}
+		return nil
+	case "advise":
+		if err := printAdvice(consoleView, p.t, gameState, p.config.risk(), commandArgs[1:]); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+		}
+		return nil
+	case "discardadvice":
+		player, err := handOwner(commandArgs[1:], curPlayer, gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			return nil
+		}
+		printDiscardAdvice(consoleView, p.t, gameState, player)
+		return nil
+	case "export":
+		if err := p.export(gameState, commandArgs[1:]); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			return nil
+		}
+		fmt.Fprint(consoleView, p.t("Exported the %v to %v\n", commandArgs[1], commandArgs[2]))
+		return nil
+	case "import":
+		if len(commandArgs) != 3 || (commandArgs[1] != "deck" && commandArgs[1] != "bundle") {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: import deck <file> | import bundle <file>")))
+			break
+		}
+		if commandArgs[1] == "bundle" {
+			if p.sandbox {
+				fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("A bundle replaces the campaign, which a puzzle can't change")))
+				return nil
+			}
+			if !force {
+				fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Importing %v replaces this campaign with the bundled one, use import! bundle %v to go ahead", commandArgs[2], commandArgs[2])))
+				return nil
+			}
+			game, backup, err := p.importBundle(commandArgs[2])
+			if err != nil {
+				fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not import %v: %v", commandArgs[2], err)))
+				return nil
+			}
+			if backup != "" {
+				fmt.Fprint(consoleView, p.t("The old campaign file is kept at %v\n", backup))
+			}
+			fmt.Fprint(consoleView, p.t("Imported %v and the campaign from %v, load it to carry on from turn %v\n", game.GameName, commandArgs[2], game.GameTurns.CurTurn+1))
+			return nil
+		}
+		file, err := os.Open(commandArgs[2])
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		deck, err := pandemic.ReadInfectionDeckYAML(file)
+		file.Close()
+		if err == nil {
+			err = gameState.ImportInfectionDeck(deck)
+		}
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not import %v: %v", commandArgs[2], err)))
+			break
+		}
+		fmt.Fprint(consoleView, p.t("Imported the infection deck from %v\n", commandArgs[2]))
+	case "nickname":
+		if len(commandArgs) < 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: nickname <city-prefix> [nickname], leave out the nickname to remove it")))
+			break
+		}
+		if p.sandbox {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Nicknames belong to the campaign, which a puzzle can't change")))
+			break
+		}
+		cityName, err := getCityByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			break
+		}
+		p.campaign.SetNickname(cityName, strings.Join(commandArgs[2:], " "))
+		err = p.campaign.Save()
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorOhFuck("%v", p.t("Could not save campaign: %v", err)))
+			break
+		}
+		fmt.Fprint(consoleView, p.t("%v is now shown as %v\n", cityName, p.cityLabel(cityName)))
+		return nil
+	case "citystats":
+		if len(commandArgs) != 2 {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: citystats <city>")))
+			return nil
+		}
+		cityName, err := getCityByPrefix(commandArgs[1], gameState)
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+			return nil
+		}
+		records := pandemic.CityRecords(campaignGames(p.config.SaveDir, gameState))
+		printCityStats(consoleView, p.t, records[cityName], p.cityLabel(cityName))
+		return nil
+	case "result":
+		if len(commandArgs) != 2 || (commandArgs[1] != "won" && commandArgs[1] != "lost") {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Usage: result <won|lost>")))
+			break
+		}
+		if p.sandbox {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("A puzzle has no place in the campaign, its result isn't recorded")))
+			break
+		}
+		result := p.campaign.RecordResult(gameState, commandArgs[1] == "won")
+		err = p.campaign.Save()
+		if err != nil {
+			fmt.Fprintln(consoleView, p.colorOhFuck("%v", p.t("Could not save campaign: %v", err)))
+			break
+		}
+		fmt.Fprint(consoleView, p.t("Recorded %v attempt %v as %v\n", result.Month, result.Attempt, commandArgs[1]))
+		if p.campaign.Complete() {
+			printFinalScore(consoleView, p.t, p.campaign)
+			printCityRecords(consoleView, p.t, pandemic.CityRecords(campaignGames(p.config.SaveDir, gameState)))
+		}
+	default:
+		fmt.Fprint(consoleView, p.colorWarning("%v", p.t("Unrecognized command %v\n", cmd)))
+		return nil
+	}
+	if p.config.AutoAdvance && autoAdvanceCommands[cmd] {
+		if turn, err := gameState.AdvanceTurn(); err != nil {
+			fmt.Fprintln(consoleView, p.colorWarning("%v", p.t("Could not move on to next turn: %v", err)))
+		} else if turn != nil {
+			p.announceTurn(gameState, console, turn)
+			p.autoAdvanced = true
+		}
+	}
+	p.announceEradication(gameState, console)
+	p.announceGuaranteedLoss(gameState, console)
+	p.announceLoss(gameState, console)
+	p.announceWin(gameState, console)
+	if p.sandbox {
+		return nil
+	}
+
+	if err := p.saveGame(gameState, cmd); err != nil {
+		fmt.Fprint(console, p.colorOhFuck("%v\n", err))
+	}
+	return nil
+}
+
+// export writes the infection deck or the event log to a file. Both carry
+// the turn and time they were taken at, and the event log can be cut down
+// to a range of turns, e.g. to share a disputed stretch of the game.
+func (p *PandemicView) export(gameState *pandemic.GameState, args []string) error {
+	usage := errors.New(p.t("Usage: export deck <file> | export events <file> [turns, e.g. 10-20] | export bundle <file>"))
+	if len(args) < 2 || (args[0] != "events" && len(args) != 2) || len(args) > 3 {
+		return usage
+	}
+	turns := pandemic.TurnRange{}
+	if len(args) == 3 {
+		var err error
+		if turns, err = pandemic.ParseTurnRange(args[2]); err != nil {
+			return err
+		}
+	}
+	now := time.Now()
+	var write func(io.Writer) error
+	switch args[0] {
+	case "deck":
+		write = func(out io.Writer) error {
+			fmt.Fprintf(out, "# turn %v, %v, %v into the game\n", gameState.GameTurns.CurTurn+1, now.Format(time.RFC3339), gameState.GameClock(now))
+			if err := gameState.InfectionDeck.WriteYAML(out); err != nil {
+				return errors.New(p.t("Could not export the infection deck: %v", err))
+			}
+			return nil
+		}
+	case "events":
+		write = func(out io.Writer) error {
+			if err := gameState.WriteEventsCSV(out, turns); err != nil {
+				return errors.New(p.t("Could not export the event log: %v", err))
+			}
+			return nil
+		}
+	case "bundle":
+		write = func(out io.Writer) error {
+			if err := p.writeBundle(out, gameState); err != nil {
+				return errors.New(p.t("Could not export the bundle: %v", err))
+			}
+			return nil
+		}
+	default:
+		return usage
+	}
+
+	// write beside the target and rename over it, so a failed export
+	// leaves any earlier file in place
+	file, err := ioutil.TempFile(filepath.Dir(args[1]), filepath.Base(args[1]))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if err := file.Chmod(0644); err != nil {
+		file.Close()
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), args[1])
+}
+
+// printForecastPreview shows what is known of the cards a Forecast will
+// turn over, so they can be checked before the new order is entered.
+func (p *PandemicView) printForecastPreview(out io.Writer, gameState *pandemic.GameState) {
+	fmt.Fprint(out, p.t("Top %v infection cards:\n", pandemic.ForecastCards))
+	for _, slot := range gameState.InfectionDeck.ForecastPreview() {
+		cities := []string{}
+		for _, city := range slot.Cities {
+			cities = append(cities, p.cityLabel(city))
+		}
+		switch {
+		case slot.First == slot.Last && slot.Complete():
+			fmt.Fprintf(out, "  %v: %v\n", slot.First+1, cities[0])
+		case slot.Complete():
+			fmt.Fprint(out, p.t("  %v-%v: %v in any order\n", slot.First+1, slot.Last+1, strings.Join(cities, ", ")))
+		default:
+			fmt.Fprint(out, p.t("  %v-%v: %v of %v\n", slot.First+1, slot.Last+1, slot.Last-slot.First+1, strings.Join(cities, ", ")))
+		}
+	}
+	fmt.Fprintln(out, p.t("Enter the new order with forecast <top city> <next city>... [/ <cities put back in no known order>...]"))
+}
+
+// printOutbreaks lists each outbreak in a chain and where its cubes went,
+// in the order to resolve them on the board. It goes to the console even
+// when output is terse, so the board can be checked against it.
+func (p *PandemicView) printOutbreaks(out io.Writer, gameState *pandemic.GameState, chain pandemic.OutbreakChain) {
+	if len(chain) == 0 {
+		return
+	}
+	if !gameState.ResolveOutbreaks {
+		fmt.Fprintln(out, p.colorOhFuck("%v", p.t("Outbreak in %v, this game was started before the tracker resolved outbreaks, so enter it with set outbreaks and set infections", p.cityLabel(chain[0].City))))
+		return
+	}
+	var max interface{} = "?"
+	if rules, err := gameState.Rules(); err == nil {
+		max = rules.MaxOutbreaks()
+	}
+	first := gameState.Outbreaks - len(chain) + 1
+	for i, step := range chain {
+		fmt.Fprintln(out, p.colorOhFuck("%v", p.t("Outbreak %v of %v in %v", first+i, max, p.cityLabel(step.City))))
+		for _, line := range []struct {
+			what   string
+			cities []pandemic.CityName
+		}{
+			{"1 more cube", step.Infected},
+			{"outbreaks next", step.Outbroke},
+			{"protected by quarantine", step.Quarantined},
+			{"protected by a role", step.Protected},
+			{"place their own color's cube by hand", step.OtherDisease},
+			{"taken over by the Faded", step.Faded},
+			{"passed over by the Faded", step.Spared},
+		} {
+			if len(line.cities) == 0 {
+				continue
+			}
+			labels := []string{}
+			for _, city := range line.cities {
+				labels = append(labels, p.cityLabel(city))
+			}
+			fmt.Fprintf(out, "  %v: %v\n", p.t(line.what), strings.Join(labels, ", "))
+		}
+	}
+}
+
+// printEpidemic lists what each step of the epidemic changed, for the table
+// to check the board against.
+func (p *PandemicView) printEpidemic(out io.Writer, result *pandemic.EpidemicResult) {
+	if result.RateAfter != result.RateBefore {
+		fmt.Fprint(out, p.t("  Increase: infection rate %v to %v\n", result.RateBefore, result.RateAfter))
+	} else {
+		fmt.Fprint(out, p.t("  Increase: infection rate stays at %v\n", result.RateAfter))
+	}
+	switch {
+	case result.QuarantineRemoved:
+		fmt.Fprint(out, p.t("  Infect: %v's quarantine marker removed instead of cubes\n", p.cityLabel(result.City)))
+	case len(result.Chain) > 0:
+		fmt.Fprint(out, p.t("  Infect: %v cubes on %v, which outbreaks\n", result.Cubes, p.cityLabel(result.City)))
+	case result.Cubes == 0:
+		fmt.Fprint(out, p.t("  Infect: no cubes placed on %v\n", p.cityLabel(result.City)))
+	default:
+		fmt.Fprint(out, p.t("  Infect: %v cubes on %v\n", result.Cubes, p.cityLabel(result.City)))
+	}
+	fmt.Fprint(out, p.t("  Intensify: %v cards shuffled back on top of the infection deck\n", result.Intensified))
+}
+
+// saveGame writes the game to a new file named after the command that
+// changed it.
+func (p *PandemicView) saveGame(gameState *pandemic.GameState, cmd string) error {
+	// sandbox games are thrown away, so nothing is saved
+	if p.sandbox {
+		return nil
+	}
+	saveDir := filepath.Join(p.config.SaveDir, gameState.GameName)
+	filename := filepath.Join(saveDir, fmt.Sprintf("game_%v_%v.json", time.Now().UnixNano(), cmd))
+	if err := os.MkdirAll(saveDir, 0755); err != nil {
+		return errors.New(p.t("Could not create a game name folder: %v", err))
+	}
+	data, err := json.Marshal(gameState)
+	if err != nil {
+		return errors.New(p.t("Could not marshal gamestate as JSON: %v", err))
+	}
+	if err := ioutil.WriteFile(filename, data, 0644); err != nil {
+		return errors.New(p.t("Could not save gamestate: %v", err))
+	}
+	return nil
+}
+
+// archiveGame writes the final save of an ended game to the archive folder,
+// named after the game, where it is kept apart from the saves in progress.
+func (p *PandemicView) archiveGame(gameState *pandemic.GameState) (string, error) {
+	archiveDir := filepath.Join(p.config.SaveDir, "archive")
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		return "", errors.New(p.t("Could not create the archive folder: %v", err))
+	}
+	data, err := json.Marshal(gameState)
+	if err != nil {
+		return "", errors.New(p.t("Could not marshal gamestate as JSON: %v", err))
+	}
+	// a month can be replayed, so each attempt gets its own archive
+	filename := filepath.Join(archiveDir, fmt.Sprintf("%v_%v.json", gameState.GameName, time.Now().UnixNano()))
+	if err := ioutil.WriteFile(filename, data, 0644); err != nil {
+		return "", errors.New(p.t("Could not archive the game: %v", err))
+	}
+	return filename, nil
+}
+
+// takeAction counts one of the current player's actions and says how many
+// are left, warning when the table has gone over.
+func (p *PandemicView) takeAction(gameState *pandemic.GameState, consoleView io.Writer, action string) {
+	if err := gameState.TakeAction(action); err != nil {
+		fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+		return
+	}
+	if err := gameState.CheckActions(); err != nil {
+		fmt.Fprintln(consoleView, p.colorWarning("%v", err))
+		return
+	}
+	p.explain(consoleView, "%v actions left this turn", gameState.ActionsLeft())
+}
+
+// announceTurn tells the table whose turn it is, out loud if a speech
+// command is set up.
+func (p *PandemicView) announceTurn(gameState *pandemic.GameState, consoleView io.Writer, turn *pandemic.Turn) {
+	fmt.Fprint(consoleView, p.t("It is now %v's turn\n", turn.Player.HumanName))
+	analysis := gameState.CityDeck.EpidemicAnalysis()
+	p.explain(consoleView, "%v has a %.1f%% chance of drawing an epidemic this turn", turn.Player.HumanName, (analysis.FirstCardProbability+analysis.SecondCardProbability)*100)
+	for _, reminder := range p.campaign.RemindersFor(turn.Player) {
+		fmt.Fprintf(consoleView, "  %v\n", reminder)
+	}
+	message := []string{turn.Player.HumanName}
+	if turn.Player.Character != nil && turn.Player.Character.TurnMessage != "" {
+		message = append(message, strings.Split(turn.Player.Character.TurnMessage, " ")...)
+	}
+	if err := p.say(message...); err != nil {
+		fmt.Fprintln(consoleView, p.colorOhFuck("%v", p.t("Could not say message out loud: %v", strings.Join(message, " "))))
+	}
+}
+
+// say reads the words out loud with the configured speech command, if any.
+func (p *PandemicView) say(words ...string) error {
+	if p.config.SpeechCommand == "" {
+		return nil
+	}
+	return exec.Command(p.config.SpeechCommand, words...).Run()
+}
+
+// announceEradication prints each eradication alert once, since a disease
+// can stay one treat away from eradication for many commands.
+func (p *PandemicView) announceEradication(gameState *pandemic.GameState, consoleView io.Writer) {
+	for _, alert := range gameState.CheckEradication() {
+		if p.alerted[alert.String()] {
+			continue
+		}
+		p.alerted[alert.String()] = true
+		fmt.Fprintln(consoleView, p.colorHighlight(alert.String()))
+	}
+}
+
+// announceGuaranteedLoss warns once per reason when no draw can save the
+// game, so the table can concede before spending Legacy resources on it.
+func (p *PandemicView) announceGuaranteedLoss(gameState *pandemic.GameState, consoleView io.Writer) {
+	if gameState.CheckPlaying() != nil {
+		return
+	}
+	lost, reason := gameState.GuaranteedLoss()
+	if !lost || p.alerted[reason] {
+		return
+	}
+	p.alerted[reason] = true
+	fmt.Fprintf(consoleView, "%v\n", p.colorOhFuck("%v", p.t("Guaranteed loss: %v. Consider conceding to save Legacy resources.", reason)))
+}
+
+// printBoardCheck lists what the table should see at a phase boundary: the
+// end of the infection step, an epidemic resolved or the infection step
+// skipped by a quiet night. It also says how to audit the game if the board
+// differs.
+func (p *PandemicView) printBoardCheck(out io.Writer, gameState *pandemic.GameState) {
+	fmt.Fprintln(out, p.colorHighlight("%v", p.t("Check the board:")))
+	for _, check := range gameState.BoardCheck() {
+		fmt.Fprintf(out, "  %v\n", check)
+	}
+	hint := p.t("type audit")
+	if p.redraw != nil {
+		hint = p.t("press %v", p.config.Keys.Audit)
+	}
+	fmt.Fprint(out, p.t("If anything differs, %v\n", hint))
+}
+
+// announceLoss tells the table the game was just lost, once per reason.
+func (p *PandemicView) announceLoss(gameState *pandemic.GameState, consoleView io.Writer) {
+	loss := gameState.Loss()
+	if loss == "" || gameState.Ended != "" || p.alerted["lost: "+loss] {
+		return
+	}
+	p.alerted["lost: "+loss] = true
+	fmt.Fprintln(consoleView, p.colorOhFuck("%v", p.t("Game lost: %v. Type end-game to archive the save.", loss)))
+}
+
+// announceWin tells the table once every win condition is met.
+func (p *PandemicView) announceWin(gameState *pandemic.GameState, consoleView io.Writer) {
+	if gameState.Ended != "" || p.alerted["won"] || !gameState.Won() {
+		return
+	}
+	p.alerted["won"] = true
+	fmt.Fprintln(consoleView, p.colorAllGood("%v", p.t("Every win condition is met, the game is won! Type end-game to archive the save.")))
+}
+
+func (p *PandemicView) runSetCommand(gameState *pandemic.GameState, args []string) error {
+	usage := errors.New(p.t("Usage: set outbreaks <n> | set rate <n> | set infections <city-prefix> <n> | set location <player-prefix> <city-prefix>"))
+	if len(args) < 2 {
+		return usage
+	}
+	if args[0] == "location" && len(args) == 3 {
+		player, err := getPlayerByPrefix(args[1], gameState)
+		if err != nil {
+			return err
+		}
+		if player == nil {
+			return errors.New(p.t("No player named %v", args[1]))
+		}
+		city, err := getCityByPrefix(args[2], gameState)
+		if err != nil {
+			return err
+		}
+		return gameState.PlacePawn(player, city)
+	}
+	value, err := strconv.Atoi(args[len(args)-1])
+	if err != nil {
+		return errors.New(p.t("%v is not a number", args[len(args)-1]))
+	}
+	switch {
+	case args[0] == "outbreaks" && len(args) == 2:
+		return gameState.SetOutbreaks(value)
+	case args[0] == "rate" && len(args) == 2:
+		return gameState.SetInfectionRate(value)
+	case args[0] == "infections" && len(args) == 3:
+		city, err := getCityByPrefix(args[1], gameState)
+		if err != nil {
+			return err
+		}
+		return gameState.SetInfections(city, value)
+	}
+	return usage
 }
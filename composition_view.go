@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+	"github.com/jroimartin/gocui"
+)
+
+const compositionBarWidth = 10
+
+// compositionHeight is how many rows the composition panel needs including
+// its frame: one per disease left in the deck plus the cube breakdown.
+func compositionHeight(game *pandemic.GameState) int {
+	return len(game.DeckComposition().ByDisease) + 3
+}
+
+// renderComposition breaks the next striation down by color, next to the
+// same share of the whole infection deck, e.g.
+//
+//	💛 ██████░░░░ 60% next, 35% deck
+func (p *PandemicView) renderComposition(game *pandemic.GameState, gui *gocui.Gui, topX, topY, bottomX, bottomY int) {
+	view, err := gui.SetView("Composition", topX, topY, bottomX, bottomY)
+	p.terminateIfErr(err, "Could not set up deck composition view", gui)
+	view.Clear()
+	next := game.NextStriationComposition()
+	deck := game.DeckComposition()
+	view.Title = p.t("Next Striation (%v cards)", next.Total)
+
+	for _, dt := range deck.Diseases() {
+		filled := int(next.Fraction(dt)*compositionBarWidth + 0.5)
+		bar := strings.Repeat("█", filled) + strings.Repeat("░", compositionBarWidth-filled)
+		fmt.Fprint(view, p.t("%v  %v %3.0f%% next, %.0f%% deck\n", p.iconFor(dt), bar, next.Fraction(dt)*100, deck.Fraction(dt)*100))
+	}
+	fmt.Fprintln(view)
+	cubes := []string{}
+	for count, cities := range next.ByCubes {
+		cubes = append(cubes, fmt.Sprintf("%v:%v", strings.Repeat("•", count), cities))
+	}
+	fmt.Fprint(view, p.t("cubes %v\n", strings.Join(cubes, " ")))
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+	"github.com/fatih/color"
+	"github.com/jroimartin/gocui"
+)
+
+// Config holds every user tunable setting. It is built in layers: the
+// defaults below, then the user's TOML config file, then any overrides
+// stored in the campaign file, which being JSON keeps them as JSON. Each
+// layer only needs to mention the settings it changes.
+type Config struct {
+	Theme         string     `json:"theme"`
+	Thresholds    Thresholds `json:"thresholds"`
+	Keys          KeyConfig  `json:"keys"`
+	SaveDir       string     `json:"save_dir"`
+	SpeechCommand string     `json:"speech_command"`
+	// HotSeat prompts each player in turn and enforces the turn structure.
+	HotSeat bool `json:"hot_seat"`
+	// BoardCheck lists what the board should show at the end of every
+	// infection step, to compare with the table.
+	BoardCheck bool `json:"board_check"`
+	// AutoAdvance starts the next player's turn as soon as the current one
+	// has drawn its city cards and infected as many cities as it should.
+	AutoAdvance bool `json:"auto_advance"`
+	// Language picks a translation from data/locales. Empty shows the
+	// canonical names from the game data.
+	Language string `json:"language"`
+	// Risk names one of the riskProfiles. The profile replaces the default
+	// thresholds, but thresholds set explicitly in any layer still win.
+	Risk string `json:"risk"`
+	// IdleSnapshotMinutes is how long the tracker waits without a command
+	// before checkpointing the session. 0 turns checkpoints off.
+	IdleSnapshotMinutes int `json:"idle_snapshot_minutes"`
+	// HiddenPanels are the panels toggled off, remembered in the user's
+	// config file between sessions.
+	HiddenPanels []string `json:"hidden_panels"`
+	// DrawOverlaySeconds is how long an infection card is shown in large
+	// letters after it is entered. 0 turns the overlay off.
+	DrawOverlaySeconds int `json:"draw_overlay_seconds"`
+	// Telemetry shares how accurate the predictions were, if opted in.
+	Telemetry Telemetry `json:"telemetry"`
+	// Integrations map a kind of change to the game, such as "epidemic", to
+	// a command run with a description of each one, e.g. to post it to the
+	// group's chat.
+	Integrations map[string]string `json:"integrations"`
+	// Verbosity is terse, normal or verbose. -q and -v after a command
+	// override it for that command.
+	Verbosity string `json:"verbosity"`
+
+	locale   *Locale
+	userFile string
+}
+
+// A RiskProfile captures how much danger the table is willing to accept.
+type RiskProfile struct {
+	Thresholds Thresholds
+	// WorstCase ranks plans and advice by their worst outcome instead of
+	// their expected one.
+	WorstCase bool
+	// SortThreatsByProbability orders the striation panels by likelihood of infection
+	// instead of by how bad an infection would be.
+	SortThreatsByProbability bool
+}
+
+var riskProfiles = map[string]RiskProfile{
+	"cautious": {
+		Thresholds: Thresholds{EpidemicDanger: 0.3, CureUnlikely: 0.3, CureLikely: 0.9, SafeDraws: 3},
+		WorstCase:  true,
+	},
+	"normal": {
+		Thresholds: defaultConfig.Thresholds,
+	},
+	"gambler": {
+		Thresholds:               Thresholds{EpidemicDanger: 0.7, CureUnlikely: 0.1, CureLikely: 0.6, SafeDraws: 1},
+		SortThreatsByProbability: true,
+	},
+}
+
+func (c *Config) risk() RiskProfile {
+	return riskProfiles[c.Risk]
+}
+
+type Thresholds struct {
+	EpidemicDanger float64 `json:"epidemic_danger"`
+	CureUnlikely   float64 `json:"cure_unlikely"`
+	CureLikely     float64 `json:"cure_likely"`
+	SafeDraws      int     `json:"safe_draws"`
+}
+
+type KeyConfig struct {
+	Quit     string `json:"quit"`
+	Complete string `json:"complete"`
+	Logs     string `json:"logs"`
+	// Stats shows the quick stats of the city being typed.
+	Stats string `json:"stats"`
+	// Audit runs the audit, e.g. when the board check doesn't match.
+	Audit string `json:"audit"`
+	// Back and Forward step the rewind panel through the saves.
+	Back    string `json:"back"`
+	Forward string `json:"forward"`
+	// Panels maps each panel to the key that shows and hides it.
+	Panels map[string]string `json:"panels"`
+}
+
+var defaultConfig = Config{
+	Theme: "default",
+	Thresholds: Thresholds{
+		EpidemicDanger: 0.5,
+		CureUnlikely:   0.2,
+		CureLikely:     0.8,
+		SafeDraws:      2,
+	},
+	Keys: KeyConfig{
+		Quit:     "ctrl-c",
+		Complete: "tab",
+		Logs:     "ctrl-l",
+		Stats:    "ctrl-t",
+		Audit:    "ctrl-a",
+		Back:     "ctrl-b",
+		Forward:  "ctrl-f",
+		Panels: map[string]string{
+			"striations": "f1",
+			"cities":     "f2",
+			"cures":      "f3",
+			"console":    "f4",
+		},
+	},
+	SaveDir:             ".",
+	SpeechCommand:       "say",
+	Risk:                "normal",
+	IdleSnapshotMinutes: 5,
+	DrawOverlaySeconds:  3,
+	AutoAdvance:         true,
+	Verbosity:           normalOutput,
+}
+
+var keyNames = map[string]gocui.Key{
+	"tab":    gocui.KeyTab,
+	"ctrl-a": gocui.KeyCtrlA,
+	"ctrl-b": gocui.KeyCtrlB,
+	"ctrl-c": gocui.KeyCtrlC,
+	"ctrl-d": gocui.KeyCtrlD,
+	"ctrl-f": gocui.KeyCtrlF,
+	"ctrl-l": gocui.KeyCtrlL,
+	"ctrl-q": gocui.KeyCtrlQ,
+	"ctrl-t": gocui.KeyCtrlT,
+	"ctrl-x": gocui.KeyCtrlX,
+	"f1":     gocui.KeyF1,
+	"f2":     gocui.KeyF2,
+	"f3":     gocui.KeyF3,
+	"f4":     gocui.KeyF4,
+	"f5":     gocui.KeyF5,
+	"f6":     gocui.KeyF6,
+}
+
+func defaultUserConfigFile() string {
+	return filepath.Join(os.Getenv("HOME"), ".pandemic-nerd-hurd.toml")
+}
+
+func loadConfig(userFile string, campaign *pandemic.Campaign) (*Config, error) {
+	config := defaultConfig
+	config.userFile = userFile
+	config.Keys.Panels = map[string]string{}
+	for panel, key := range defaultConfig.Keys.Panels {
+		config.Keys.Panels[panel] = key
+	}
+	layers := [][]byte{}
+	data, err := ioutil.ReadFile(userFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if data, err = tomlToJSON(data); err != nil {
+			return nil, fmt.Errorf("Invalid config file at %v: %v", userFile, err)
+		}
+		if err = json.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("Invalid config file at %v: %v", userFile, err)
+		}
+		layers = append(layers, data)
+	}
+	if len(campaign.Config) > 0 {
+		if err = json.Unmarshal(campaign.Config, &config); err != nil {
+			return nil, fmt.Errorf("Invalid config in campaign file: %v", err)
+		}
+		layers = append(layers, campaign.Config)
+	}
+	profile, ok := riskProfiles[config.Risk]
+	if !ok {
+		return nil, fmt.Errorf("Unknown risk profile %q", config.Risk)
+	}
+	config.Thresholds = profile.Thresholds
+	for _, layer := range layers {
+		if err = json.Unmarshal(layer, &config); err != nil {
+			return nil, fmt.Errorf("Invalid config: %v", err)
+		}
+	}
+	if _, ok := themes[config.Theme]; !ok {
+		return nil, fmt.Errorf("Unknown theme %q", config.Theme)
+	}
+	if !validVerbosity(config.Verbosity) {
+		return nil, fmt.Errorf("Unknown verbosity %q, use terse, normal or verbose", config.Verbosity)
+	}
+	for _, name := range []string{config.Keys.Quit, config.Keys.Complete, config.Keys.Logs, config.Keys.Stats, config.Keys.Audit, config.Keys.Back, config.Keys.Forward} {
+		if _, ok := keyNames[name]; !ok {
+			return nil, fmt.Errorf("Unknown key %q", name)
+		}
+	}
+	if config.Language != "" {
+		if config.locale, err = loadLocale(config.Language, userFile); err != nil {
+			return nil, err
+		}
+	}
+	return &config, nil
+}
+
+func (c *Config) key(name string) gocui.Key {
+	return keyNames[name]
+}
+
+type theme struct {
+	whiteHighlight []color.Attribute
+	allGood        []color.Attribute
+	warning        []color.Attribute
+	highlight      []color.Attribute
+	ohFuck         []color.Attribute
+}
+
+var themes = map[string]theme{
+	"default": {
+		whiteHighlight: []color.Attribute{color.FgBlack, color.BgWhite},
+		allGood:        []color.Attribute{color.FgGreen, color.BgBlack},
+		warning:        []color.Attribute{color.FgYellow, color.BgBlack},
+		highlight:      []color.Attribute{color.FgRed},
+		ohFuck:         []color.Attribute{color.FgBlack, color.BgRed, color.BlinkSlow},
+	},
+	"no-blink": {
+		whiteHighlight: []color.Attribute{color.FgBlack, color.BgWhite},
+		allGood:        []color.Attribute{color.FgGreen, color.BgBlack},
+		warning:        []color.Attribute{color.FgYellow, color.BgBlack},
+		highlight:      []color.Attribute{color.FgRed},
+		ohFuck:         []color.Attribute{color.FgBlack, color.BgRed},
+	},
+	"light": {
+		whiteHighlight: []color.Attribute{color.FgWhite, color.BgBlack},
+		allGood:        []color.Attribute{color.FgGreen},
+		warning:        []color.Attribute{color.FgMagenta},
+		highlight:      []color.Attribute{color.FgRed},
+		ohFuck:         []color.Attribute{color.FgWhite, color.BgRed},
+	},
+}
@@ -32,10 +32,10 @@ func (b byCurability) Swap(i, j int) {
 
 func (p *PandemicView) colorProbabilityOfCure(prob float64) string {
 	str := fmt.Sprintf("%.2f", prob)
-	if prob < 0.2 {
+	if prob < p.config.Thresholds.CureUnlikely {
 		return p.colorOhFuck(str)
 	}
-	if prob < 0.8 {
+	if prob < p.config.Thresholds.CureLikely {
 		return p.colorWarning(str)
 	}
 	return p.colorAllGood(str)
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+	"github.com/jroimartin/gocui"
+)
+
+// cureProgressHeight is how many rows the cure progress panel needs
+// including its frame and the cube supply line.
+func cureProgressHeight() int {
+	return len(pandemic.CurableDiseases()) + 3
+}
+
+// lowCubes is the supply left of a color at which it is shown as a warning:
+// one epidemic and an outbreak could use it all.
+const lowCubes = 2 * pandemic.MaxInfections
+
+// renderCureProgress shows, per disease, how many matching cards the team
+// holds against what the designated curer needs, e.g.
+//
+//	💛 ███░░ 3/5 team (Will holds 2)
+func (p *PandemicView) renderCureProgress(game *pandemic.GameState, gui *gocui.Gui, topX, topY, bottomX, bottomY int) {
+	view, err := gui.SetView("Cures", topX, topY, bottomX, bottomY)
+	p.terminateIfErr(err, "Could not set up cure progress view", gui)
+	view.Clear()
+	view.Title = "Cure Progress"
+
+	diseases := pandemic.CurableDiseases()
+	sort.Sort(byCurability{dts: diseases})
+	for _, dt := range diseases {
+		if data, err := game.GetDiseaseData(dt); err == nil && data.Eradicated {
+			fmt.Fprintf(view, "%v  %v\n", p.iconFor(dt), p.colorAllGood("%v", p.t("eradicated")))
+			continue
+		} else if err == nil && data.Cured {
+			fmt.Fprintf(view, "%v  %v\n", p.iconFor(dt), p.colorAllGood("%v", p.t("cured")))
+			continue
+		}
+		progress := game.CureProgress(dt)
+		if progress.Curer == nil {
+			fmt.Fprint(view, p.t("%v  nobody can cure\n", p.iconFor(dt)))
+			continue
+		}
+		filled := progress.TeamHolds
+		if filled > progress.Needed {
+			filled = progress.Needed
+		}
+		bar := strings.Repeat("█", filled) + strings.Repeat("░", progress.Needed-filled)
+		if progress.CurerHolds >= progress.Needed {
+			bar = p.colorAllGood(bar)
+		}
+		unknown := ""
+		if progress.Unknown > 0 {
+			unknown = fmt.Sprintf(" +%v?", progress.Unknown)
+		}
+		fmt.Fprint(view, p.t("%v  %v %v%v/%v team (%v holds %v)\n", p.iconFor(dt), bar, progress.TeamHolds, unknown, progress.Needed, progress.Curer.HumanName, progress.CurerHolds))
+	}
+	fmt.Fprint(view, p.t("Cubes left: %v\n", p.cubeSupply(game)))
+}
+
+// cubeSupply shows the cubes left of each color, e.g. "💛 19  ❤️ 4".
+func (p *PandemicView) cubeSupply(game *pandemic.GameState) string {
+	counts := []string{}
+	for _, supply := range game.CubeSupply() {
+		count := fmt.Sprintf("%v %v", p.iconFor(supply.Disease), supply.Left)
+		if supply.Left <= lowCubes {
+			count = p.colorWarning("%v", count)
+		}
+		counts = append(counts, count)
+	}
+	return strings.Join(counts, "  ")
+}
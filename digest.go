@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+)
+
+// writeDigest summarizes the games played since the given time as Markdown,
+// ready to paste into the group chat. The panic map and notable events come
+// from the given game state, which may be nil, and the events only from the
+// given turns.
+func writeDigest(out io.Writer, campaign *pandemic.Campaign, gs *pandemic.GameState, since time.Time, turns pandemic.TurnRange) {
+	fmt.Fprintf(out, "# Pandemic Legacy digest, %v\n\n", time.Now().Format("January 2, 2006"))
+
+	fmt.Fprintln(out, "## Games played")
+	results := campaign.ResultsSince(since)
+	if len(results) == 0 {
+		fmt.Fprintln(out, "\nNo games this session.")
+	} else {
+		fmt.Fprintln(out)
+		for _, result := range results {
+			outcome := "Lost"
+			if result.Won {
+				outcome = "Won"
+			}
+			fmt.Fprintf(out, "* **%v** (attempt %v): %v with %v outbreaks and %v fallen cities\n", result.Month, result.Attempt, outcome, result.Outbreaks, result.FallenCities)
+		}
+	}
+	score := campaign.FinalScore()
+	fmt.Fprintf(out, "\nCampaign score so far: **%v**\n", score.Total)
+
+	if gs != nil && gs.Metadata != nil {
+		fmt.Fprintf(out, "\nLatest game: **%v**, %v\n", gs.GameName, gs.Metadata)
+	}
+
+	if gs != nil {
+		fmt.Fprintln(out, "\n## Panic map")
+		panicked := []*pandemic.City{}
+		for _, city := range *gs.Cities {
+			if city.PanicLevel != pandemic.Nothing {
+				panicked = append(panicked, city)
+			}
+		}
+		sort.Sort(byPanic(panicked))
+		if len(panicked) == 0 {
+			fmt.Fprintln(out, "\nEvery city is calm.")
+		} else {
+			fmt.Fprintln(out, "\n| City | Panic |\n| --- | --- |")
+			for _, city := range panicked {
+				name := string(city.Name)
+				if nickname := campaign.Nicknames[city.Name]; nickname != "" {
+					name = fmt.Sprintf("%v (%v)", city.Name, nickname)
+				}
+				fmt.Fprintf(out, "| %v | %v |\n", name, city.PanicLevel)
+			}
+		}
+
+		notable := []*pandemic.GameEvent{}
+		for _, event := range gs.EventsIn(turns) {
+			// every infection is logged, which is too routine to list
+			if event.Kind != pandemic.Infected {
+				notable = append(notable, event)
+			}
+		}
+		if len(notable) > 0 {
+			fmt.Fprintf(out, "\n## Notable events, %v\n\n", turns)
+			for _, event := range notable {
+				fmt.Fprintf(out, "* Turn %v (%v, %v into the game): %v\n", event.Turn+1, event.Time.Format("Jan 2 15:04"), gs.GameClock(event.Time), event.Message)
+			}
+		}
+	}
+
+	fmt.Fprintln(out, "\n## Open objectives")
+	objectives := campaign.OpenObjectives()
+	if len(objectives) == 0 {
+		fmt.Fprintln(out, "\nNone.")
+	} else {
+		fmt.Fprintln(out)
+		for _, objective := range objectives {
+			fmt.Fprintf(out, "* [ ] %v\n", objective.Description)
+		}
+	}
+}
+
+type byPanic []*pandemic.City
+
+func (b byPanic) Len() int      { return len(b) }
+func (b byPanic) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byPanic) Less(i, j int) bool {
+	if b[i].PanicLevel != b[j].PanicLevel {
+		return b[i].PanicLevel > b[j].PanicLevel
+	}
+	return b[i].Name < b[j].Name
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
@@ -12,9 +15,15 @@ import (
 
 var (
 	app              = kingpin.New("pandemic–nerd-hurd", "Start a nerd herd game")
+	campaignFile     = app.Flag("campaign", "The JSON file holding the results of every game in the campaign").Default("campaign.json").String()
+	logLevel         = app.Flag("log-level", "Only log entries at this level or above").Default("info").Enum("debug", "info", "warning", "error")
+	plainOutput      = app.Flag("plain", "Read commands line by line and describe the game in plain text, for screen readers or piping to a file").Bool()
+	hotSeat          = app.Flag("hot-seat", "Prompt each player in turn and enforce the turn structure").Bool()
+	strictLoad       = app.Flag("strict", "Refuse saves with unknown fields or impossible states instead of loading what can be read").Bool()
+	configFile       = app.Flag("config", "The TOML file containing your personal settings").Default(defaultUserConfigFile()).String()
 	startCmd         = app.Command("start", "Start a new game")
 	startNewGameFile = startCmd.Flag("new-game-file", "The file containing initial data about Cities, Players and Funded Events.").Default("data/new_game.json").ExistingFile()
-	startMonth       = startCmd.Flag("month", "The name of the month in the game we are playing. If playing the second time in a month, add '2' after the name").Required().Enum(
+	startMonth       = startCmd.Flag("month", "The name of the month in the game we are playing. If playing the second time in a month, add '2' after the name. Defaults to the next game in the campaign").Enum(
 		"jan",
 		"feb",
 		"mar",
@@ -40,33 +49,282 @@ var (
 		"nov2",
 		"dec2",
 	)
-	loadCmd  = app.Command("load", "Load a game from an existing saved game")
-	loadFile = loadCmd.Flag("file", "The JSON file containing the game state").Required().ExistingFile()
+	startEpidemics    = startCmd.Flag("epidemics", "How many epidemics to shuffle in, overriding the new game file").Int()
+	startFundedEvents = startCmd.Flag("funded-events", "Shuffle in only the first n funded events in the new game file").Int()
+
+	loadCmd     = app.Command("load", "Load a game from an existing saved game")
+	loadFile    = loadCmd.Flag("file", "The JSON file containing the game state. Lists the saved games if left out").ExistingFile()
+	scoreCmd    = app.Command("score", "Print the final campaign score")
+	digestCmd   = app.Command("digest", "Write a Markdown summary of the latest session")
+	digestSince = digestCmd.Flag("since", "How far back the session goes").Default("168h").Duration()
+	digestGame  = digestCmd.Flag("game", "A saved game to take the panic map and notable events from").ExistingFile()
+	digestTurns = digestCmd.Flag("turns", "Only list the notable events of these turns, e.g. 10-20").String()
+	watchCmd    = app.Command("watch", "Show the analysis panels of a game being played in another terminal")
+	watchDir    = watchCmd.Flag("dir", "The folder the game is being saved to").Required().ExistingDir()
+
+	puzzleCmd   = app.Command("puzzle", "Try plans against a crafted game state")
+	puzzleState = puzzleCmd.Arg("state", "The JSON file containing the puzzle's game state").Required().ExistingFile()
+	puzzleDeck  = puzzleCmd.Flag("deck", "An exported infection deck to use instead of the one in the state").ExistingFile()
+
+	probCmd        = app.Command("prob", "Print the probability of a city being infected next turn")
+	probSave       = probCmd.Arg("save", "The JSON file containing the game state").Required().ExistingFile()
+	probCity       = probCmd.Arg("city", "The city (or a prefix of it) to check").Required().String()
+	threatsCmd     = app.Command("threats", "Print every city that could be infected next turn, most likely first")
+	threatsSave    = threatsCmd.Arg("save", "The JSON file containing the game state").Required().ExistingFile()
+	forecastCmd    = app.Command("forecast", "Print the epidemic forecast for the city deck")
+	forecastSave   = forecastCmd.Arg("save", "The JSON file containing the game state").Required().ExistingFile()
+	forecastPhases = forecastCmd.Flag("phases", "Also forecast the striations the next N infection phases draw from").Default("0").Int()
+	worstCaseCmd   = app.Command("worstcase", "Print the worst that can happen before the next turn")
+	worstCaseSave  = worstCaseCmd.Arg("save", "The JSON file containing the game state").Required().ExistingFile()
+	eventOddsCmd   = app.Command("eventodds", "Print the chance of drawing a funded event before the next epidemic")
+	eventOddsSave  = eventOddsCmd.Arg("save", "The JSON file containing the game state").Required().ExistingFile()
+	eventOddsEvent = eventOddsCmd.Arg("event", "The event (or a prefix of it), or every event left in the deck if omitted").String()
+	verifyCmd      = app.Command("verify", "Check the odds against the slow reference engine")
+	verifySave     = verifyCmd.Arg("save", "The JSON file containing the game state").Required().ExistingFile()
+	dumpCmd        = app.Command("dump", "Print the whole game as sorted, readable text for a bug report")
+	dumpSave       = dumpCmd.Arg("save", "The JSON file containing the game state").Required().ExistingFile()
+
+	fixtureCmd   = app.Command("fixture", "Turn a game's saves into an anonymized regression test fixture")
+	fixtureSaves = fixtureCmd.Arg("saves", "The directory the game was saved to").Required().ExistingDir()
+	fixtureOut   = fixtureCmd.Arg("out", "Where to write the fixture, e.g. pandemic/testdata/fixtures/<name>.json").Required().String()
+
+	scenarioCmd  = app.Command("scenario", "Run rule test scenarios")
+	scenarioFile = scenarioCmd.Arg("file", "The scenario files").Required().ExistingFiles()
+
+	campaignCmd        = app.Command("campaign", "Keep several campaigns side by side, each with its own saves")
+	campaignSwitchCmd  = campaignCmd.Command("switch", "Play the named campaign from now on, starting it if it is new")
+	campaignSwitchName = campaignSwitchCmd.Arg("name", "The campaign, e.g. the group playing it").Required().String()
+	campaignListCmd    = campaignCmd.Command("list", "List the campaigns, marking the one being played")
+
+	transitionCmd      = app.Command("transition", "Record the end of a month and write the next game's setup")
+	transitionGame     = transitionCmd.Arg("save", "The JSON file containing the finished game").Required().ExistingFile()
+	transitionTemplate = transitionCmd.Flag("new-game-file", "The setup the finished game was started with").Default("data/new_game.json").ExistingFile()
+	transitionOut      = transitionCmd.Flag("out", "Where to write the next game's setup").Default("next_game.json").String()
 )
 
+// loadSave loads a saved game, refusing anything suspicious with --strict.
+func loadSave(path string) (*pandemic.GameState, error) {
+	if *strictLoad {
+		return pandemic.LoadGameStrict(path)
+	}
+	return pandemic.LoadGame(path)
+}
+
 func main() {
 	cmd := kingpin.MustParse(app.Parse(os.Args[1:]))
 
 	logger := logrus.New()
 	fd, err := os.OpenFile("log.txt", os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
 	logger.Out = fd
+	logger.Level, _ = logrus.ParseLevel(*logLevel)
 	wd, _ := os.Getwd()
 
 	var gameState *pandemic.GameState
 
+	store, err := pandemic.LoadCampaignStore(wd)
+	if err != nil {
+		logger.Fatalln(err)
+	}
+	switch cmd {
+	case "campaign switch":
+		if err := store.Switch(*campaignSwitchName); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Now playing campaign %v, saved in %v\n", store.Active, store.Dir(store.Active))
+		return
+	case "campaign list":
+		printCampaigns(os.Stdout, store)
+		return
+	}
+
+	campaignPath := filepath.Join(wd, *campaignFile)
+	if store.Active != "" {
+		campaignPath = store.CampaignFile(store.Active)
+	}
+	campaign, err := pandemic.LoadCampaign(campaignPath)
+	if err != nil {
+		logger.Fatalln(err)
+	}
+	if campaign.Name == "" {
+		campaign.Name = store.Active
+	}
+
+	// Only the UI, and the commands listing the saves it writes, read the
+	// config, so a broken config file can't stop the other commands.
+	var config *Config
+	switch cmd {
+	case "start", "load", "score", "puzzle", "watch":
+		config, err = loadConfig(*configFile, campaign)
+		if err != nil {
+			logger.Fatalln(err)
+		}
+		// each campaign in the store keeps its saves, and the stats worked
+		// out from them, to itself
+		if store.Active != "" && !filepath.IsAbs(config.SaveDir) {
+			config.SaveDir = filepath.Join(store.Dir(store.Active), config.SaveDir)
+		}
+		if *hotSeat {
+			config.HotSeat = true
+		}
+	}
+
 	switch cmd {
 	case "start":
-		gameState, err = pandemic.NewGame(filepath.Join(wd, *startNewGameFile), *startMonth)
+		gameName := *startMonth
+		if gameName == "" {
+			gameName = pandemic.GameNameFor(campaign.NextGame())
+		}
+		var settings pandemic.NewGameSettings
+		settings, err = pandemic.LoadNewGameSettings(filepath.Join(wd, *startNewGameFile))
 		if err != nil {
 			logger.Fatalln(err)
 		}
+		if *startEpidemics != 0 {
+			settings.Epidemics = *startEpidemics
+		}
+		if *startFundedEvents != 0 {
+			settings.FundedEventCount = *startFundedEvents
+		}
+		gameState, err = pandemic.NewGameFrom(settings, gameName)
+		if err != nil {
+			logger.Fatalln(err)
+		}
+		if problems := campaign.CheckInfectionDeck(gameState); len(problems) > 0 {
+			if *plainOutput {
+				// stdin holds the game's commands, so just warn
+				fmt.Fprintln(os.Stderr, strings.Join(problems, "\n"))
+			} else if !confirmInfectionDeck(os.Stdin, os.Stdout, problems) {
+				os.Exit(1)
+			}
+		}
 	case "load":
-		gameState, err = pandemic.LoadGame(filepath.Join(wd, *loadFile))
+		if *loadFile == "" {
+			printLoadMenu(os.Stdout, config.SaveDir)
+			return
+		}
+		gameState, err = loadSave(filepath.Join(wd, *loadFile))
+		if err != nil {
+			logger.Fatalln(err)
+		}
+	case "score":
+		printFinalScore(os.Stdout, fmt.Sprintf, campaign)
+		printCityRecords(os.Stdout, fmt.Sprintf, pandemic.CityRecords(campaignGames(config.SaveDir, nil)))
+		return
+	case "digest":
+		var gs *pandemic.GameState
+		if *digestGame != "" {
+			gs, err = loadSave(filepath.Join(wd, *digestGame))
+			if err != nil {
+				logger.Fatalln(err)
+			}
+		}
+		turns := pandemic.TurnRange{}
+		if *digestTurns != "" {
+			if turns, err = pandemic.ParseTurnRange(*digestTurns); err != nil {
+				logger.Fatalln(err)
+			}
+		}
+		writeDigest(os.Stdout, campaign, gs, time.Now().Add(-*digestSince), turns)
+		return
+	case "puzzle":
+		puzzle, err := loadSave(filepath.Join(wd, *puzzleState))
 		if err != nil {
 			logger.Fatalln(err)
 		}
+		if *puzzleDeck != "" {
+			file, err := os.Open(*puzzleDeck)
+			if err != nil {
+				logger.Fatalln(err)
+			}
+			deck, err := pandemic.ReadInfectionDeckYAML(file)
+			file.Close()
+			if err == nil {
+				err = puzzle.ImportInfectionDeck(deck)
+			}
+			if err != nil {
+				logger.Fatalln(err)
+			}
+		}
+		if err := NewView(logger, campaign, config).RunPuzzle(puzzle, os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	case "watch":
+		NewView(logger, campaign, config).Watch(filepath.Join(wd, *watchDir))
+		return
+	case "transition":
+		gs, err := loadSave(filepath.Join(wd, *transitionGame))
+		if err == nil {
+			var template pandemic.NewGameSettings
+			if template, err = pandemic.LoadNewGameSettings(filepath.Join(wd, *transitionTemplate)); err == nil {
+				err = runTransition(os.Stdin, os.Stdout, campaign, gs, template, *transitionOut)
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	case "fixture":
+		name := strings.TrimSuffix(filepath.Base(*fixtureOut), ".json")
+		fixture, err := pandemic.FixtureFromSaves(name, *fixtureSaves)
+		if err == nil {
+			if err = fixture.Replay(); err != nil {
+				err = fmt.Errorf("The saves do not replay cleanly: %v", err)
+			}
+		}
+		if err == nil {
+			err = fixture.Save(*fixtureOut)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote %v steps to %v\n", len(fixture.Steps), *fixtureOut)
+		return
+	case "scenario":
+		failed := false
+		for _, path := range *scenarioFile {
+			scenario, err := pandemic.LoadScenario(path)
+			if err == nil {
+				err = scenario.Run()
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				failed = true
+				continue
+			}
+			fmt.Printf("ok   %v\n", scenario.Name)
+		}
+		if failed {
+			os.Exit(1)
+		}
+		return
+	case "prob", "threats", "forecast", "worstcase", "eventodds", "verify", "dump":
+		err = runAnalysis(os.Stdout, cmd)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	view := NewView(logger)
+	var staged []string
+	if !*plainOutput && config.IdleSnapshotMinutes > 0 {
+		if restored := offerRecovery(os.Stdin, os.Stdout, config.SaveDir, gameState.GameName); restored != nil {
+			gameState = restored.Game
+			staged = restored.Staged
+		}
+	}
+
+	view := NewView(logger, campaign, config)
+	view.staged = staged
+	view.subscribe(gameState)
+	if *plainOutput {
+		view.RunPlain(gameState, os.Stdin, os.Stdout)
+		return
+	}
 	view.Start(gameState)
 }
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+)
+
+// hotSeatPrompt tells the table whose turn it is and what the operator
+// should be entering next, e.g. "Player 2 (Medic): enter your draws".
+func (p *PandemicView) hotSeatPrompt(game *pandemic.GameState) string {
+	turn, err := game.GameTurns.CurrentTurn()
+	if err != nil {
+		return p.t("Commands")
+	}
+	number := 1
+	for i, player := range game.GameTurns.PlayerOrder {
+		if player.HumanName == turn.Player.HumanName {
+			number = i + 1
+		}
+	}
+	who := turn.Player.HumanName
+	if turn.Player.Character != nil {
+		who = string(turn.Player.Character.Type)
+	}
+	var ask string
+	switch turn.Phase() {
+	case pandemic.ActionPhase:
+		ask = p.t("take your actions, then enter your draws")
+	case pandemic.DrawPhase:
+		ask = p.t("enter your second draw")
+	case pandemic.InfectPhase:
+		ask = p.t("enter the infections, then next-turn")
+	}
+	return p.t("Player %v (%v): %v", number, who, ask)
+}
+
+// phaseIndicator sums up where the game is for the Commands title, e.g.
+// "March · Turn 6 · Alice (Medic) · Draw 2/2", naming the card the engine
+// expects next in the draw and infect phases.
+func (p *PandemicView) phaseIndicator(game *pandemic.GameState) string {
+	turn, err := game.GameTurns.CurrentTurn()
+	if err != nil {
+		return p.t("Commands")
+	}
+	parts := []string{}
+	if month := pandemic.MonthName(game.GameName); month != "" {
+		parts = append(parts, strings.Title(month))
+	}
+	parts = append(parts, p.t("Turn %v", game.GameTurns.CurTurn+1))
+	who := turn.Player.HumanName
+	if turn.Player.Character != nil {
+		who = fmt.Sprintf("%v (%v)", who, turn.Player.Character.Type)
+	}
+	parts = append(parts, who)
+	if game.Ended != "" || game.Loss() != "" {
+		return strings.Join(append(parts, p.t("Game over")), " · ")
+	}
+	switch turn.Phase() {
+	case pandemic.ActionPhase:
+		if taken := len(turn.Actions); taken < pandemic.ActionsPerTurn {
+			parts = append(parts, p.t("Action %v/%v", taken+1, pandemic.ActionsPerTurn))
+		} else {
+			parts = append(parts, p.t("Actions done, draw"))
+		}
+	case pandemic.DrawPhase:
+		parts = append(parts, p.t("Draw %v/%v", len(turn.DrawnCards)+1, pandemic.CityCardsPerTurn))
+	case pandemic.InfectPhase:
+		infected := len(turn.Infected)
+		if turn.QuietNight {
+			parts = append(parts, p.t("Quiet night, next-turn"))
+		} else if infected < game.InfectionRate {
+			parts = append(parts, p.t("Infect %v/%v", infected+1, game.InfectionRate))
+		} else {
+			parts = append(parts, p.t("Infect done, next-turn"))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// drawOwner picks who a drawn card goes to. In hot-seat mode that's always
+// the player whose turn it is, so draws are attributed without naming anyone;
+// otherwise an optional player prefix picks the hand, as for every other
+// command.
+func (p *PandemicView) drawOwner(args []string, turn *pandemic.Turn, gs *pandemic.GameState) (*pandemic.Player, error) {
+	if p.config.HotSeat {
+		return turn.Player, nil
+	}
+	return handOwner(args, turn.Player, gs)
+}
+
+// checkHotSeat keeps hot-seat games to the turn structure: phases can't be
+// skipped, and cards drawn always go to the player whose turn it is.
+func (p *PandemicView) checkHotSeat(cmd string, force bool, args []string, turn *pandemic.Turn, gs *pandemic.GameState) error {
+	if force {
+		return errors.New(p.t("Phases can't be skipped in hot-seat mode"))
+	}
+	drawTo := ""
+	if cmd == "unknown-draw" && len(args) == 2 {
+		drawTo = args[1]
+	} else if (cmd == "city-draw" || cmd == "c" || cmd == "draw") && len(args) == 3 {
+		drawTo = args[2]
+	}
+	if drawTo != "" {
+		player, err := getPlayerByPrefix(drawTo, gs)
+		if err == nil && player != nil && player.HumanName != turn.Player.HumanName {
+			return errors.New(p.t("It is %v's turn, only they can draw", turn.Player.HumanName))
+		}
+	}
+	return nil
+}
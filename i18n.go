@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+)
+
+const localeDir = "data/locales"
+
+// A Locale translates city display names and console output. City names in
+// the data files stay canonical: they are the keys here, and translated
+// names are only used for display and to understand typed commands.
+// Messages are keyed by the English format string, so anything missing
+// from a locale is shown in English.
+type Locale struct {
+	Cities   map[pandemic.CityName]string `json:"cities"`
+	Messages map[string]string            `json:"messages"`
+}
+
+// localeDirs lists where a locale may live, in the order they're tried:
+// beside the config file, beside the binary, then the working directory, so
+// the game finds its locales wherever it's started from.
+func localeDirs(userFile string) []string {
+	dirs := []string{filepath.Join(filepath.Dir(userFile), localeDir)}
+	if binary, err := os.Executable(); err == nil {
+		dirs = append(dirs, filepath.Join(filepath.Dir(binary), localeDir))
+	}
+	return append(dirs, localeDir)
+}
+
+func loadLocale(language, userFile string) (*Locale, error) {
+	var file string
+	var data []byte
+	var err error
+	for _, dir := range localeDirs(userFile) {
+		file = filepath.Join(dir, language+".json")
+		if data, err = ioutil.ReadFile(file); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Could not find the %q locale in %v", language, strings.Join(localeDirs(userFile), ", "))
+	}
+	var locale Locale
+	if err := json.Unmarshal(data, &locale); err != nil {
+		return nil, fmt.Errorf("Invalid locale file at %v: %v", file, err)
+	}
+	return &locale, nil
+}
+
+func (l *Locale) city(cn pandemic.CityName) string {
+	if l == nil || l.Cities[cn] == "" {
+		return string(cn)
+	}
+	return l.Cities[cn]
+}
+
+func (l *Locale) message(format string) string {
+	if l == nil || l.Messages[format] == "" {
+		return format
+	}
+	return l.Messages[format]
+}
+
+// canonicalArgs swaps translated city names typed into a command for the
+// canonical ones. An argument is left alone if it already names a card or
+// a player, so translations never change what a command used to mean.
+func (l *Locale) canonicalArgs(args []string, gs *pandemic.GameState) []string {
+	if l == nil {
+		return args
+	}
+	for i, arg := range args {
+		if i == 0 {
+			continue
+		}
+		if _, err := gs.CityDeck.GetCardByPrefix(arg); err == nil {
+			continue
+		}
+		if player, _ := getPlayerByPrefix(arg, gs); player != nil {
+			continue
+		}
+		var match pandemic.CityName
+		matches := 0
+		for cn, name := range l.Cities {
+			typed := strings.ToLower(strings.Replace(name, " ", "", -1))
+			if strings.HasPrefix(typed, strings.ToLower(arg)) {
+				match = cn
+				matches++
+			}
+		}
+		if matches == 1 {
+			args[i] = string(match)
+		}
+	}
+	return args
+}
+
+// A translator formats output for the reader. The UI's is t, in the
+// configured language, and subcommands, which don't load the config, print
+// with plain fmt.Sprintf.
+type translator func(format string, args ...interface{}) string
+
+// t formats console output in the configured language.
+func (p *PandemicView) t(format string, args ...interface{}) string {
+	return fmt.Sprintf(p.config.locale.message(format), args...)
+}
+
+// cityLabel is the city's name in the configured language, followed by the
+// campaign's nickname for it if there is one.
+func (p *PandemicView) cityLabel(cn pandemic.CityName) string {
+	if nickname := p.campaign.Nicknames[cn]; nickname != "" {
+		return fmt.Sprintf("%v (%v)", p.config.locale.city(cn), nickname)
+	}
+	return p.config.locale.city(cn)
+}
+
+// shortCityLabel is the four letter version of the city's name used in the
+// denser panels, still followed by any nickname.
+func (p *PandemicView) shortCityLabel(cn pandemic.CityName) string {
+	label := []rune(p.config.locale.city(cn))
+	if len(label) > 4 {
+		label = label[:4]
+	}
+	if nickname := p.campaign.Nicknames[cn]; nickname != "" {
+		return fmt.Sprintf("%v %v", string(label), nickname)
+	}
+	return string(label)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+)
+
+// printLoadMenu lists the latest save of every game in the save folder,
+// along with what is known about the game from its metadata.
+func printLoadMenu(out io.Writer, saveDir string) {
+	dirs, err := ioutil.ReadDir(saveDir)
+	if err != nil {
+		fmt.Fprintf(out, "Could not read saves in %v: %v\n", saveDir, err)
+		return
+	}
+	found := false
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		save, err := latestSave(filepath.Join(saveDir, dir.Name()))
+		if err != nil {
+			continue
+		}
+		found = true
+		fmt.Fprintf(out, "%v\n  load --file %v\n", dir.Name(), save)
+		if game, err := pandemic.LoadGame(save); err == nil && game.Metadata != nil {
+			fmt.Fprintf(out, "  %v\n", game.Metadata)
+		}
+	}
+	if !found {
+		fmt.Fprintf(out, "No saved games in %v\n", saveDir)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"strings"
+)
+
+// readOnlyCommands only look at the game, so they still run while it is
+// locked.
+var readOnlyCommands = map[string]bool{
+	"staged":        true,
+	"worstcase":     true,
+	"advise":        true,
+	"discardadvice": true,
+	"simulate":      true,
+	"phases":        true,
+	"eventodds":     true,
+	"whatif":        true,
+	"events":        true,
+	"dump":          true,
+	"audit":         true,
+	"verify":        true,
+	"rules":         true,
+	"rewind":        true,
+	"win":           true,
+	"telemetry":     true,
+	"citystats":     true,
+	"route":         true,
+	"export":        true,
+	"log-level":     true,
+	"lock":          true,
+	"unlock":        true,
+}
+
+// writingSubcommands are the subcommands of read only commands that do more
+// than look, e.g. telemetry send posting and clearing the tallies.
+var writingSubcommands = map[string]string{
+	"telemetry": "send",
+}
+
+// checkLock refuses any command that could change the game while it is
+// locked, e.g. over a dinner break.
+func (p *PandemicView) checkLock(cmd string, args []string) error {
+	if !p.locked {
+		return nil
+	}
+	if sub, ok := writingSubcommands[cmd]; ok && len(args) > 0 && args[0] == sub {
+		return errors.New(p.t("The game is locked, unlock it before running %v %v", cmd, sub))
+	}
+	if !readOnlyCommands[cmd] {
+		return errors.New(p.t("The game is locked, unlock it before running %v", cmd))
+	}
+	return nil
+}
+
+// lock freezes the game until unlock is typed, followed by the passphrase
+// if one was given.
+func (p *PandemicView) lock(args []string) error {
+	if p.locked {
+		return errors.New(p.t("The game is already locked"))
+	}
+	p.locked = true
+	p.lockPhrase = strings.Join(args, " ")
+	return nil
+}
+
+func (p *PandemicView) unlock(args []string) error {
+	if !p.locked {
+		return errors.New(p.t("The game is not locked"))
+	}
+	if strings.Join(args, " ") != p.lockPhrase {
+		return errors.New(p.t("That is not the passphrase the game was locked with"))
+	}
+	p.locked = false
+	p.lockPhrase = ""
+	return nil
+}
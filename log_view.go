@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/jroimartin/gocui"
+)
+
+const logBufferSize = 200
+
+// logBuffer is a logrus hook that remembers the most recent log entries so
+// they can be shown inside the game without leaving the UI.
+type logBuffer struct {
+	mu      sync.Mutex
+	entries []logrus.Entry
+}
+
+func (b *logBuffer) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (b *logBuffer) Fire(entry *logrus.Entry) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries = append(b.entries, *entry)
+	if len(b.entries) > logBufferSize {
+		b.entries = b.entries[len(b.entries)-logBufferSize:]
+	}
+	return nil
+}
+
+// Recent returns the entries at or more severe than the given level,
+// oldest first.
+func (b *logBuffer) Recent(level logrus.Level) []logrus.Entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ret := []logrus.Entry{}
+	for _, entry := range b.entries {
+		if entry.Level <= level {
+			ret = append(ret, entry)
+		}
+	}
+	return ret
+}
+
+func (p *PandemicView) toggleLogs(gui *gocui.Gui, view *gocui.View) error {
+	p.showLogs = !p.showLogs
+	if !p.showLogs {
+		gui.DeleteView("Logs")
+	}
+	return nil
+}
+
+func (p *PandemicView) renderLogs(gui *gocui.Gui, maxX, maxY int) {
+	if !p.showLogs {
+		return
+	}
+	view, err := gui.SetView("Logs", maxX/8, maxY/8, maxX*7/8, maxY*7/8)
+	p.terminateIfErr(err, "Could not set up log view", gui)
+	view.Clear()
+	view.Title = p.t("Logs (%v and above, %v to close)", p.logLevel, p.config.Keys.Logs)
+	view.Wrap = true
+	view.Autoscroll = true
+	for _, entry := range p.logs.Recent(p.logLevel) {
+		fmt.Fprintf(view, "%v %-7v %v\n", entry.Time.Format("15:04:05"), entry.Level, entry.Message)
+	}
+}
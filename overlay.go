@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+	"github.com/jroimartin/gocui"
+)
+
+// drawOverlay shows the card just entered in large letters for a moment,
+// so people across the table can check the operator typed the right one.
+type drawOverlay struct {
+	title       string
+	card        string
+	label       string
+	consequence string
+	until       time.Time
+}
+
+// showDraw puts up the overlay for a card. events is how many events the
+// game had logged before the card was entered, to find what it caused.
+func (p *PandemicView) showDraw(game *pandemic.GameState, title string, city pandemic.CityName, events int) {
+	seconds := p.config.DrawOverlaySeconds
+	if seconds <= 0 {
+		return
+	}
+	consequence := ""
+	if data, err := game.GetCity(city); err == nil {
+		consequence = p.t("%v cubes", data.NumInfections)
+	}
+	for _, event := range game.Log.Events[events:] {
+		if event.Kind == pandemic.Outbreak && event.City == city {
+			consequence = "OUTBREAK"
+		}
+	}
+	duration := time.Duration(seconds) * time.Second
+	p.overlay = &drawOverlay{
+		title:       title,
+		card:        string(city),
+		label:       p.cityLabel(city),
+		consequence: consequence,
+		until:       time.Now().Add(duration),
+	}
+	if p.redraw != nil {
+		time.AfterFunc(duration, p.redraw)
+	}
+}
+
+func (p *PandemicView) renderOverlay(gui *gocui.Gui, width, height int) {
+	if p.overlay == nil || time.Now().After(p.overlay.until) {
+		p.overlay = nil
+		gui.DeleteView("Draw")
+		return
+	}
+	lines := bigText(p.overlay.card, width-2)
+	if lines == nil {
+		lines = []string{strings.ToUpper(p.overlay.card)}
+	}
+	lines = append(lines, "", p.overlay.label)
+	if p.overlay.consequence == "OUTBREAK" {
+		lines = append(lines, p.colorOhFuck(p.overlay.consequence))
+	} else if p.overlay.consequence != "" {
+		lines = append(lines, p.overlay.consequence)
+	}
+	top := (height - len(lines)) / 2
+	view, err := gui.SetView("Draw", 0, top-1, width-1, top+len(lines))
+	if err != nil && err != gocui.ErrUnknownView {
+		p.logger.Errorf("Could not show the draw overlay: %v", err)
+		return
+	}
+	view.Clear()
+	view.Title = p.overlay.title
+	for _, line := range lines {
+		fmt.Fprintln(view, line)
+	}
+}
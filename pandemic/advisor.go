@@ -0,0 +1,170 @@
+package pandemic
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// Campaign-level costs of an outbreak. A city that outbreaks gains a panic
+// level, a city pushed past collapsing falls for the rest of the campaign,
+// and a character standing in it takes a scar.
+const (
+	PanicCost = 1
+	FallCost  = 5
+	ScarCost  = 2
+)
+
+// AdvisorMode picks what the advisor tries to minimize.
+type AdvisorMode string
+
+const (
+	// WinMode scores actions by the outbreaks they risk in this game.
+	WinMode AdvisorMode = "win"
+	// ConserveMode scores actions by what they risk costing the campaign,
+	// for when this game is lost and the future months matter more.
+	ConserveMode AdvisorMode = "conserve"
+)
+
+func ParseAdvisorMode(s string) (AdvisorMode, error) {
+	switch mode := AdvisorMode(s); mode {
+	case WinMode, ConserveMode:
+		return mode, nil
+	}
+	return "", fmt.Errorf("Unknown advisor mode %q, expected %v or %v", s, WinMode, ConserveMode)
+}
+
+// campaignCost is what an outbreak in the city costs the campaign, given
+// the panic level it had before the outbreak.
+func (gs GameState) campaignCost(cn CityName, panicked PanicLevel) int {
+	if panicked == Fallen {
+		return 0
+	}
+	cost := PanicCost
+	if panicked+1 == Fallen {
+		cost = FallCost
+	}
+	if gs.GameTurns != nil {
+		for _, player := range gs.GameTurns.PlayerOrder {
+			if player.Location == cn {
+				cost += ScarCost
+			}
+		}
+	}
+	return cost
+}
+
+// Advice is one candidate action and how the next infection phase is
+// expected to go after it.
+type Advice struct {
+	Action               string
+	ExpectedOutbreaks    float64
+	WorstOutbreaks       int
+	ExpectedCampaignCost float64
+	// Cost is what it takes the current player to get there and do it.
+	Cost PlanCost
+}
+
+func (a Advice) score(mode AdvisorMode) float64 {
+	if mode == ConserveMode {
+		return a.ExpectedCampaignCost
+	}
+	return a.ExpectedOutbreaks
+}
+
+// Advise tries treating a cube or quarantining each infected city and
+// ranks the actions by the mode's score, best first. A table that plays for
+// the worst case has actions to win ranked by the most outbreaks any trial
+// saw, with the expected outbreaks breaking ties.
+func (gs GameState) Advise(mode AdvisorMode, worstCase bool, trials int, rng *rand.Rand) ([]Advice, error) {
+	advice := []Advice{}
+	var player *Player
+	if turn, err := gs.GameTurns.CurrentTurn(); err == nil {
+		player = turn.Player
+	}
+	try := func(action string, cost PlanCost, apply func(*GameState) error) error {
+		plan, err := gs.Clone()
+		if err != nil {
+			return err
+		}
+		if err := apply(plan); err != nil {
+			return err
+		}
+		outcomes, err := plan.SimulateInfectPhase(trials, rng)
+		if err != nil {
+			return err
+		}
+		advice = append(advice, Advice{
+			Action:               action,
+			ExpectedOutbreaks:    outcomes.ExpectedOutbreaks(),
+			WorstOutbreaks:       outcomes.WorstOutbreaks(),
+			ExpectedCampaignCost: outcomes.ExpectedCampaignCost(),
+			Cost:                 cost,
+		})
+		return nil
+	}
+	if err := try("nothing", PlanCost{Turn: gs.GameTurns.CurTurn + 1}, func(*GameState) error { return nil }); err != nil {
+		return nil, err
+	}
+	for _, city := range *gs.Cities {
+		if city.NumInfections == 0 {
+			continue
+		}
+		name := city.Name
+		if err := try(fmt.Sprintf("treat %v", name), gs.actionCost(player, "treat", name), func(plan *GameState) error {
+			treated, err := plan.GetCity(name)
+			if err != nil {
+				return err
+			}
+			treated.SetInfections(treated.NumInfections - 1)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		if city.Quarantined || city.Unexplored || gs.QuarantineMarkersLeft() == 0 {
+			continue
+		}
+		if err := try(fmt.Sprintf("quarantine %v", name), gs.actionCost(player, "quarantine", name), func(plan *GameState) error {
+			return plan.Quarantine(name)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	sort.Stable(byAdvice{advice, mode, worstCase})
+	return advice, nil
+}
+
+// actionCost is the fastest way for the player to reach the city and then
+// take one action of the given kind there.
+func (gs GameState) actionCost(player *Player, kind string, city CityName) PlanCost {
+	cost := PlanCost{}
+	if player == nil {
+		cost.add(kind, 1)
+		cost.Turn = gs.GameTurns.CurTurn + 1
+		return cost
+	}
+	if player.Location != city {
+		if fastest, _, err := gs.PlanRoutes(player, player.Location, city); err == nil {
+			cost = gs.Cost(player, fastest)
+		}
+	}
+	cost.add(kind, 1)
+	cost.Turn = gs.CompletionTurn(player, cost.Actions)
+	return cost
+}
+
+type byAdvice struct {
+	advice    []Advice
+	mode      AdvisorMode
+	worstCase bool
+}
+
+func (b byAdvice) Len() int      { return len(b.advice) }
+func (b byAdvice) Swap(i, j int) { b.advice[i], b.advice[j] = b.advice[j], b.advice[i] }
+func (b byAdvice) Less(i, j int) bool {
+	a, c := b.advice[i], b.advice[j]
+	if b.worstCase && b.mode == WinMode && a.WorstOutbreaks != c.WorstOutbreaks {
+		return a.WorstOutbreaks < c.WorstOutbreaks
+	}
+	return a.score(b.mode) < c.score(b.mode)
+}
@@ -0,0 +1,66 @@
+package pandemic
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestAdviseConserve(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", NumInfections: 3},
+		{Name: "b", NumInfections: 3, PanicLevel: Collapsing},
+		{Name: "c"},
+	})
+	cityDeck, err := cities.GenerateCityDeck(1, nil, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cityDeck.DrawEpidemic()
+	deck := NewInfectionDeck(cities.CityNames())
+	deck.Striations = []Set{
+		Set{}.Add(CityName("a")).Add(CityName("b")),
+		Set{}.Add(CityName("c")),
+	}
+	gs := GameState{
+		Cities:        &cities,
+		CityDeck:      &cityDeck,
+		InfectionDeck: deck,
+		InfectionRate: 1,
+		GameTurns:     InitGameTurns(),
+	}
+	rng := rand.New(rand.NewSource(1))
+
+	advice, err := gs.Advise(ConserveMode, false, 100, rng)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// b would fall, so protecting it matters more than protecting a
+	if best := advice[0].Action; best != "treat b" && best != "quarantine b" {
+		t.Fatalf("Expected to protect b first, got %+v", advice)
+	}
+	for _, a := range advice {
+		if a.Action == "nothing" && a.ExpectedCampaignCost <= advice[0].ExpectedCampaignCost {
+			t.Fatalf("Doing nothing should cost more than protecting b, got %+v", advice)
+		}
+	}
+
+	if _, err := ParseAdvisorMode("panic"); err == nil {
+		t.Fatal("Expected an unknown mode to be rejected")
+	}
+}
+
+func TestAdviseWorstCase(t *testing.T) {
+	advice := []Advice{
+		{Action: "steady", ExpectedOutbreaks: 0.5, WorstOutbreaks: 1},
+		{Action: "gamble", ExpectedOutbreaks: 0.2, WorstOutbreaks: 3},
+	}
+	sort.Stable(byAdvice{advice, WinMode, false})
+	if advice[0].Action != "gamble" {
+		t.Fatalf("Expected the fewest expected outbreaks first, got %+v", advice)
+	}
+	sort.Stable(byAdvice{advice, WinMode, true})
+	if advice[0].Action != "steady" {
+		t.Fatalf("Expected the mildest worst case first, got %+v", advice)
+	}
+}
@@ -0,0 +1,107 @@
+package pandemic
+
+import (
+	"fmt"
+)
+
+// ImpossibleEpidemicError is returned when an epidemic is entered at a point
+// in the city deck where none of the possible pile layouts has one left,
+// e.g. a second epidemic in a pile that already had its epidemic. This
+// almost always means an earlier city card draw was never entered.
+type ImpossibleEpidemicError struct {
+	Draw     int // counting from 1, not including the starting hands
+	Position PilePosition
+}
+
+func (e *ImpossibleEpidemicError) Error() string {
+	pos := e.Position
+	return fmt.Sprintf("Draw %v of the city deck can't be an epidemic: it is card %v of pile %v, which already had its epidemic",
+		e.Draw, rangeString(pos.MinDepth+1, pos.MaxDepth+1), rangeString(pos.MinPile+1, pos.MaxPile+1))
+}
+
+// CanDrawEpidemic checks that an epidemic could be the next card of the
+// city deck without changing the deck.
+func (c *CityDeck) CanDrawEpidemic() error {
+	if drawn := c.EpidemicsDrawn(); drawn >= c.NumEpidemics() {
+		return fmt.Errorf("Already drawn %v epidemics this game, there shouldn't be any more", drawn)
+	}
+	index := c.probabilityIndex()
+	model := c.ProbabilityModel
+	if len(model.Scenarios) > 0 && model.EpidemicProbabilityAt(index) == 0.0 {
+		return &ImpossibleEpidemicError{Draw: index + 1, Position: c.PilePosition()}
+	}
+	return nil
+}
+
+// AuditEntry is one card drawn from the city deck, with the piles it could
+// have come from given the epidemics drawn since.
+type AuditEntry struct {
+	Draw    int
+	Card    CardName
+	MinPile int // zero-indexed
+	MaxPile int
+}
+
+// Audit lists the city deck draws in order, after the starting hands, so
+// that a missed or misplaced entry can be found. Unknown draws are not
+// listed since their place in the order was never recorded, so the piles
+// of later cards may be off by as many.
+func (c *CityDeck) Audit() []AuditEntry {
+	entries := []AuditEntry{}
+	for i, card := range c.Drawn[len(c.StartCities):] {
+		pos := c.ProbabilityModel.PilePosition(i)
+		entries = append(entries, AuditEntry{Draw: i + 1, Card: card.Name(), MinPile: pos.MinPile, MaxPile: pos.MaxPile})
+	}
+	return entries
+}
+
+func rangeString(min, max int) string {
+	if min == max {
+		return fmt.Sprintf("%v", min)
+	}
+	return fmt.Sprintf("%v-%v", min, max)
+}
+
+// BoardCheck lists what the physical game should show at the end of an
+// infection step, so a missed or doubled entry is caught at the table: the
+// size of each pile, the markers, and the cubes on every city that changed
+// this turn.
+func (gs GameState) BoardCheck() []string {
+	checks := []string{
+		fmt.Sprintf("Infection discard pile: %v cards", gs.InfectionDeck.DrawnCount()),
+		fmt.Sprintf("Infection deck: %v cards", gs.InfectionDeck.Size()),
+		fmt.Sprintf("City deck: %v cards", gs.CityDeck.CardsLeftInDeck()),
+		fmt.Sprintf("Outbreaks: %v, infection rate: %v", gs.Outbreaks, gs.InfectionRate),
+	}
+	for _, cn := range gs.CitiesChangedThisTurn() {
+		city, err := gs.GetCity(cn)
+		if err != nil {
+			continue
+		}
+		check := fmt.Sprintf("%v: %v cubes", cn, city.NumInfections)
+		if city.Quarantined {
+			check += ", quarantined"
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// CitiesChangedThisTurn lists the cities the event log has something for
+// this turn, such as an infection or a treatment, in the order they first
+// came up.
+func (gs GameState) CitiesChangedThisTurn() []CityName {
+	cities := []CityName{}
+	if gs.Log == nil || gs.GameTurns == nil {
+		return cities
+	}
+	seen := map[CityName]bool{}
+	for _, event := range gs.Log.Events {
+		if event.Turn != gs.GameTurns.CurTurn || event.City == "" || seen[event.City] {
+			continue
+		}
+		seen[event.City] = true
+		cities = append(cities, event.City)
+	}
+	return cities
+}
@@ -0,0 +1,42 @@
+package pandemic
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBoardCheck(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", Disease: Yellow.Type, NumInfections: 3, Neighbors: []string{"b"}},
+		{Name: "b", Disease: Yellow.Type, Neighbors: []string{"a"}},
+		{Name: "c", Disease: Yellow.Type},
+		{Name: "d", Disease: Yellow.Type},
+	})
+	cityDeck, err := cities.GenerateCityDeck(1, nil, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := &GameState{
+		Cities:           &cities,
+		CityDeck:         &cityDeck,
+		InfectionDeck:    NewInfectionDeck(cities.CityNames()),
+		InfectionRate:    2,
+		GameTurns:        InitGameTurns(&Player{HumanName: "p1"}, &Player{HumanName: "p2"}),
+		Log:              &EventLog{},
+		ResolveOutbreaks: true,
+	}
+	if _, err := gs.Infect("a"); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"Infection discard pile: 1 cards",
+		"Infection deck: 3 cards",
+		"City deck: 5 cards",
+		"Outbreaks: 1, infection rate: 2",
+		"a: 3 cubes",
+		"b: 1 cubes",
+	}
+	if checks := gs.BoardCheck(); !reflect.DeepEqual(checks, expected) {
+		t.Fatalf("Expected %q, got %q", expected, checks)
+	}
+}
@@ -0,0 +1,85 @@
+package pandemic
+
+import (
+	"fmt"
+)
+
+// A Change is something the engine did to the game that the rest of the
+// tracker may want to react to. Subscribers switch on the concrete type.
+type Change interface {
+	change()
+}
+
+// CityInfected is published for every infection that placed cubes,
+// including the ones spread by outbreaks and epidemics.
+type CityInfected struct {
+	City     CityName
+	Cubes    int
+	Outbreak bool
+}
+
+// EpidemicDrawn is published once the epidemic's bottom card is pulled,
+// before its cubes are placed.
+type EpidemicDrawn struct {
+	City CityName
+}
+
+// OutbreakChained is published once an outbreak and everything it set off
+// is resolved.
+type OutbreakChained struct {
+	Chain OutbreakChain
+}
+
+type DiseaseCured struct {
+	Disease DiseaseType
+}
+
+// CityTreated is published when cubes are taken off a city, e.g. by the
+// Medic walking into a city of a cured disease.
+type CityTreated struct {
+	City  CityName
+	Cubes int
+}
+
+// GameChanged is published for every other change: each entry written to
+// the event log, and the changes that don't write one, such as cards
+// changing hands or an action being counted. What describes the change.
+type GameChanged struct {
+	What string
+}
+
+func (CityInfected) change()    {}
+func (EpidemicDrawn) change()   {}
+func (OutbreakChained) change() {}
+func (DiseaseCured) change()    {}
+func (CityTreated) change()     {}
+func (GameChanged) change()     {}
+
+// EventBus passes the game's changes on to its subscribers, in the order
+// they subscribed. It isn't saved, so clones, such as the ones simulations
+// play out, change quietly.
+type EventBus struct {
+	subscribers []func(Change)
+}
+
+// Subscribe calls fn with every change made to the game from now on.
+func (gs *GameState) Subscribe(fn func(Change)) {
+	if gs.bus == nil {
+		gs.bus = &EventBus{}
+	}
+	gs.bus.subscribers = append(gs.bus.subscribers, fn)
+}
+
+// changed publishes a GameChanged.
+func (gs GameState) changed(format string, args ...interface{}) {
+	gs.publish(GameChanged{What: fmt.Sprintf(format, args...)})
+}
+
+func (gs GameState) publish(change Change) {
+	if gs.bus == nil {
+		return
+	}
+	for _, fn := range gs.bus.subscribers {
+		fn(change)
+	}
+}
@@ -0,0 +1,70 @@
+package pandemic
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEventBus(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", Disease: Yellow.Type, NumInfections: 3, Neighbors: []string{"b"}},
+		{Name: "b", Disease: Yellow.Type, Neighbors: []string{"a"}},
+		{Name: "c", Disease: Yellow.Type},
+	})
+	gs := &GameState{
+		Cities:           &cities,
+		DiseaseData:      []DiseaseData{Yellow},
+		InfectionDeck:    NewInfectionDeck(cities.CityNames()),
+		GameTurns:        InitGameTurns(&Player{HumanName: "p1"}, &Player{HumanName: "p2"}),
+		ResolveOutbreaks: true,
+	}
+	changes, other := []Change{}, []Change{}
+	gs.Subscribe(func(change Change) {
+		if _, ok := change.(GameChanged); ok {
+			other = append(other, change)
+			return
+		}
+		changes = append(changes, change)
+	})
+
+	chain, err := gs.Infect("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.Cure(Yellow.Type); err != nil {
+		t.Fatal(err)
+	}
+	expected := []Change{
+		CityInfected{City: "a", Cubes: 1, Outbreak: true},
+		CityInfected{City: "b", Cubes: 1},
+		OutbreakChained{Chain: chain},
+		DiseaseCured{Disease: Yellow.Type},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, changes)
+	}
+	if len(other) == 0 || other[0] != (GameChanged{What: "a was drawn from the infection deck"}) {
+		t.Fatalf("Expected the infection card drawn to be published, got %+v", other)
+	}
+
+	// changes with nothing of their own to publish are still heard of
+	before := len(other)
+	if err := gs.TakeAction("treat"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.Quarantine("c"); err != nil {
+		t.Fatal(err)
+	}
+	if len(other) != before+2 {
+		t.Fatalf("Expected the action and quarantine to be published, got %+v", other[before:])
+	}
+
+	clone, err := gs.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone.Infect("b")
+	if len(changes) != len(expected) {
+		t.Fatalf("Changes to a clone should not reach the game's subscribers, got %+v", changes[len(expected):])
+	}
+}
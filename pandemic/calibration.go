@@ -0,0 +1,144 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+)
+
+// CalibrationBins splits predictions into tenths by their probability.
+const CalibrationBins = 10
+
+const (
+	InfectionPrediction = "infection" // the next infection card is this city
+	EpidemicPrediction  = "epidemic"  // the next city card is an epidemic
+)
+
+// Calibration tallies the tracker's predictions against what was drawn, to
+// check that things given a 30% chance happen about 30% of the time. It
+// keeps nothing but counts, so it can be shared without saying anything
+// about the games or the people playing them.
+type Calibration struct {
+	Kinds map[string][]CalibrationBin `json:"kinds"`
+}
+
+type CalibrationBin struct {
+	Predictions  int     `json:"predictions"`
+	PredictedSum float64 `json:"predicted_sum"`
+	Hits         int     `json:"hits"`
+}
+
+func (c *Calibration) Record(kind string, predicted float64, hit bool) {
+	bins := c.bins(kind)
+	// allow for rounding so that an even 20% lands in the 20% bin
+	bin := int(predicted*CalibrationBins + 1e-9)
+	if bin >= CalibrationBins {
+		bin = CalibrationBins - 1
+	}
+	if bin < 0 {
+		bin = 0
+	}
+	bins[bin].Predictions++
+	bins[bin].PredictedSum += predicted
+	if hit {
+		bins[bin].Hits++
+	}
+}
+
+func (c *Calibration) bins(kind string) []CalibrationBin {
+	if c.Kinds == nil {
+		c.Kinds = map[string][]CalibrationBin{}
+	}
+	bins, ok := c.Kinds[kind]
+	if !ok {
+		bins = make([]CalibrationBin, CalibrationBins)
+		c.Kinds[kind] = bins
+	}
+	return bins
+}
+
+func (c *Calibration) Predictions() int {
+	var total int
+	for _, bins := range c.Kinds {
+		for _, bin := range bins {
+			total += bin.Predictions
+		}
+	}
+	return total
+}
+
+// RecordInfection records, before the card is drawn, the chance the tracker
+// gave every card in the infection deck of being the next one.
+func (gs GameState) RecordInfection(c *Calibration, drawn CityName) {
+	for _, card := range gs.InfectionDeck.Cards() {
+		if card.Location != InStriation {
+			continue
+		}
+		c.Record(InfectionPrediction, gs.InfectionDeck.ProbabilityOfDrawing(card.City, 1), card.City == drawn)
+	}
+}
+
+// RecordCityDraw records, before the card is drawn, the chance the tracker
+// gave the next city card of being an epidemic.
+func (gs GameState) RecordCityDraw(c *Calibration, epidemic bool) {
+	if len(gs.CityDeck.ProbabilityModel.Scenarios) == 0 {
+		return
+	}
+	c.Record(EpidemicPrediction, gs.CityDeck.EpidemicAnalysis().FirstCardProbability, epidemic)
+}
+
+// LoadCalibration reads the tallies kept at the path, starting afresh if
+// there are none yet.
+func LoadCalibration(file string) (*Calibration, error) {
+	c := &Calibration{}
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, json.Unmarshal(data, c)
+}
+
+func (c *Calibration) Save(file string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0644)
+}
+
+// Add folds the other tallies into these.
+func (c *Calibration) Add(other *Calibration) {
+	for kind, bins := range other.Kinds {
+		for i, bin := range bins {
+			if bin.Predictions == 0 {
+				continue
+			}
+			mine := c.bins(kind)
+			mine[i].Predictions += bin.Predictions
+			mine[i].PredictedSum += bin.PredictedSum
+			mine[i].Hits += bin.Hits
+		}
+	}
+}
+
+// Subtract takes the other tallies back out of these, e.g. once they've
+// been sent, leaving whatever was recorded since.
+func (c *Calibration) Subtract(other *Calibration) {
+	for kind, bins := range other.Kinds {
+		for i, bin := range bins {
+			if bin.Predictions == 0 {
+				continue
+			}
+			mine := c.bins(kind)
+			mine[i].Predictions -= bin.Predictions
+			mine[i].PredictedSum -= bin.PredictedSum
+			mine[i].Hits -= bin.Hits
+			if mine[i].Predictions <= 0 {
+				mine[i] = CalibrationBin{}
+			}
+		}
+	}
+}
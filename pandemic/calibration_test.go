@@ -0,0 +1,25 @@
+package pandemic
+
+import (
+	"testing"
+)
+
+func TestCalibrationSubtract(t *testing.T) {
+	sent := &Calibration{}
+	sent.Record("infection", 0.25, true)
+	sent.Record("epidemic", 0.5, false)
+
+	// a prediction tallied while the others were being sent is kept
+	current := &Calibration{}
+	current.Add(sent)
+	current.Record("infection", 0.25, false)
+	current.Subtract(sent)
+
+	if current.Predictions() != 1 {
+		t.Fatalf("Expected the one prediction tallied since the send, got %v", current.Predictions())
+	}
+	bin := current.Kinds["infection"][2]
+	if bin.Predictions != 1 || bin.Hits != 0 {
+		t.Fatalf("Expected one miss left in the infection tallies, got %+v", bin)
+	}
+}
@@ -0,0 +1,295 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+)
+
+const FinalMonth = "dec"
+
+// A Campaign retains the result of every game played across the Legacy year
+// so that the final score can be tallied once December is done.
+type Campaign struct {
+	Name    string         `json:"name"`
+	Months  []*MonthResult `json:"months"`
+	Scoring ScoringRules   `json:"scoring"`
+	// Objectives are edited by hand as the Legacy deck reveals them.
+	Objectives []*Objective `json:"objectives,omitempty"`
+	// Nicknames are what the group calls cities, e.g. "HOME".
+	Nicknames map[CityName]string `json:"nicknames,omitempty"`
+	// InfectionCards lists the cities whose infection cards the Legacy deck
+	// has added to or removed from the infection deck.
+	InfectionCards []*InfectionCardChange `json:"infection_cards,omitempty"`
+	// Config holds per-campaign overrides of the user's config file.
+	Config json.RawMessage `json:"config,omitempty"`
+	// Upgrades are the end of game upgrades the group has chosen.
+	Upgrades []*Upgrade `json:"upgrades,omitempty"`
+	// Reminders are told to a player at the start of their turn, keyed by
+	// the player's name or their character's type, e.g. an upgrade that
+	// grants an extra action.
+	Reminders map[string][]string `json:"reminders,omitempty"`
+
+	file string
+}
+
+type MonthResult struct {
+	Month        string    `json:"month"`
+	Attempt      int       `json:"attempt"`
+	Won          bool      `json:"won"`
+	Outbreaks    int       `json:"outbreaks"`
+	FallenCities int       `json:"fallen_cities"`
+	Bonus        int       `json:"bonus,omitempty"`
+	RecordedAt   time.Time `json:"recorded_at"`
+	// Fallen names the cities fallen by the end of the game. Results
+	// recorded before the names were kept only have FallenCities.
+	Fallen []CityName `json:"fallen,omitempty"`
+}
+
+type Upgrade struct {
+	Month       string `json:"month"`
+	Attempt     int    `json:"attempt"`
+	Description string `json:"description"`
+}
+
+type Objective struct {
+	Description string `json:"description"`
+	Complete    bool   `json:"complete"`
+}
+
+// ScoringRules are the weights used to tally the end of year score. They
+// are stored in the campaign file so they can be adjusted by hand.
+type ScoringRules struct {
+	WonGame          int `json:"won_game"`
+	LostGame         int `json:"lost_game"`
+	WonFinalMonth    int `json:"won_final_month"`
+	PerFallenCity    int `json:"per_fallen_city"`
+	SecondAttemptWin int `json:"second_attempt_win"`
+}
+
+var DefaultScoringRules = ScoringRules{
+	WonGame:          50,
+	LostGame:         0,
+	WonFinalMonth:    750,
+	PerFallenCity:    -25,
+	SecondAttemptWin: -25,
+}
+
+type CampaignScore struct {
+	Lines []ScoreLine
+	Total int
+}
+
+type ScoreLine struct {
+	Description string
+	Points      int
+}
+
+func NewCampaign(file string) *Campaign {
+	return &Campaign{
+		Months:  []*MonthResult{},
+		Scoring: DefaultScoringRules,
+		file:    file,
+	}
+}
+
+// LoadCampaign reads the campaign at the given path. A missing file results
+// in a new, empty campaign that will be written to that path when saved.
+func LoadCampaign(file string) (*Campaign, error) {
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return NewCampaign(file), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	campaign := NewCampaign(file)
+	err = json.Unmarshal(data, campaign)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid campaign file at %v: %v", file, err)
+	}
+	return campaign, nil
+}
+
+// Restore replaces everything in the campaign with the campaign file's
+// contents in data, e.g. one brought over from another machine. It is still
+// saved to its own path.
+func (c *Campaign) Restore(data []byte) error {
+	restored := NewCampaign(c.file)
+	if err := json.Unmarshal(data, restored); err != nil {
+		return fmt.Errorf("Invalid campaign file: %v", err)
+	}
+	*c = *restored
+	return nil
+}
+
+// Backup copies the campaign file as it is on disk beside itself, stamped
+// with the time, and returns the copy's path. A campaign that was never
+// saved has nothing to back up and returns "".
+func (c *Campaign) Backup(now time.Time) (string, error) {
+	data, err := ioutil.ReadFile(c.file)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	backup := fmt.Sprintf("%v.%v.bak", c.file, now.Format("20060102-150405"))
+	return backup, ioutil.WriteFile(backup, data, 0644)
+}
+
+func (c *Campaign) Save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.file, data, 0644)
+}
+
+// ParseMonth splits a game name such as "mar2" or "march-attempt-2" into
+// the month and the attempt number.
+func ParseMonth(gameName string) (string, int) {
+	if month, attempt, ok := parseLongGameName(gameName); ok {
+		return month, attempt
+	}
+	if strings.HasSuffix(gameName, "2") {
+		return strings.TrimSuffix(gameName, "2"), 2
+	}
+	return gameName, 1
+}
+
+// RecordResult stores the outcome of the given game, replacing any result
+// previously recorded for the same month and attempt.
+func (c *Campaign) RecordResult(gs *GameState, won bool) *MonthResult {
+	month, attempt := ParseMonth(gs.GameName)
+	result := &MonthResult{
+		Month:        month,
+		Attempt:      attempt,
+		Won:          won,
+		Outbreaks:    gs.Outbreaks,
+		FallenCities: gs.Cities.CountFallen(),
+		RecordedAt:   time.Now(),
+		Fallen:       gs.Cities.Fallen(),
+	}
+	for i, existing := range c.Months {
+		if existing.Month == month && existing.Attempt == attempt {
+			result.Bonus = existing.Bonus
+			c.Months[i] = result
+			return result
+		}
+	}
+	c.Months = append(c.Months, result)
+	return result
+}
+
+// ResultsSince returns the results recorded after the given time, such as
+// the games played in the last session.
+func (c *Campaign) ResultsSince(since time.Time) []*MonthResult {
+	results := []*MonthResult{}
+	for _, result := range c.Months {
+		if result.RecordedAt.After(since) {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+func (c *Campaign) OpenObjectives() []*Objective {
+	open := []*Objective{}
+	for _, objective := range c.Objectives {
+		if !objective.Complete {
+			open = append(open, objective)
+		}
+	}
+	return open
+}
+
+// SetNickname names a city for the rest of the campaign. An empty nickname
+// removes it.
+func (c *Campaign) SetNickname(cn CityName, nickname string) {
+	if nickname == "" {
+		delete(c.Nicknames, cn)
+		return
+	}
+	if c.Nicknames == nil {
+		c.Nicknames = map[CityName]string{}
+	}
+	c.Nicknames[cn] = nickname
+}
+
+// RemindersFor lists what to remind the player of as their turn starts:
+// their role's ability, their character's scars, and the campaign's
+// reminders for their character type and for them by name.
+func (c *Campaign) RemindersFor(player *Player) []string {
+	reminders := []string{}
+	if reminder := player.Role().Reminder(); reminder != "" {
+		reminders = append(reminders, reminder)
+	}
+	if player.Character != nil {
+		for _, scar := range player.Character.Scars {
+			reminders = append(reminders, fmt.Sprintf("Scar: %v", scar))
+		}
+		reminders = append(reminders, c.Reminders[string(player.Character.Type)]...)
+	}
+	return append(reminders, c.Reminders[player.HumanName]...)
+}
+
+// Complete is true once the final month of the campaign has been played
+// for the last time: either it was won, or both attempts were used.
+func (c *Campaign) Complete() bool {
+	for _, result := range c.Months {
+		if result.Month == FinalMonth && (result.Won || result.Attempt == 2) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Campaign) FinalScore() CampaignScore {
+	score := CampaignScore{}
+	add := func(points int, format string, args ...interface{}) {
+		score.Lines = append(score.Lines, ScoreLine{fmt.Sprintf(format, args...), points})
+		score.Total += points
+	}
+	// a city that fell in any game of the campaign stays fallen, even if a
+	// later game's result doesn't list it
+	fallen := Set{}
+	unnamed := 0
+	for _, result := range c.Months {
+		name := result.Month
+		if result.Attempt == 2 {
+			name += " (second attempt)"
+		}
+		if result.Won {
+			add(c.Scoring.WonGame, "Won %v", name)
+			if result.Attempt == 2 {
+				add(c.Scoring.SecondAttemptWin, "Needed a second attempt for %v", result.Month)
+			}
+			if result.Month == FinalMonth {
+				add(c.Scoring.WonFinalMonth, "Won the final month")
+			}
+		} else {
+			add(c.Scoring.LostGame, "Lost %v", name)
+		}
+		if result.Bonus != 0 {
+			add(result.Bonus, "Bonus for %v", name)
+		}
+		for _, city := range result.Fallen {
+			fallen.Add(city)
+		}
+		if len(result.Fallen) == 0 && result.FallenCities > unnamed {
+			unnamed = result.FallenCities
+		}
+	}
+	count := fallen.Size()
+	if unnamed > count {
+		count = unnamed
+	}
+	if count > 0 {
+		add(c.Scoring.PerFallenCity*count, "%v fallen cities at the end of the campaign", count)
+	}
+	return score
+}
@@ -0,0 +1,82 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const CampaignStoreFile = "campaigns.json"
+
+// A CampaignStore keeps several campaigns side by side, e.g. for two groups
+// playing the same season. Each campaign has its own directory holding its
+// campaign file and its saves, so results, config overrides, Legacy changes
+// and city stats never mix.
+type CampaignStore struct {
+	Active    string   `json:"active"`
+	Campaigns []string `json:"campaigns"`
+
+	file string
+}
+
+// LoadCampaignStore reads the store in the directory. A missing file
+// results in an empty store, and the single campaign file used before.
+func LoadCampaignStore(dir string) (*CampaignStore, error) {
+	file := filepath.Join(dir, CampaignStoreFile)
+	store := &CampaignStore{Campaigns: []string{}, file: file}
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("Invalid campaign store at %v: %v", file, err)
+	}
+	return store, nil
+}
+
+func (s *CampaignStore) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.file, data, 0644)
+}
+
+func (s *CampaignStore) Has(name string) bool {
+	for _, campaign := range s.Campaigns {
+		if campaign == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Dir is the directory the campaign's files are kept in.
+func (s *CampaignStore) Dir(name string) string {
+	return filepath.Join(filepath.Dir(s.file), "campaigns", name)
+}
+
+func (s *CampaignStore) CampaignFile(name string) string {
+	return filepath.Join(s.Dir(name), "campaign.json")
+}
+
+// Switch makes the named campaign the active one, starting it if it is new.
+func (s *CampaignStore) Switch(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("%q can't be used as a campaign name", name)
+	}
+	if !s.Has(name) {
+		if err := os.MkdirAll(s.Dir(name), 0755); err != nil {
+			return fmt.Errorf("Could not start campaign %v: %v", name, err)
+		}
+		s.Campaigns = append(s.Campaigns, name)
+	}
+	s.Active = name
+	return s.Save()
+}
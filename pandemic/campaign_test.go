@@ -0,0 +1,363 @@
+package pandemic
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func tempFile(t *testing.T, name string) (string, func()) {
+	dir, err := ioutil.TempDir("", "pandemic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, name), func() { os.RemoveAll(dir) }
+}
+
+func TestParseMonth(t *testing.T) {
+	if month, attempt := ParseMonth("mar2"); month != "mar" || attempt != 2 {
+		t.Fatalf("Expected mar attempt 2, got %v attempt %v", month, attempt)
+	}
+	if month, attempt := ParseMonth("dec"); month != "dec" || attempt != 1 {
+		t.Fatalf("Expected dec attempt 1, got %v attempt %v", month, attempt)
+	}
+}
+
+func TestFinalScore(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", PanicLevel: Fallen},
+		{Name: "b", PanicLevel: Rioting2},
+	})
+	campaign := NewCampaign("campaign.json")
+	campaign.RecordResult(&GameState{GameName: "nov", Cities: &cities}, true)
+	campaign.RecordResult(&GameState{GameName: "dec", Cities: &cities}, false)
+	if campaign.Complete() {
+		t.Fatal("Campaign should not be complete with a second attempt at december left")
+	}
+	campaign.RecordResult(&GameState{GameName: "dec2", Cities: &cities}, true)
+	if !campaign.Complete() {
+		t.Fatal("Campaign should be complete after winning december")
+	}
+
+	// 50 (nov) + 0 (dec) + 50 - 25 + 750 (dec2) - 25 (one fallen city)
+	score := campaign.FinalScore()
+	if score.Total != 800 {
+		t.Fatalf("Expected a final score of 800, got %v: %+v", score.Total, score.Lines)
+	}
+
+	// b fell by the second attempt at december, and a, fallen in november, still counts
+	cities[0].PanicLevel = Nothing
+	cities[1].PanicLevel = Fallen
+	campaign.RecordResult(&GameState{GameName: "dec2", Cities: &cities}, true)
+	if score := campaign.FinalScore(); score.Total != 775 {
+		t.Fatalf("Expected both fallen cities to count, got %v: %+v", score.Total, score.Lines)
+	}
+}
+
+func TestCampaignRoundTrip(t *testing.T) {
+	file, cleanup := tempFile(t, "campaign.json")
+	defer cleanup()
+	campaign, err := LoadCampaign(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cities := Cities([]*City{})
+	campaign.RecordResult(&GameState{GameName: "jan", Cities: &cities}, true)
+	if err := campaign.Save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadCampaign(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Months) != 1 || !loaded.Months[0].Won || loaded.Scoring != DefaultScoringRules {
+		t.Fatalf("Campaign did not survive a round trip: %+v", loaded)
+	}
+}
+
+func TestCampaignRestore(t *testing.T) {
+	file, cleanup := tempFile(t, "campaign.json")
+	defer cleanup()
+	campaign := NewCampaign(file)
+	campaign.Reminders = map[string][]string{"Will": {"extra action"}}
+	if err := campaign.Restore([]byte(`{"name": "tuesdays", "months": [{"month": "jan", "won": true}]}`)); err != nil {
+		t.Fatal(err)
+	}
+	if campaign.Name != "tuesdays" || len(campaign.Months) != 1 || campaign.Reminders != nil || campaign.Scoring != DefaultScoringRules {
+		t.Fatalf("Expected the campaign to be replaced, got %+v", campaign)
+	}
+	if err := campaign.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err := LoadCampaign(file); err != nil || loaded.Name != "tuesdays" {
+		t.Fatalf("Expected the restored campaign to be saved to its own file, got %+v, %v", loaded, err)
+	}
+	if err := campaign.Restore([]byte("{")); err == nil {
+		t.Fatal("Should not restore an invalid campaign file")
+	}
+}
+
+func TestResultsSince(t *testing.T) {
+	cities := Cities([]*City{})
+	campaign := NewCampaign("campaign.json")
+	campaign.RecordResult(&GameState{GameName: "jan", Cities: &cities}, true)
+	campaign.Months[0].RecordedAt = time.Now().Add(-30 * 24 * time.Hour)
+	campaign.RecordResult(&GameState{GameName: "feb", Cities: &cities}, false)
+
+	results := campaign.ResultsSince(time.Now().Add(-7 * 24 * time.Hour))
+	if len(results) != 1 || results[0].Month != "feb" {
+		t.Fatalf("Expected only february in the last week, got %+v", results)
+	}
+}
+
+func TestNextGame(t *testing.T) {
+	cities := Cities([]*City{})
+	campaign := NewCampaign("campaign.json")
+	if name := GameNameFor(campaign.NextGame()); name != "january-attempt-1" {
+		t.Fatalf("Expected the campaign to start in january, got %v", name)
+	}
+	campaign.RecordResult(&GameState{GameName: "january-attempt-1", Cities: &cities}, true)
+	campaign.RecordResult(&GameState{GameName: "feb", Cities: &cities}, false)
+	name := GameNameFor(campaign.NextGame())
+	if name != "february-attempt-2" {
+		t.Fatalf("Expected a second attempt at february, got %v", name)
+	}
+	if month, attempt := ParseMonth(name); month != "feb" || attempt != 2 {
+		t.Fatalf("Expected feb attempt 2, got %v attempt %v", month, attempt)
+	}
+	campaign.RecordResult(&GameState{GameName: name, Cities: &cities}, false)
+	if name := GameNameFor(campaign.NextGame()); name != "march-attempt-1" {
+		t.Fatalf("Expected to move on to march, got %v", name)
+	}
+}
+
+func TestNicknames(t *testing.T) {
+	file, cleanup := tempFile(t, "campaign.json")
+	defer cleanup()
+	campaign := NewCampaign(file)
+	campaign.SetNickname("lagos", "HOME")
+	campaign.SetNickname("cairo", "")
+	if err := campaign.Save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadCampaign(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Nicknames) != 1 || loaded.Nicknames["lagos"] != "HOME" {
+		t.Fatalf("Expected only lagos to be nicknamed, got %v", loaded.Nicknames)
+	}
+	loaded.SetNickname("lagos", "")
+	if len(loaded.Nicknames) != 0 {
+		t.Fatalf("Expected the nickname to be removed, got %v", loaded.Nicknames)
+	}
+}
+
+func TestCampaignBackup(t *testing.T) {
+	file, cleanup := tempFile(t, "campaign.json")
+	defer cleanup()
+	campaign := NewCampaign(file)
+	if backup, err := campaign.Backup(time.Now()); err != nil || backup != "" {
+		t.Fatalf("An unsaved campaign has nothing to back up, got %q, %v", backup, err)
+	}
+	campaign.SetNickname("lagos", "HOME")
+	if err := campaign.Save(); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := campaign.Backup(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := campaign.Restore([]byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := campaign.Save(); err != nil {
+		t.Fatal(err)
+	}
+	kept, err := LoadCampaign(backup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kept.Nicknames["lagos"] != "HOME" {
+		t.Fatalf("Expected the backup to keep the old campaign, got %v", kept.Nicknames)
+	}
+}
+
+func TestCityRecords(t *testing.T) {
+	board := func() Cities {
+		return Cities([]*City{
+			{Name: "a", NumInfections: 3, PanicLevel: Collapsing},
+			{Name: "b"},
+		})
+	}
+	// a falls on its first outbreak in march, and the second one finds it
+	// already fallen
+	marchCities := board()
+	march := GameState{GameName: "march-attempt-1", Cities: &marchCities, Log: &EventLog{}}
+	march.infectCity(marchCities[1], 1)
+	march.infectCity(marchCities[0], 1)
+	march.infectCity(marchCities[0], 1)
+	if marchCities[0].PanicLevel != Fallen {
+		t.Fatalf("Expected a to have fallen, it is %v", marchCities[0].PanicLevel)
+	}
+	janCities := board()
+	jan := GameState{GameName: "jan", Cities: &janCities, Log: &EventLog{}}
+	jan.infectCity(janCities[0], 1)
+
+	records := CityRecords([]*GameState{&march, &jan})
+	a := records["a"]
+	if a.FirstInfected != "jan" || a.Outbreaks != 3 || a.TimesFallen != 2 {
+		t.Fatalf("Expected a to be first infected in jan with 3 outbreaks and 2 falls, got %+v", a)
+	}
+	if b := records["b"]; b.FirstInfected != "march-attempt-1" || b.Outbreaks != 0 {
+		t.Fatalf("Expected b to be infected once in march, got %+v", b)
+	}
+}
+
+func TestCheckInfectionDeck(t *testing.T) {
+	gs := &GameState{GameName: "march-attempt-1", InfectionDeck: NewInfectionDeck([]CityName{"a", "b"})}
+	campaign := NewCampaign("")
+	campaign.InfectionCards = []*InfectionCardChange{
+		{Month: "feb", City: "a", Removed: true},
+		{Month: "mar", City: "c"},
+		{Month: "feb", City: "b", Removed: true},
+		{Month: "mar", City: "b"},
+		{Month: "apr", City: "d"},
+		{Month: "march", City: "e"},
+	}
+	problems := campaign.CheckInfectionDeck(gs)
+	expected := []string{
+		`"march" is not a month, use jan to dec for the change to e`,
+		"a's infection card was removed in feb but is in the deck",
+		"c's infection card was added in mar but is missing from the deck",
+	}
+	if len(problems) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, problems)
+	}
+	for i := range expected {
+		if problems[i] != expected[i] {
+			t.Fatalf("Expected %q, got %q", expected[i], problems[i])
+		}
+	}
+}
+
+func TestCampaignStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pandemic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := LoadCampaignStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"tuesdays", "family", "tuesdays"} {
+		if err := store.Switch(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Switch("../escape"); err == nil {
+		t.Fatal("Campaign names should not leave the store")
+	}
+
+	store, err = LoadCampaignStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.Active != "tuesdays" || len(store.Campaigns) != 2 {
+		t.Fatalf("Expected tuesdays active of 2 campaigns, got %+v", store)
+	}
+	tuesdays, err := LoadCampaign(store.CampaignFile("tuesdays"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tuesdays.Months = append(tuesdays.Months, &MonthResult{Month: "jan", Won: true})
+	if err := tuesdays.Save(); err != nil {
+		t.Fatal(err)
+	}
+	family, err := LoadCampaign(store.CampaignFile("family"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(family.Months) != 0 {
+		t.Fatalf("Expected campaigns to keep their results apart, got %+v", family.Months)
+	}
+}
+
+func TestMonthTransition(t *testing.T) {
+	template := NewGameSettings{
+		Cities: Cities{{Name: "a"}, {Name: "b"}},
+		Players: []*Player{
+			{HumanName: "p1", StartCards: []CardName{"a"}, Character: &Character{Type: Medic}},
+			{HumanName: "p2", StartCards: []CardName{"b"}},
+		},
+		FundedEvents: []*FundedEvent{{Name: "airlift"}},
+	}
+	played := Cities{{Name: "a", PanicLevel: Rioting2, Stickers: []string{"permanent research station"}}, {Name: "b"}}
+	scarred := &Player{HumanName: "p1", Character: &Character{Type: Medic, Scars: []string{"fragile"}}}
+	gs := GameState{GameName: "feb", Cities: &played, GameTurns: InitGameTurns(scarred, &Player{HumanName: "p2"})}
+
+	transition := MonthTransition{
+		Won:          true,
+		Upgrades:     []string{"positive mutation"},
+		Scars:        map[string][]string{"p1": {"reckless"}},
+		Stickers:     map[CityName][]string{"b": {"military base"}},
+		FundedEvents: []FundedEventName{"rapid vaccine deployment"},
+		StartCards:   map[string][]CardName{"p2": {"a", "b"}},
+	}
+	next, err := gs.NextGameSettings(template, transition)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := next.Cities.GetCity("a")
+	b, _ := next.Cities.GetCity("b")
+	if a.PanicLevel != Rioting2 || len(a.Stickers) != 1 || len(b.Stickers) != 1 {
+		t.Fatalf("Expected panic and stickers to carry over, got %+v %+v", a, b)
+	}
+	if scars := next.Players[0].Character.Scars; len(scars) != 2 || scars[1] != "reckless" {
+		t.Fatalf("Expected the old and new scars, got %v", scars)
+	}
+	if len(next.Players[1].StartCards) != 2 || len(next.FundedEvents) != 1 || next.FundedEvents[0].Name != "rapid vaccine deployment" {
+		t.Fatalf("Expected the new start cards and funded events, got %+v", next)
+	}
+	if template.Cities[0].PanicLevel != Nothing || len(template.Players[0].Character.Scars) != 0 {
+		t.Fatal("The template should not change")
+	}
+
+	transition.Scars = map[string][]string{"p2": {"reckless"}}
+	if _, err := gs.NextGameSettings(template, transition); err == nil {
+		t.Fatal("A player without a character cannot be scarred")
+	}
+
+	campaign := NewCampaign("")
+	campaign.RecordTransition(&gs, transition)
+	if len(campaign.Upgrades) != 1 || campaign.Upgrades[0].Month != "feb" || !campaign.Months[0].Won {
+		t.Fatalf("Expected the result and upgrade to be recorded, got %+v", campaign)
+	}
+}
+
+func TestReminders(t *testing.T) {
+	campaign := NewCampaign("")
+	campaign.Reminders = map[string][]string{
+		"Medic": {"Upgrade: the Medic treats neighbors too"},
+		"alice": {"Your upgrade grants +1 action this game"},
+		"bob":   {"Not for alice"},
+	}
+	alice := &Player{HumanName: "alice", Character: &Character{Type: Medic, Scars: []string{"Distraught: 3 actions"}}}
+	expected := []string{
+		MedicRole{}.Reminder(),
+		"Scar: Distraught: 3 actions",
+		"Upgrade: the Medic treats neighbors too",
+		"Your upgrade grants +1 action this game",
+	}
+	if reminders := campaign.RemindersFor(alice); !reflect.DeepEqual(reminders, expected) {
+		t.Fatalf("Expected %v, got %v", expected, reminders)
+	}
+	if reminders := campaign.RemindersFor(&Player{HumanName: "carol"}); len(reminders) != 0 {
+		t.Fatalf("Expected no reminders for a player without a character, got %v", reminders)
+	}
+}
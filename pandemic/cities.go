@@ -37,17 +37,41 @@ func (c CardName) Empty() bool {
 	return string(c) == ""
 }
 
+// CityDeck tracks every player card in the game. Drawn holds every card
+// that has left the deck, in draw order. A drawn card is either in a player's
+// hand, on the player discard pile (Discarded) or out of the game entirely
+// (Removed).
 type CityDeck struct {
-	Drawn            []CityCard
-	All              []CityCard
-	StartCities      []CityCard
+	Drawn       []CityCard
+	All         []CityCard
+	StartCities []CityCard
+	Discarded   []CityCard
+	Removed     []CityCard
+	// UnknownDraws counts cards that were drawn without anyone recording
+	// which card it was. They are still counted as unseen by RemainingCards.
+	UnknownDraws     int
 	ProbabilityModel *cityDeckProbabilityModel
+	// RanOut is set when a card had to be drawn from the empty deck, which
+	// loses the game.
+	RanOut bool `json:",omitempty"`
 }
 
+type CardLocation string
+
+const (
+	InDeck       = CardLocation("deck")
+	InHand       = CardLocation("hand")
+	InDiscard    = CardLocation("discard")
+	OutOfTheGame = CardLocation("removed")
+)
+
 type CityCard struct {
 	CityName        CityName        `json:"city_name,omitempty"`
 	IsEpidemic      bool            `json:"is_epidemic"`
 	FundedEventName FundedEventName `json:"funded_event_name,omitempty"`
+	// ID tells copies of the same card apart. Saves from before IDs have
+	// 0, and their cards are told apart by name.
+	ID int `json:"id,omitempty"`
 }
 
 type City struct {
@@ -58,6 +82,17 @@ type City struct {
 	Neighbors       []string    `json:"neighbors"`
 	NumInfections   int         `json:"num_infections"`
 	Quarantined     bool        `json:"quarantined"`
+	ResearchStation bool        `json:"research_station,omitempty"`
+	// Stickers are the Legacy stickers on the city, e.g. a permanent
+	// research station, carried from game to game.
+	Stickers []string `json:"stickers,omitempty"`
+	// OtherCubes counts the cubes of other diseases that outbreaks next
+	// door spilled onto the city. NumInfections is the city's own disease.
+	OtherCubes map[DiseaseType]int `json:"other_cubes,omitempty"`
+	// Season 2 only
+	SupplyCubes int  `json:"supply_cubes,omitempty"`
+	Haven       bool `json:"haven,omitempty"`
+	Unexplored  bool `json:"unexplored,omitempty"`
 }
 
 type Cities []*City
@@ -77,6 +112,14 @@ func (c CityCard) Name() CardName {
 	return "epidemic"
 }
 
+// sameCard is whether both are the same physical card.
+func (c CityCard) sameCard(other CityCard) bool {
+	if c.ID != 0 && other.ID != 0 {
+		return c.ID == other.ID
+	}
+	return c.Name() == other.Name()
+}
+
 func (c CityCard) IsCity() bool {
 	return !c.CityName.Empty()
 }
@@ -89,15 +132,30 @@ func (c CityCard) IsFundedEvent() bool {
 // TODO: funded events + epidemics should be named for drawing. If the initial hands
 // contain funded events, all cure stats will be wrong.
 func (c Cities) GenerateCityDeck(epidemicCount int, events []*FundedEvent, startCities Set) (CityDeck, error) {
+	return c.GenerateCityDeckWith(epidemicCount, events, startCities, nil)
+}
+
+// GenerateCityDeckWith also shuffles in a second copy of a city's card for
+// each of the copies, as some variants and Legacy cards call for.
+func (c Cities) GenerateCityDeckWith(epidemicCount int, events []*FundedEvent, startCities Set, copies []CityName) (CityDeck, error) {
 	cards := []CityCard{}
 	for _, city := range c {
-		cards = append(cards, CityCard{city.Name, false, ""})
+		cards = append(cards, CityCard{CityName: city.Name})
+	}
+	for _, extra := range copies {
+		if _, err := c.GetCity(extra); err != nil {
+			return CityDeck{}, fmt.Errorf("Cannot add a copy of %v: %v", extra, err)
+		}
+		cards = append(cards, CityCard{CityName: extra})
 	}
 	for i := 0; i < epidemicCount; i++ {
-		cards = append(cards, CityCard{"", true, ""})
+		cards = append(cards, CityCard{IsEpidemic: true})
 	}
 	for _, event := range events {
-		cards = append(cards, CityCard{"", false, event.Name})
+		cards = append(cards, CityCard{FundedEventName: event.Name})
+	}
+	for i := range cards {
+		cards[i].ID = i + 1
 	}
 
 	probModel := generateProbabilityModel(len(cards)-startCities.Size(), epidemicCount)
@@ -164,6 +222,59 @@ func (c Cities) CityNames() []CityName {
 	return names
 }
 
+// Explored returns the cities that have cards in the city and infection
+// decks. Only Season 2 maps have unexplored cities.
+func (c Cities) Explored() Cities {
+	explored := Cities{}
+	for _, city := range c {
+		if !city.Unexplored {
+			explored = append(explored, city)
+		}
+	}
+	return explored
+}
+
+func (c Cities) ResearchStations() []CityName {
+	names := []CityName{}
+	for _, city := range c {
+		if city.ResearchStation {
+			names = append(names, city.Name)
+		}
+	}
+	return names
+}
+
+func (c Cities) CountQuarantined() int {
+	var quarantined int
+	for _, city := range c {
+		if city.Quarantined {
+			quarantined++
+		}
+	}
+	return quarantined
+}
+
+func (c Cities) CountFallen() int {
+	var fallen int
+	for _, city := range c {
+		if city.PanicLevel == Fallen {
+			fallen++
+		}
+	}
+	return fallen
+}
+
+// Fallen names the cities that have fallen.
+func (c Cities) Fallen() []CityName {
+	fallen := []CityName{}
+	for _, city := range c {
+		if city.PanicLevel == Fallen {
+			fallen = append(fallen, city.Name)
+		}
+	}
+	return fallen
+}
+
 func (c *City) Infect() bool {
 	if c.NumInfections == 3 {
 		return true
@@ -172,10 +283,6 @@ func (c *City) Infect() bool {
 	return false
 }
 
-func (c *City) Epidemic() {
-	c.NumInfections = 3
-}
-
 func (c *City) Quarantine() {
 	c.Quarantined = true
 }
@@ -188,6 +295,29 @@ func (c *City) SetInfections(infections int) {
 	c.NumInfections = infections
 }
 
+// Cubes is how many cubes of the disease are on the city.
+func (c *City) Cubes(dt DiseaseType) int {
+	if dt == c.Disease {
+		return c.NumInfections
+	}
+	return c.OtherCubes[dt]
+}
+
+func (c *City) setCubes(dt DiseaseType, cubes int) {
+	if dt == c.Disease {
+		c.SetInfections(cubes)
+		return
+	}
+	if cubes == 0 {
+		delete(c.OtherCubes, dt)
+		return
+	}
+	if c.OtherCubes == nil {
+		c.OtherCubes = map[DiseaseType]int{}
+	}
+	c.OtherCubes[dt] = cubes
+}
+
 func (c CityDeck) Total() int {
 	return len(c.All)
 }
@@ -217,12 +347,13 @@ func (c *CityDeck) EpidemicsDrawn() int {
 }
 
 func (c *CityDeck) ProbabilityOfDrawing(cn CardName) float64 {
-	for _, already := range c.Drawn {
-		if already.Name() == cn {
-			return 0.0
+	var left int
+	for _, card := range c.All {
+		if card.Name() == cn && c.cardLocation(card) == InDeck {
+			left++
 		}
 	}
-	return 1.0 / float64(len(c.All)-len(c.Drawn))
+	return float64(left) / float64(len(c.All)-len(c.Drawn))
 }
 
 // Returns the probability of drawing a particular type. If the given
@@ -233,10 +364,48 @@ func (c *CityDeck) ProbabilityOfDrawingType(dt DiseaseType, cities *Cities) floa
 	return float64(inAll) / (float64(c.RemainingCards()))
 }
 
+// RemainingCards is the number of cards whose whereabouts are unknown:
+// the cards in the deck plus any unknown draws sitting in players' hands.
 func (c *CityDeck) RemainingCards() int {
 	return c.Total() - len(c.Drawn)
 }
 
+// CardsLeftInDeck is the number of cards physically left to draw.
+func (c *CityDeck) CardsLeftInDeck() int {
+	return c.RemainingCards() - c.UnknownDraws
+}
+
+// DrawUnknown records that a card was drawn without knowing which. It can
+// never be an epidemic, since those are always resolved at the table.
+func (c *CityDeck) DrawUnknown() error {
+	if c.CardsLeftInDeck() <= 0 {
+		return fmt.Errorf("There are no cards left in the city deck")
+	}
+	c.ProbabilityModel.DrawCity(c.probabilityIndex())
+	c.UnknownDraws++
+	return nil
+}
+
+// Reveal identifies a card that was previously drawn as unknown.
+func (c *CityDeck) Reveal(cn CardName) (*CityCard, error) {
+	if c.UnknownDraws == 0 {
+		return nil, fmt.Errorf("There are no unknown cards to reveal")
+	}
+	if _, err := c.GetCard(cn); err != nil {
+		return nil, err
+	}
+	card, ok := c.copyIn(cn, InDeck)
+	if !ok {
+		return nil, fmt.Errorf("%v cannot be an unknown card, it is in the %v", cn, c.Location(cn))
+	}
+	if card.IsEpidemic {
+		return nil, fmt.Errorf("An unknown card cannot be an epidemic")
+	}
+	c.Drawn = append(c.Drawn, *card)
+	c.UnknownDraws--
+	return card, nil
+}
+
 func (c *CityDeck) RemainingCardsWith(dt DiseaseType, cities *Cities) int {
 	inAll := 0
 	for _, card := range c.All {
@@ -268,6 +437,127 @@ func (c *CityDeck) RemainingCardsWith(dt DiseaseType, cities *Cities) int {
 	return inAll
 }
 
+// AvailableCardsWith counts the cards of the given disease that could still
+// be used for a cure: those remaining in the deck plus those held in hands.
+func (c *CityDeck) AvailableCardsWith(dt DiseaseType, cities *Cities) int {
+	available := c.RemainingCardsWith(dt, cities)
+	for _, card := range c.Drawn {
+		if !card.IsCity() || c.cardLocation(card) != InHand {
+			continue
+		}
+		city, _ := cities.GetCity(card.CityName)
+		if city.OriginalDisease == dt {
+			available++
+		}
+	}
+	return available
+}
+
+// Location is where the card is. When there are several copies of it, the
+// one nearest to hand wins: a copy in a hand, then in the deck, then on the
+// discard pile.
+func (c *CityDeck) Location(cn CardName) CardLocation {
+	found := OutOfTheGame
+	rank := map[CardLocation]int{OutOfTheGame: 0, InDiscard: 1, InDeck: 2, InHand: 3}
+	known := false
+	for _, card := range c.All {
+		if card.Name() != cn {
+			continue
+		}
+		known = true
+		if loc := c.cardLocation(card); rank[loc] > rank[found] {
+			found = loc
+		}
+	}
+	if !known {
+		return c.cardLocation(CityCard{CityName: CityName(cn)})
+	}
+	return found
+}
+
+// cardLocation is where one copy of a card is.
+func (c *CityDeck) cardLocation(card CityCard) CardLocation {
+	for _, removed := range c.Removed {
+		if removed.sameCard(card) {
+			return OutOfTheGame
+		}
+	}
+	for _, discarded := range c.Discarded {
+		if discarded.sameCard(card) {
+			return InDiscard
+		}
+	}
+	for _, drawn := range c.Drawn {
+		if drawn.sameCard(card) {
+			return InHand
+		}
+	}
+	return InDeck
+}
+
+// copyIn finds a copy of the card at the given location.
+func (c *CityDeck) copyIn(cn CardName, loc CardLocation) (*CityCard, bool) {
+	for _, card := range c.All {
+		if card.Name() == cn && c.cardLocation(card) == loc {
+			return &card, true
+		}
+	}
+	return nil, false
+}
+
+// Discard moves a card from a player's hand onto the player discard pile.
+func (c *CityDeck) Discard(cn CardName) error {
+	card, ok := c.copyIn(cn, InHand)
+	if !ok {
+		return fmt.Errorf("%v cannot be discarded, it is in the %v", cn, c.Location(cn))
+	}
+	return c.discard(*card)
+}
+
+// discard moves the given copy of a card from a player's hand onto the
+// discard pile, leaving any other copy where it is.
+func (c *CityDeck) discard(card CityCard) error {
+	if loc := c.cardLocation(card); loc != InHand {
+		return fmt.Errorf("%v cannot be discarded, it is in the %v", card.Name(), loc)
+	}
+	c.Discarded = append(c.Discarded, card)
+	return nil
+}
+
+// undiscard takes a card back off the discard pile.
+func (c *CityDeck) undiscard(card CityCard) {
+	discarded := []CityCard{}
+	for _, existing := range c.Discarded {
+		if !existing.sameCard(card) {
+			discarded = append(discarded, existing)
+		}
+	}
+	c.Discarded = discarded
+}
+
+// Remove takes a card in a player's hand or on the discard pile out of the
+// game for good.
+func (c *CityDeck) Remove(cn CardName) error {
+	card, ok := c.copyIn(cn, InHand)
+	if !ok {
+		card, ok = c.copyIn(cn, InDiscard)
+	}
+	if !ok {
+		return fmt.Errorf("%v cannot be removed from the game, it is in the %v", cn, c.Location(cn))
+	}
+	return c.remove(*card)
+}
+
+// remove takes the given copy of a card out of the game.
+func (c *CityDeck) remove(card CityCard) error {
+	if loc := c.cardLocation(card); loc != InHand && loc != InDiscard {
+		return fmt.Errorf("%v cannot be removed from the game, it is in the %v", card.Name(), loc)
+	}
+	c.undiscard(card)
+	c.Removed = append(c.Removed, card)
+	return nil
+}
+
 func (c *CityDeck) GetCard(cn CardName) (*CityCard, error) {
 	for _, card := range c.All {
 		if card.Name() == cn {
@@ -285,10 +575,12 @@ func (c *CityDeck) GetCardByPrefix(prefix string) (*CityCard, error) {
 		}
 		card := card
 		if strings.HasPrefix(strings.ToLower(string(card.Name())), strings.ToLower(prefix)) {
-			if ret != nil {
+			if ret != nil && ret.Name() != card.Name() {
 				return nil, fmt.Errorf("'%v' is ambiguous", prefix)
 			}
-			ret = &card
+			if ret == nil {
+				ret = &card
+			}
 		}
 	}
 	if ret == nil {
@@ -298,23 +590,16 @@ func (c *CityDeck) GetCardByPrefix(prefix string) (*CityCard, error) {
 }
 
 func (c *CityDeck) DrawCard(cn CardName) (*CityCard, error) {
-	for _, card := range c.Drawn {
-		if card.Name() == cn {
-			return nil, fmt.Errorf("%v has already been drawn from the city deck", cn)
-		}
-	}
-	var target CityCard
-	for _, card := range c.All {
-		if card.Name() == cn {
-			target = card
-		}
-	}
-	if target.Name() == "" {
+	if _, err := c.GetCard(cn); err != nil {
 		return nil, fmt.Errorf("No card called %v in the city deck", cn)
 	}
+	target, ok := c.copyIn(cn, InDeck)
+	if !ok {
+		return nil, fmt.Errorf("%v has already been drawn from the city deck", cn)
+	}
 	c.ProbabilityModel.DrawCity(c.probabilityIndex())
-	c.Drawn = append(c.Drawn, target)
-	return &target, nil
+	c.Drawn = append(c.Drawn, *target)
+	return target, nil
 }
 
 func (c *CityDeck) GetCity(cn CityName) (*CityCard, error) {
@@ -337,23 +622,27 @@ func (c *CityDeck) NumFundedEvents() int {
 }
 
 func (c *CityDeck) DrawEpidemic() error {
-	totalEpis := c.NumEpidemics()
-	var drawnEpis int
-	for _, card := range c.Drawn {
-		if card.IsEpidemic {
-			drawnEpis++
-		}
-	}
-	if drawnEpis >= totalEpis {
-		return fmt.Errorf("Already drawn %v epidemics this game, there shouldn't be any more", drawnEpis)
+	if err := c.CanDrawEpidemic(); err != nil {
+		return err
 	}
-	c.ProbabilityModel.DrawEpidemic(c.probabilityIndex())
-	c.Drawn = append(c.Drawn, CityCard{"", true, ""})
+	c.drawEpidemic()
 	return nil
 }
 
+// drawEpidemic draws an epidemic without checking that one could be next,
+// for an epidemic entered anyway.
+func (c *CityDeck) drawEpidemic() CityCard {
+	c.ProbabilityModel.DrawEpidemic(c.probabilityIndex())
+	card, ok := c.copyIn("epidemic", InDeck)
+	if !ok {
+		card = &CityCard{IsEpidemic: true}
+	}
+	c.Drawn = append(c.Drawn, *card)
+	return *card
+}
+
 func (c CityDeck) probabilityIndex() int {
-	return len(c.Drawn) - len(c.StartCities)
+	return len(c.Drawn) - len(c.StartCities) + c.UnknownDraws
 }
 
 // The function Pe(x) is the probabiltiy of drawing an epidemic at index x.
@@ -375,6 +664,11 @@ func (c CityDeck) EpidemicAnalysis() EpidemicAnalysis {
 	return c.ProbabilityModel.EpidemicAnalysis(index)
 }
 
+func (c CityDeck) PilePosition() PilePosition {
+	index := c.probabilityIndex()
+	return c.ProbabilityModel.PilePosition(index)
+}
+
 ///////////////////////////////////
 /// City Deck Probability Model ///
 ///////////////////////////////////
@@ -416,6 +710,24 @@ type EpidemicAnalysis struct {
 	ComingDrawsWith0           int
 }
 
+// PilePosition describes where the next city deck draw falls with respect
+// to the epidemic piles the deck was built from. Since more than one scenario
+// may still be possible, the pile, the number of cards already drawn from it
+// and its size are all reported as ranges.
+type PilePosition struct {
+	MinPile  int // zero-indexed
+	MaxPile  int
+	NumPiles int
+	MinDepth int // cards already drawn from the current pile
+	MaxDepth int
+	MinSize  int
+	MaxSize  int
+}
+
+func (p PilePosition) Exhausted() bool {
+	return p.MinPile >= p.NumPiles
+}
+
 // 1 extra is 5 possible scenarios 5!/1!(4!) = 5
 // 2 extra is 10 possible scenarios (5!)/(2!)(3!) = 5*4/2 = 10
 func generateProbabilityModel(cardCount int, epidemics int) cityDeckProbabilityModel {
@@ -483,7 +795,11 @@ func (c *cityDeckProbabilityModel) DrawEpidemic(index int) {
 			filtered = append(filtered, scenario)
 		}
 	}
-	c.Scenarios = filtered
+	// an epidemic entered where no layout has one would rule them all out,
+	// so the layouts are kept until the draws are put right
+	if len(filtered) > 0 {
+		c.Scenarios = filtered
+	}
 	c.EpidemicsDrawn++
 	c.LastIndex = index
 }
@@ -519,6 +835,36 @@ func (c *cityDeckProbabilityModel) EpidemicAnalysis(index int) EpidemicAnalysis
 	return analysis
 }
 
+func (c *cityDeckProbabilityModel) PilePosition(index int) PilePosition {
+	pos := PilePosition{MinPile: -1, MinDepth: -1, MinSize: -1}
+	for _, scenario := range c.Scenarios {
+		pos.NumPiles = len(scenario.CardCounts)
+		pile, depth, size := scenario.pileAt(index)
+		if pos.MinPile == -1 || pile < pos.MinPile {
+			pos.MinPile = pile
+		}
+		if pile > pos.MaxPile {
+			pos.MaxPile = pile
+		}
+		if pos.MinDepth == -1 || depth < pos.MinDepth {
+			pos.MinDepth = depth
+		}
+		if depth > pos.MaxDepth {
+			pos.MaxDepth = depth
+		}
+		if pos.MinSize == -1 || size < pos.MinSize {
+			pos.MinSize = size
+		}
+		if size > pos.MaxSize {
+			pos.MaxSize = size
+		}
+	}
+	if len(c.Scenarios) == 0 {
+		return PilePosition{}
+	}
+	return pos
+}
+
 func (c *cityDeckProbabilityModel) HighestIndex() int {
 	if len(c.Scenarios) == 0 {
 		return 0
@@ -540,6 +886,19 @@ func (c *cityDeckProbabilityModel) EpidemicProbabilityAt(index int) float64 {
 	return aggregate
 }
 
+// pileAt returns the pile containing the card at index, how many cards of
+// that pile come before it and the size of the pile. Indexes past the end of
+// the deck report a pile equal to the number of piles.
+func (c *cityDeckScenario) pileAt(index int) (int, int, int) {
+	for i, striationCount := range c.CardCounts {
+		if index < striationCount {
+			return i, index, striationCount
+		}
+		index = index - striationCount
+	}
+	return len(c.CardCounts), 0, 0
+}
+
 func (c *cityDeckScenario) EpidemicProbabilityAt(index, epidemicsDrawn int) float64 {
 	for i, striationCount := range c.CardCounts {
 		if index >= striationCount {
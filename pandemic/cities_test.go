@@ -44,3 +44,96 @@ func TestSimpleGame(t *testing.T) {
 		t.Fatalf("Expected 100%% chance of epidemic, got %v", prob)
 	}
 }
+
+func TestImpossibleEpidemic(t *testing.T) {
+	cities := Cities([]*City{{Name: "a"}, {Name: "b"}, {Name: "c"}})
+	deck, err := cities.GenerateCityDeck(2, nil, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := deck.DrawEpidemic(); err != nil {
+		t.Fatal(err)
+	}
+	// the first pile has at least two cards and already had its epidemic
+	err = deck.DrawEpidemic()
+	if _, ok := err.(*ImpossibleEpidemicError); !ok {
+		t.Fatalf("Expected an impossible epidemic, got %v", err)
+	}
+	if deck.EpidemicsDrawn() != 1 {
+		t.Fatalf("The impossible epidemic should not have been drawn, have %v", deck.EpidemicsDrawn())
+	}
+	if _, err := deck.DrawCard("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := deck.DrawEpidemic(); err != nil {
+		t.Fatalf("The second pile could start on the third card: %v", err)
+	}
+	audit := deck.Audit()
+	if len(audit) != 3 || audit[1].Card != "a" || audit[2].MinPile != 1 {
+		t.Fatalf("Unexpected audit %+v", audit)
+	}
+}
+
+func TestEpidemicCardIDs(t *testing.T) {
+	cities := Cities([]*City{{Name: "a"}, {Name: "b"}, {Name: "c"}})
+	deck, err := cities.GenerateCityDeck(2, nil, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := deck.DrawEpidemic(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := deck.DrawCard("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := deck.DrawEpidemic(); err != nil {
+		t.Fatal(err)
+	}
+	first, second := deck.Drawn[0], deck.Drawn[2]
+	if first.ID == 0 || second.ID == 0 || first.ID == second.ID {
+		t.Fatalf("Expected each epidemic drawn to be its own card, got IDs %v and %v", first.ID, second.ID)
+	}
+}
+
+func TestDuplicateCityCards(t *testing.T) {
+	cities := Cities([]*City{{Name: "a"}, {Name: "b"}})
+	deck, err := cities.GenerateCityDeckWith(1, nil, Set{}, []CityName{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := deck.ProbabilityOfDrawing("a"); math.Abs(p-2.0/4) > 0.0001 {
+		t.Fatalf("Expected both copies of a to count, got %v", p)
+	}
+	first, err := deck.DrawCard("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := deck.Discard("a"); err != nil {
+		t.Fatal(err)
+	}
+	if loc := deck.Location("a"); loc != InDeck {
+		t.Fatalf("The second copy of a should still be in the deck, got %v", loc)
+	}
+	second, err := deck.DrawCard("a")
+	if err != nil {
+		t.Fatalf("Should be able to draw the second copy of a: %v", err)
+	}
+	if first.ID == second.ID {
+		t.Fatalf("Both copies of a have ID %v", first.ID)
+	}
+	if _, err := deck.DrawCard("a"); err == nil {
+		t.Fatal("Should not be able to draw a third a")
+	}
+	if err := deck.Remove("a"); err != nil {
+		t.Fatal(err)
+	}
+	if len(deck.Discarded) != 1 || deck.Discarded[0].ID != first.ID || deck.Removed[0].ID != second.ID {
+		t.Fatalf("Expected the copy in hand to be removed and the discarded one kept, got %+v and %+v", deck.Discarded, deck.Removed)
+	}
+	if card, err := deck.GetCardByPrefix("a"); err != nil || card.Name() != "a" {
+		t.Fatalf("Copies of a should not make its prefix ambiguous: %v", err)
+	}
+	if _, err := cities.GenerateCityDeckWith(1, nil, Set{}, []CityName{"z"}); err == nil {
+		t.Fatal("Should not be able to copy a city that isn't in the game")
+	}
+}
@@ -0,0 +1,78 @@
+package pandemic
+
+import (
+	"sort"
+)
+
+// CityRecord is a city's history across the campaign.
+type CityRecord struct {
+	City CityName
+	// FirstInfected is the first game the city got cubes in.
+	FirstInfected string
+	Outbreaks     int
+	// TimesFallen counts the games the city fell in.
+	TimesFallen int
+}
+
+// CityRecords adds up the event logs of the campaign's games. Games are put
+// in campaign order first, so it does not matter how they were found.
+func CityRecords(games []*GameState) map[CityName]*CityRecord {
+	games = append([]*GameState{}, games...)
+	sort.Stable(byCampaignOrder(games))
+	records := map[CityName]*CityRecord{}
+	for _, game := range games {
+		if game.Log == nil {
+			continue
+		}
+		fell := Set{}
+		for _, event := range game.Log.Events {
+			if event.City == "" {
+				continue
+			}
+			record, ok := records[event.City]
+			if !ok {
+				record = &CityRecord{City: event.City}
+				records[event.City] = record
+			}
+			switch event.Kind {
+			case Infected:
+				if record.FirstInfected == "" {
+					record.FirstInfected = game.GameName
+				}
+			case Outbreak:
+				record.Outbreaks++
+			case CityFell:
+				if !fell.Contains(event.City) {
+					fell.Add(event.City)
+					record.TimesFallen++
+				}
+			}
+		}
+	}
+	return records
+}
+
+// monthIndex places a game in the campaign year, or after it when the name
+// is not a month.
+func monthIndex(gameName string) (int, int) {
+	month, attempt := ParseMonth(gameName)
+	for i, m := range Months {
+		if m == month {
+			return i, attempt
+		}
+	}
+	return len(Months), attempt
+}
+
+type byCampaignOrder []*GameState
+
+func (b byCampaignOrder) Len() int      { return len(b) }
+func (b byCampaignOrder) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byCampaignOrder) Less(i, j int) bool {
+	mi, ai := monthIndex(b[i].GameName)
+	mj, aj := monthIndex(b[j].GameName)
+	if mi != mj {
+		return mi < mj
+	}
+	return ai < aj
+}
@@ -0,0 +1,79 @@
+package pandemic
+
+import (
+	"sort"
+)
+
+// A Composition breaks a group of infection cards down by the disease of
+// each city and by how many cubes are already on it.
+type Composition struct {
+	Total     int
+	ByDisease map[DiseaseType]int
+	ByCubes   [MaxInfections + 1]int
+}
+
+func (gs GameState) Composition(cities []CityName) Composition {
+	comp := Composition{ByDisease: map[DiseaseType]int{}}
+	for _, cn := range cities {
+		city, err := gs.GetCity(cn)
+		if err != nil {
+			continue
+		}
+		comp.Total++
+		comp.ByDisease[city.Disease]++
+		cubes := city.NumInfections
+		if cubes > MaxInfections {
+			cubes = MaxInfections
+		}
+		comp.ByCubes[cubes]++
+	}
+	return comp
+}
+
+// NextStriationComposition describes the striation the next infection
+// cards will be drawn from.
+func (gs GameState) NextStriationComposition() Composition {
+	return gs.Composition(gs.InfectionDeck.CitiesInStriation(0))
+}
+
+func (gs GameState) DeckComposition() Composition {
+	cities := []CityName{}
+	for _, card := range gs.InfectionDeck.Cards() {
+		if card.Location == InStriation {
+			cities = append(cities, card.City)
+		}
+	}
+	return gs.Composition(cities)
+}
+
+func (c Composition) Fraction(dt DiseaseType) float64 {
+	if c.Total == 0 {
+		return 0
+	}
+	return float64(c.ByDisease[dt]) / float64(c.Total)
+}
+
+// Diseases lists the diseases in the composition, most common first.
+func (c Composition) Diseases() []DiseaseType {
+	diseases := []DiseaseType{}
+	for dt := range c.ByDisease {
+		diseases = append(diseases, dt)
+	}
+	sort.Sort(byCount{diseases, c.ByDisease})
+	return diseases
+}
+
+type byCount struct {
+	diseases []DiseaseType
+	counts   map[DiseaseType]int
+}
+
+func (b byCount) Len() int      { return len(b.diseases) }
+func (b byCount) Swap(i, j int) { b.diseases[i], b.diseases[j] = b.diseases[j], b.diseases[i] }
+func (b byCount) Less(i, j int) bool {
+	ci, cj := b.counts[b.diseases[i]], b.counts[b.diseases[j]]
+	if ci != cj {
+		return ci > cj
+	}
+	return b.diseases[i] < b.diseases[j]
+}
@@ -0,0 +1,33 @@
+package pandemic
+
+import (
+	"testing"
+)
+
+func TestComposition(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", Disease: Yellow.Type, NumInfections: 3},
+		{Name: "b", Disease: Yellow.Type},
+		{Name: "c", Disease: Yellow.Type, NumInfections: 1},
+		{Name: "d", Disease: Blue.Type},
+		{Name: "e", Disease: Red.Type},
+	})
+	deck := NewInfectionDeck(cities.CityNames())
+	deck.Striations = []Set{
+		Set{}.Add(CityName("a")).Add(CityName("b")).Add(CityName("c")).Add(CityName("d")),
+		Set{}.Add(CityName("e")),
+	}
+	gs := GameState{Cities: &cities, InfectionDeck: deck}
+
+	next := gs.NextStriationComposition()
+	if next.Total != 4 || next.Fraction(Yellow.Type) != 0.75 {
+		t.Fatalf("Expected the next striation to be 75%% yellow, got %+v", next)
+	}
+	if next.ByCubes != [4]int{2, 1, 0, 1} {
+		t.Fatalf("Unexpected cube breakdown %v", next.ByCubes)
+	}
+	deckComp := gs.DeckComposition()
+	if deckComp.Total != 5 || deckComp.Diseases()[0] != Yellow.Type || len(deckComp.Diseases()) != 3 {
+		t.Fatalf("Unexpected deck composition %+v", deckComp)
+	}
+}
@@ -0,0 +1,79 @@
+package pandemic
+
+// CubesPerDisease is how many cubes of each color come in the box.
+const CubesPerDisease = 24
+
+// CubeCount is how many cubes of a disease are left in the supply. It goes
+// below zero when a cube was needed and there were none left.
+type CubeCount struct {
+	Disease DiseaseType
+	Left    int
+}
+
+// CubeSupply lists the cubes left of every disease on the board. Season 2
+// keeps count of its plague cubes in the Supply. Otherwise every cube not
+// on a city is taken to be in the supply, counting both each city's own
+// disease and the cubes of other colors outbreaks spilled onto it, so
+// infections, outbreaks and treatments are all accounted for.
+func (gs GameState) CubeSupply() []CubeCount {
+	if gs.Supply != nil {
+		return []CubeCount{{Plague.Type, gs.Supply.PlagueCubes}}
+	}
+	supply := []CubeCount{}
+	for _, data := range gs.DiseaseData {
+		count := CubeCount{Disease: data.Type, Left: CubesPerDisease}
+		onBoard := false
+		for _, city := range *gs.Cities {
+			if city.Disease == data.Type || city.OtherCubes[data.Type] > 0 {
+				onBoard = true
+				count.Left -= city.Cubes(data.Type)
+			}
+		}
+		if onBoard {
+			supply = append(supply, count)
+		}
+	}
+	return supply
+}
+
+// CubesLeft is how many cubes of the disease are left in the supply.
+func (gs GameState) CubesLeft(dt DiseaseType) int {
+	for _, count := range gs.CubeSupply() {
+		if count.Disease == dt {
+			return count.Left
+		}
+	}
+	return CubesPerDisease
+}
+
+// placesCubesOf is whether infecting the city takes cubes of the disease
+// from the supply.
+func (gs GameState) placesCubesOf(city *City, dt DiseaseType) bool {
+	if gs.Supply != nil {
+		return dt == Plague.Type
+	}
+	return city.Disease == dt
+}
+
+// placeOtherCube puts a cube of another disease on the city, spilled by an
+// outbreak next door. It returns true when the city already has 3 of them,
+// in which case it outbreaks in that disease instead, raising its panic
+// level like an outbreak of its own disease. Eradicated diseases place
+// nothing.
+func (gs GameState) placeOtherCube(city *City, dt DiseaseType) bool {
+	if gs.IsEradicated(dt) {
+		return false
+	}
+	if city.Cubes(dt) == MaxInfections {
+		gs.recordCity(Outbreak, city.Name, "%v outbreaks with %v", city.Name, dt)
+		if city.PanicLevel < Fallen {
+			if city.PanicLevel++; city.PanicLevel == Fallen {
+				gs.recordCity(CityFell, city.Name, "%v has fallen", city.Name)
+			}
+		}
+		return true
+	}
+	city.setCubes(dt, city.Cubes(dt)+1)
+	gs.recordCity(Infected, city.Name, "%v infected with a cube of %v", city.Name, dt)
+	return false
+}
@@ -0,0 +1,63 @@
+package pandemic
+
+// CardsToCure is the number of city cards of the given disease the player
+// must discard to discover a cure. The second return value is false for
+// characters that cannot discover cures at all.
+func (gs GameState) CardsToCure(player *Player, dt DiseaseType) (int, bool) {
+	// TODO: make disease curability more programatic
+	required := 5
+	if dt == Red.Type || dt == Black.Type {
+		required = 4
+	}
+	return player.Role().CardsToCure(required)
+}
+
+// CardsHeldWith counts the city cards of the given disease in the player's
+// hand.
+func (gs GameState) CardsHeldWith(player *Player, dt DiseaseType) int {
+	var held int
+	for _, card := range player.Cards {
+		if !card.IsCity() {
+			continue
+		}
+		city, err := gs.Cities.GetCity(card.CityName)
+		if err != nil {
+			panic("City card with no corresponding city: " + card.CityName)
+		}
+		if city.Disease == dt {
+			held++
+		}
+	}
+	return held
+}
+
+type CureProgress struct {
+	Disease    DiseaseType
+	TeamHolds  int
+	Unknown    int     // cards in hands that could be of any color
+	Curer      *Player // nil if nobody can cure this disease
+	CurerHolds int
+	Needed     int
+}
+
+// CureProgress compares the cards the whole team holds for a disease with
+// what the designated curer needs. The designated curer is whoever is
+// fewest cards away from a cure.
+func (gs GameState) CureProgress(dt DiseaseType) CureProgress {
+	progress := CureProgress{Disease: dt}
+	for _, player := range gs.GameTurns.PlayerOrder {
+		held := gs.CardsHeldWith(player, dt)
+		progress.TeamHolds += held
+		progress.Unknown += player.UnknownCards
+		needed, canCure := gs.CardsToCure(player, dt)
+		if !canCure {
+			continue
+		}
+		if progress.Curer == nil || needed-held < progress.Needed-progress.CurerHolds {
+			progress.Curer = player
+			progress.CurerHolds = held
+			progress.Needed = needed
+		}
+	}
+	return progress
+}
@@ -0,0 +1,128 @@
+package pandemic
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCureProgress(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", Disease: Yellow.Type},
+		{Name: "b", Disease: Yellow.Type},
+		{Name: "c", Disease: Yellow.Type},
+		{Name: "d", Disease: Red.Type},
+	})
+	scientist := &Player{
+		HumanName: "sci",
+		Character: &Character{Type: Scientist},
+		Cards:     []*CityCard{{CityName: "a"}},
+	}
+	soldier := &Player{
+		HumanName: "sol",
+		Character: &Character{Type: Soldier},
+		Cards:     []*CityCard{{CityName: "b"}, {CityName: "c"}},
+	}
+	gs := GameState{Cities: &cities, GameTurns: InitGameTurns(scientist, soldier)}
+
+	progress := gs.CureProgress(Yellow.Type)
+	if progress.TeamHolds != 3 {
+		t.Fatalf("Expected the team to hold 3 yellow cards, got %v", progress.TeamHolds)
+	}
+	if progress.Curer != scientist || progress.CurerHolds != 1 || progress.Needed != 4 {
+		t.Fatalf("Expected the scientist to need 4 yellow cards and hold 1, got %+v", progress)
+	}
+}
+
+func TestUnknownCards(t *testing.T) {
+	cities, deck, err := generateLopsidedCityDeck()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracked := &Player{HumanName: "a"}
+	untracked := &Player{HumanName: "b"}
+	gs := GameState{Cities: &cities, CityDeck: &deck, GameTurns: InitGameTurns(tracked, untracked)}
+
+	before := gs.ProbabilityOfCuring(untracked, Yellow.Type)
+	if err := gs.DrawUnknown(untracked); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.DrawUnknown(untracked); err != nil {
+		t.Fatal(err)
+	}
+	if turn, _ := gs.GameTurns.CurrentTurn(); turn.Phase() != InfectPhase {
+		t.Fatalf("Expected two unknown draws to end the draw phase, got %v", turn.Phase())
+	}
+	if err := gs.DrawUnknown(untracked); err == nil {
+		t.Fatal("A third card should not be drawn in one turn")
+	}
+	if deck.RemainingCards() != 23 || deck.CardsLeftInDeck() != 21 {
+		t.Fatalf("Expected 23 unseen cards with 21 left in the deck, got %v and %v", deck.RemainingCards(), deck.CardsLeftInDeck())
+	}
+	// drawing cards nobody saw does not tell us anything new
+	unknown := gs.ProbabilityOfCuring(untracked, Yellow.Type)
+	if math.Abs(unknown-before) > 0.0001 {
+		t.Fatalf("Unknown draws should not change the odds of a cure, went from %v to %v", before, unknown)
+	}
+
+	if err := gs.RevealCard(untracked, "epidemic"); err == nil {
+		t.Fatal("An unknown card should not be revealed as an epidemic")
+	}
+	if err := gs.RevealCard(untracked, "d"); err != nil {
+		t.Fatal(err)
+	}
+	if untracked.UnknownCards != 1 || !untracked.HasCard("d") || deck.CardsLeftInDeck() != 21 {
+		t.Fatalf("Revealing should move the card into the hand without changing the deck, got %+v", untracked)
+	}
+	if revealed := gs.ProbabilityOfCuring(untracked, Yellow.Type); revealed <= unknown {
+		t.Fatalf("Revealing a yellow card should make a yellow cure more likely, went from %v to %v", unknown, revealed)
+	}
+	if err := gs.RevealCard(tracked, "e"); err == nil {
+		t.Fatal("Should not be able to reveal a card for a player with no unknown cards")
+	}
+}
+
+func TestRoles(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", Disease: Yellow.Type, NumInfections: 2, Neighbors: []string{"b"}},
+		{Name: "b", Disease: Yellow.Type, Neighbors: []string{"a", "c"}},
+		{Name: "c", Disease: Yellow.Type, Neighbors: []string{"b"}},
+	})
+	medic := &Player{HumanName: "med", Character: &Character{Type: Medic}, Location: "a"}
+	specialist := &Player{HumanName: "qs", Character: &Character{Type: QuarantineSpecialist}, Location: "c"}
+	gs := GameState{Cities: &cities, DiseaseData: []DiseaseData{Yellow}, GameTurns: InitGameTurns(medic, specialist)}
+
+	gs.infectCity(cities[1], 1)
+	if cities[1].NumInfections != 0 {
+		t.Fatalf("Expected the Quarantine Specialist to protect the city next to them, got %v cubes", cities[1].NumInfections)
+	}
+	if err := gs.Cure(Yellow.Type); err != nil {
+		t.Fatal(err)
+	}
+	if cities[0].NumInfections != 0 {
+		t.Fatalf("Expected the Medic to remove cured cubes from their city, got %v cubes", cities[0].NumInfections)
+	}
+	gs.infectCity(cities[0], 1)
+	if cities[0].NumInfections != 0 {
+		t.Fatal("Expected the Medic to keep cured cubes off their city")
+	}
+
+	cities = append(cities, &City{Name: "d", Disease: Yellow.Type, NumInfections: 2})
+	treated := []Change{}
+	gs.Subscribe(func(change Change) {
+		if _, ok := change.(CityTreated); ok {
+			treated = append(treated, change)
+		}
+	})
+	if err := gs.PlacePawn(medic, "d"); err != nil {
+		t.Fatal(err)
+	}
+	if cities[3].NumInfections != 0 {
+		t.Fatalf("Expected the Medic to treat cured cubes in the city they move to, got %v cubes", cities[3].NumInfections)
+	}
+	if len(treated) != 1 || treated[0] != (CityTreated{City: "d", Cubes: 2}) {
+		t.Fatalf("Expected the Medic's treatment to be published, got %+v", treated)
+	}
+	if gs.ProtectedBy("a") != nil || gs.ProtectedBy("d") != medic {
+		t.Fatal("Expected the Medic's protection to move with them")
+	}
+}
@@ -0,0 +1,108 @@
+package pandemic
+
+import (
+	"fmt"
+	"sort"
+)
+
+// NearlyEradicated is how few cubes of a color can be left on the board
+// before its cards stop being worth keeping to reach its cities.
+const NearlyEradicated = 3
+
+// A DiscardOption is a card in a player's hand and how safe it is to let
+// go of. The higher the Safety the less the team loses by discarding it.
+type DiscardOption struct {
+	Card    CardName
+	Safety  int
+	Reasons []string
+}
+
+type bySafety []DiscardOption
+
+func (b bySafety) Len() int           { return len(b) }
+func (b bySafety) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b bySafety) Less(i, j int) bool { return b[i].Safety > b[j].Safety }
+
+// DiscardAdvice ranks the player's recorded cards, safest to discard first.
+// A card is safe when its disease is cured, can't be cured or the team
+// already holds enough for the cure, when its city already has a research
+// station or the Operations Expert can build one there without it, and
+// when its color is all but gone from the board. It's kept when the cure
+// the player, or whoever is closest, is working on still needs it.
+// Event cards are better played than discarded.
+func (gs GameState) DiscardAdvice(player *Player) []DiscardOption {
+	options := []DiscardOption{}
+	earlier := map[DiseaseType]int{}
+	for _, card := range player.Cards {
+		if !card.IsCity() {
+			options = append(options, DiscardOption{
+				Card:    card.Name(),
+				Safety:  -3,
+				Reasons: []string{"play the event rather than discard it"},
+			})
+			continue
+		}
+		city, err := gs.GetCity(card.CityName)
+		if err != nil {
+			continue
+		}
+		option := DiscardOption{Card: card.Name()}
+		option.add(gs.cureValue(player, city.Disease, earlier[city.Disease]))
+		earlier[city.Disease]++
+		if city.ResearchStation {
+			option.add(1, fmt.Sprintf("%v already has a research station", city.Name))
+		} else if cost, err := gs.StationCost(player, city.Name); err == nil && cost.Empty() {
+			option.add(1, fmt.Sprintf("%v builds a research station in %v without it", player.HumanName, city.Name))
+		}
+		if cubes := gs.cubesOnBoardOf(city.Disease); cubes > 0 && cubes <= NearlyEradicated {
+			option.add(1, fmt.Sprintf("only %v %v cubes left on the board", cubes, city.Disease))
+		}
+		options = append(options, option)
+	}
+	sort.Stable(bySafety(options))
+	return options
+}
+
+func (o *DiscardOption) add(safety int, reason string) {
+	o.Safety += safety
+	o.Reasons = append(o.Reasons, reason)
+}
+
+// cureValue is how safe a card of the disease is to discard as far as the
+// cure goes, and why. earlier is how many cards of the disease come before it
+// in the player's hand: only as many as the team holds beyond what the cure
+// needs are spare.
+func (gs GameState) cureValue(player *Player, dt DiseaseType, earlier int) (int, string) {
+	data, err := gs.GetDiseaseData(dt)
+	if err != nil {
+		return 0, fmt.Sprintf("%v isn't a disease in this game", dt)
+	}
+	switch {
+	case data.Eradicated:
+		return 3, fmt.Sprintf("%v is eradicated", dt)
+	case data.Cured:
+		return 3, fmt.Sprintf("%v is cured", dt)
+	case data.Incurable:
+		return 3, fmt.Sprintf("%v can't be cured", dt)
+	}
+	progress := gs.CureProgress(dt)
+	switch {
+	case progress.Curer == nil:
+		return 3, fmt.Sprintf("nobody can cure %v", dt)
+	case earlier < progress.TeamHolds-progress.Needed:
+		return 1, fmt.Sprintf("the team holds %v %v cards, the cure needs %v", progress.TeamHolds, dt, progress.Needed)
+	case gs.ProbabilityOfCuring(progress.Curer, dt) == 0:
+		return 2, fmt.Sprintf("too few %v cards are left to cure it", dt)
+	case progress.Curer == player:
+		return -2, fmt.Sprintf("%v needs it to cure %v, holding %v of %v", player.HumanName, dt, progress.CurerHolds, progress.Needed)
+	}
+	return -1, fmt.Sprintf("%v is closest to curing %v, give it to them", progress.Curer.HumanName, dt)
+}
+
+func (gs GameState) cubesOnBoardOf(dt DiseaseType) int {
+	cubes := 0
+	for _, city := range gs.Cities.WithDisease(dt) {
+		cubes += city.NumInfections
+	}
+	return cubes
+}
@@ -0,0 +1,94 @@
+package pandemic
+
+import (
+	"testing"
+)
+
+func TestDiscardAdvice(t *testing.T) {
+	cities, deck, err := generateLopsidedCityDeck()
+	if err != nil {
+		t.Fatal(err)
+	}
+	scientist := &Player{
+		HumanName: "sci",
+		Character: &Character{Type: Scientist},
+		Cards:     []*CityCard{{CityName: "i"}, {CityName: "d"}, {CityName: "a"}},
+	}
+	other := &Player{
+		HumanName: "other",
+		Cards:     []*CityCard{{CityName: "e"}, {CityName: "f"}},
+	}
+	red := Red
+	red.Cured = true
+	gs := GameState{
+		Cities:      &cities,
+		CityDeck:    &deck,
+		DiseaseData: []DiseaseData{Blue, Yellow, red, Black},
+		GameTurns:   InitGameTurns(scientist, other),
+	}
+	a, _ := gs.GetCity("a")
+	a.ResearchStation = true
+	i, _ := gs.GetCity("i")
+	i.NumInfections = 2
+
+	advice := gs.DiscardAdvice(scientist)
+	order := []CardName{}
+	for _, option := range advice {
+		order = append(order, option.Card)
+	}
+	if len(order) != 3 || order[0] != "i" || order[1] != "a" || order[2] != "d" {
+		t.Fatalf("Expected i and a to be safe to discard before d, got %v", advice)
+	}
+	if advice[0].Safety != 4 || advice[1].Safety != 4 || advice[2].Safety != -2 {
+		t.Fatalf("Expected safeties of 4, 4 and -2, got %v", advice)
+	}
+
+	// the cure is the scientist's, the others' yellow cards should go to them
+	advice = gs.DiscardAdvice(other)
+	if len(advice) != 2 || advice[0].Safety != -1 {
+		t.Fatalf("Expected the other player's yellow cards to be kept for the scientist, got %v", advice)
+	}
+
+	// the operations expert doesn't need a city's card to build a station there
+	other.Character = &Character{Type: OperationsExpert}
+	advice = gs.DiscardAdvice(other)
+	if len(advice) != 2 || advice[0].Safety != 0 {
+		t.Fatalf("Expected the operations expert's cards to be safer to discard, got %v", advice)
+	}
+}
+
+func TestDiscardAdviceSurplus(t *testing.T) {
+	cities, deck, err := generateLopsidedCityDeck()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []CityName{"x", "y", "z"} {
+		cities = append(cities, &City{Name: name, Disease: Yellow.Type, OriginalDisease: Yellow.Type})
+	}
+	scientist := &Player{
+		HumanName: "sci",
+		Character: &Character{Type: Scientist},
+		Cards:     []*CityCard{{CityName: "d"}, {CityName: "e"}, {CityName: "f"}, {CityName: "x"}},
+	}
+	other := &Player{
+		HumanName: "other",
+		Cards:     []*CityCard{{CityName: "y"}},
+	}
+	gs := GameState{
+		Cities:      &cities,
+		CityDeck:    &deck,
+		DiseaseData: []DiseaseData{Blue, Yellow, Red, Black},
+		GameTurns:   InitGameTurns(scientist, other),
+	}
+
+	// five yellow cards for a cure of four leaves one to spare, not five
+	spare := 0
+	for _, option := range gs.DiscardAdvice(scientist) {
+		if option.Safety > 0 {
+			spare++
+		}
+	}
+	if spare != 1 {
+		t.Fatalf("Expected one of the scientist's yellow cards to be spare, got %v", spare)
+	}
+}
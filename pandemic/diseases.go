@@ -8,6 +8,8 @@ type DiseaseData struct {
 	Untreatable      bool        `json:"untreatable,omitempty"`
 	BecomingFaded    bool        `json:"becoming_faded,omitempty"`
 	InfectOnCityDraw bool        `json:"infect_on_city_draw,omitempty"`
+	Cured            bool        `json:"cured,omitempty"`
+	Eradicated       bool        `json:"eradicated,omitempty"`
 }
 
 var Yellow = DiseaseData{
@@ -33,6 +35,12 @@ var Faded = DiseaseData{
 	InfectOnCityDraw: true,
 }
 
+// FadedUnlock is the spoiler that reveals how the Faded spread, listed in
+// the new game file's unlocks once the campaign has revealed it. Until then
+// the Faded outbreak like any other disease, so the tracker doesn't give the
+// surprise away.
+const FadedUnlock = "faded"
+
 func (dt DiseaseType) String() string {
 	return string(dt)
 }
@@ -0,0 +1,162 @@
+package pandemic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Dump writes everything a save holds as indented "key: value" lines that
+// can be pasted into a bug report. Keys are sorted, sets are written as
+// sorted lists and empty values, including false and "", are left out, so
+// the same game always dumps to the same text. Lists of small objects, such
+// as the cities, take one line per object.
+func (gs GameState) Dump(w io.Writer) error {
+	data, err := json.Marshal(gs)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var state map[string]interface{}
+	if err := decoder.Decode(&state); err != nil {
+		return err
+	}
+	lines := []string{}
+	for _, key := range sortedKeys(state) {
+		lines = append(lines, dumpField("", key, state[key])...)
+	}
+	_, err = fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+func dumpField(indent, key string, value interface{}) []string {
+	if isEmpty(value) {
+		return nil
+	}
+	if inline, ok := dumpInline(value); ok {
+		return []string{fmt.Sprintf("%v%v: %v", indent, key, inline)}
+	}
+	lines := []string{indent + key + ":"}
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for _, child := range sortedKeys(value) {
+			lines = append(lines, dumpField(indent+"  ", child, value[child])...)
+		}
+	case []interface{}:
+		for _, item := range value {
+			if inline, ok := dumpInline(item); ok {
+				lines = append(lines, fmt.Sprintf("%v  - %v", indent, inline))
+				continue
+			}
+			lines = append(lines, indent+"  -")
+			if fields, ok := item.(map[string]interface{}); ok {
+				for _, child := range sortedKeys(fields) {
+					lines = append(lines, dumpField(indent+"    ", child, fields[child])...)
+				}
+			}
+		}
+	}
+	return lines
+}
+
+// dumpInline writes scalars, sets, lists of scalars and objects made up of
+// those on a single line.
+func dumpInline(value interface{}) (string, bool) {
+	switch value := value.(type) {
+	case nil:
+		return "null", true
+	case bool:
+		return strconv.FormatBool(value), true
+	case json.Number:
+		return value.String(), true
+	case string:
+		return dumpString(value), true
+	case []interface{}:
+		items := []string{}
+		for _, item := range value {
+			if _, ok := item.(map[string]interface{}); ok {
+				return "", false
+			}
+			if _, ok := item.([]interface{}); ok {
+				return "", false
+			}
+			inline, _ := dumpInline(item)
+			items = append(items, inline)
+		}
+		return "[" + strings.Join(items, ", ") + "]", true
+	case map[string]interface{}:
+		if isSet(value) {
+			items := []string{}
+			for _, key := range sortedKeys(value) {
+				items = append(items, dumpString(key))
+			}
+			return "[" + strings.Join(items, ", ") + "]", true
+		}
+		fields := []string{}
+		for _, key := range sortedKeys(value) {
+			if isEmpty(value[key]) {
+				continue
+			}
+			if _, ok := value[key].(map[string]interface{}); ok && !isSet(value[key].(map[string]interface{})) {
+				return "", false
+			}
+			inline, ok := dumpInline(value[key])
+			if !ok {
+				return "", false
+			}
+			fields = append(fields, fmt.Sprintf("%v: %v", key, inline))
+		}
+		return "{" + strings.Join(fields, ", ") + "}", true
+	}
+	return fmt.Sprintf("%v", value), true
+}
+
+func dumpString(s string) string {
+	if s == "" || strings.ContainsAny(s, ",:[]{}#\"'\n") || strings.TrimSpace(s) != s {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// isSet spots a Set, which is saved as an object of empty objects.
+func isSet(value map[string]interface{}) bool {
+	if len(value) == 0 {
+		return false
+	}
+	for _, member := range value {
+		if fields, ok := member.(map[string]interface{}); !ok || len(fields) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func isEmpty(value interface{}) bool {
+	switch value := value.(type) {
+	case nil:
+		return true
+	case bool:
+		return !value
+	case string:
+		return value == ""
+	case []interface{}:
+		return len(value) == 0
+	case map[string]interface{}:
+		return len(value) == 0
+	}
+	return false
+}
+
+func sortedKeys(value map[string]interface{}) []string {
+	keys := []string{}
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
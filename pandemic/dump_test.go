@@ -0,0 +1,49 @@
+package pandemic
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "b", Disease: Blue.Type, NumInfections: 2, Neighbors: []string{"a"}},
+		{Name: "a", Disease: Blue.Type, Quarantined: true, Neighbors: []string{"b"}},
+	})
+	gs := GameState{
+		GameName:      "jan",
+		Cities:        &cities,
+		InfectionDeck: NewInfectionDeck(cities.CityNames()),
+		InfectionRate: 2,
+		GameTurns:     InitGameTurns(&Player{HumanName: "p1"}, &Player{HumanName: "p2"}),
+	}
+	if _, err := gs.Infect("b"); err != nil {
+		t.Fatal(err)
+	}
+	var first, second bytes.Buffer
+	if err := gs.Dump(&first); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.Dump(&second); err != nil {
+		t.Fatal(err)
+	}
+	if first.String() != second.String() {
+		t.Fatalf("Expected the same game to dump the same way, got\n%v\nand\n%v", first.String(), second.String())
+	}
+	dump := first.String()
+	for _, line := range []string{
+		"  - {disease: Blue, name: b, neighbors: [a], num_infections: 3, panic_level: Nothing}",
+		"  - {disease: Blue, name: a, neighbors: [b], num_infections: 0, panic_level: Nothing, quarantined: true}",
+		"game_name: jan",
+		"  Drawn: [b]",
+		"  Striations:\n    - [a]",
+	} {
+		if !strings.Contains(dump, line+"\n") {
+			t.Fatalf("Expected %q in the dump, got\n%v", line, dump)
+		}
+	}
+	if strings.Index(dump, "cities:") > strings.Index(dump, "game_name:") {
+		t.Fatalf("Expected sorted keys, got\n%v", dump)
+	}
+}
@@ -0,0 +1,92 @@
+package pandemic
+
+// EpidemicResult is everything an epidemic changed, step by step, for the
+// table to check against the board.
+type EpidemicResult struct {
+	City CityName
+	// RateBefore and RateAfter are the infection rate either side of the
+	// Increase step.
+	RateBefore int
+	RateAfter  int
+	// Cubes is how many cubes the Infect step added to the city. None are
+	// added to a protected city, and a quarantined one loses its marker
+	// instead.
+	Cubes             int
+	QuarantineRemoved bool
+	Chain             OutbreakChain
+	// Intensified is how many cards were shuffled back on top of the
+	// infection deck.
+	Intensified int
+}
+
+// Epidemic draws an epidemic from the city deck and resolves it: Increase
+// moves the infection rate marker, Infect pulls the city from the bottom of
+// the infection deck and places 3 cubes on it, outbreaking if need be, and
+// Intensify shuffles the discard pile back on top of the deck. The epidemic
+// is resolved in full or not at all: if any step fails the game is put back
+// as it was. An epidemic where the city deck can't have one is refused with
+// an ImpossibleEpidemicError unless forced.
+func (gs *GameState) Epidemic(cn CityName, force bool) (*EpidemicResult, error) {
+	if err := gs.CheckPlaying(); err != nil {
+		return nil, err
+	}
+	if err := gs.CityDeck.CanDrawEpidemic(); err != nil {
+		if _, impossible := err.(*ImpossibleEpidemicError); !impossible || !force {
+			return nil, err
+		}
+	}
+	before, err := gs.Clone()
+	if err != nil {
+		return nil, err
+	}
+	result, err := gs.epidemic(cn)
+	if err != nil {
+		gs.restore(before)
+		return nil, err
+	}
+	gs.markDeckRanOut(gs.currentTurn())
+	return result, nil
+}
+
+func (gs *GameState) epidemic(cn CityName) (*EpidemicResult, error) {
+	result := &EpidemicResult{City: cn, RateBefore: gs.InfectionRate}
+	// the draws are the steps that can fail, so they come before anything
+	// is published. Epidemic has already checked the city deck.
+	card := gs.CityDeck.drawEpidemic()
+	if err := gs.InfectionDeck.PullFromBottom(cn); err != nil {
+		return nil, err
+	}
+	city, err := gs.Cities.GetCity(cn)
+	if err != nil {
+		return nil, err
+	}
+	// the epidemic counts towards this turn's city card draws
+	if curTurn := gs.currentTurn(); curTurn != nil {
+		curTurn.DrawnCards = append(curTurn.DrawnCards, &card)
+	}
+	gs.recordCity(EpidemicResolved, cn, "Epidemic in %v", cn)
+	gs.publish(EpidemicDrawn{City: cn})
+
+	// Increase
+	gs.advanceInfectionRate()
+	result.RateAfter = gs.InfectionRate
+
+	// Infect
+	cubes := city.NumInfections
+	result.Chain, result.QuarantineRemoved = gs.placeInfection(city, MaxInfections)
+	result.Cubes = city.NumInfections - cubes
+
+	// Intensify
+	result.Intensified = len(gs.InfectionDeck.DiscardPile())
+	gs.InfectionDeck.ShuffleDrawn()
+	gs.record(Intensified, "%v infection cards were shuffled back on top of the deck", result.Intensified)
+	return result, nil
+}
+
+// restore puts the game back as it was when before was cloned from it. The
+// subscribers stay, since the clone has none.
+func (gs *GameState) restore(before *GameState) {
+	bus := gs.bus
+	*gs = *before
+	gs.bus = bus
+}
@@ -0,0 +1,151 @@
+package pandemic
+
+import (
+	"fmt"
+)
+
+// An EradicationAlert announces that a cured disease has no cubes left on
+// the board, or that treating a single city would eradicate it.
+type EradicationAlert struct {
+	Disease    DiseaseType
+	Eradicated bool
+	LastCity   CityName // the only city with cubes left, if not eradicated
+}
+
+func (a EradicationAlert) String() string {
+	if a.Eradicated {
+		return fmt.Sprintf("%v has been eradicated", a.Disease)
+	}
+	return fmt.Sprintf("%v is one treat away from eradication in %v", a.Disease, a.LastCity)
+}
+
+func (gs GameState) diseaseIndex(dt DiseaseType) (int, error) {
+	for i, data := range gs.DiseaseData {
+		if data.Type == dt {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("No disease identified by %v", dt)
+}
+
+func (gs GameState) Cure(dt DiseaseType) error {
+	i, err := gs.diseaseIndex(dt)
+	if err != nil {
+		return err
+	}
+	data := &gs.DiseaseData[i]
+	if data.Incurable {
+		return fmt.Errorf("%v cannot be cured", dt)
+	}
+	if data.Cured {
+		return fmt.Errorf("%v is already cured", dt)
+	}
+	data.Cured = true
+	gs.enterRoles()
+	gs.publish(DiseaseCured{Disease: dt})
+	return nil
+}
+
+// Treat records the player treating the city's disease the given number of
+// times, one cube and one action each. A cured disease, or a Medic, takes
+// every cube off at once for a single action. It returns the cubes removed
+// and the actions spent.
+func (gs GameState) Treat(cn CityName, times int, player *Player) (int, int, error) {
+	city, err := gs.Cities.GetCity(cn)
+	if err != nil {
+		return 0, 0, err
+	}
+	return gs.TreatDisease(cn, city.Disease, times, player)
+}
+
+// TreatDisease is Treat for cubes of any disease on the city, such as the
+// ones an outbreak next door spilled onto it.
+func (gs GameState) TreatDisease(cn CityName, dt DiseaseType, times int, player *Player) (int, int, error) {
+	if err := gs.CheckPlaying(); err != nil {
+		return 0, 0, err
+	}
+	city, err := gs.Cities.GetCity(cn)
+	if err != nil {
+		return 0, 0, err
+	}
+	if times < 1 {
+		return 0, 0, fmt.Errorf("Treat %v at least once", cn)
+	}
+	cubes := city.Cubes(dt)
+	if cubes == 0 {
+		return 0, 0, fmt.Errorf("%v has no cubes of %v to treat", cn, dt)
+	}
+	removed, actions := times, times
+	if gs.isCured(dt) || (player != nil && player.IsCharacter(Medic)) {
+		removed, actions = cubes, 1
+	} else if times > cubes {
+		return 0, 0, fmt.Errorf("%v only has %v cubes of %v to treat", cn, cubes, dt)
+	}
+	city.setCubes(dt, cubes-removed)
+	gs.recordCity(Treated, cn, "%v cubes of %v treated in %v", removed, dt, cn)
+	gs.publish(CityTreated{City: cn, Cubes: removed})
+	return removed, actions, nil
+}
+
+// Eradicate marks a cured disease as eradicated, for when the table
+// eradicates it before the tracker notices, e.g. because treatments weren't
+// entered. Any cubes the tracker still has for it are taken off the board.
+func (gs GameState) Eradicate(dt DiseaseType) error {
+	i, err := gs.diseaseIndex(dt)
+	if err != nil {
+		return err
+	}
+	data := &gs.DiseaseData[i]
+	if !data.Cured {
+		return fmt.Errorf("%v has to be cured before it can be eradicated", dt)
+	}
+	if data.Eradicated {
+		return fmt.Errorf("%v is already eradicated", dt)
+	}
+	for _, city := range *gs.Cities {
+		if cubes := city.Cubes(dt); cubes > 0 {
+			gs.recordCity(ManualOverride, city.Name, "%v cubes in %v cleared by eradicating %v", cubes, city.Name, dt)
+			city.setCubes(dt, 0)
+		}
+	}
+	data.Eradicated = true
+	gs.record(Eradication, "%v eradicated", dt)
+	return nil
+}
+
+func (gs GameState) isCured(dt DiseaseType) bool {
+	i, err := gs.diseaseIndex(dt)
+	return err == nil && gs.DiseaseData[i].Cured
+}
+
+func (gs GameState) IsEradicated(dt DiseaseType) bool {
+	i, err := gs.diseaseIndex(dt)
+	return err == nil && gs.DiseaseData[i].Eradicated
+}
+
+// CheckEradication looks at every cured disease, marking the ones without
+// any cubes on the board as eradicated.
+func (gs GameState) CheckEradication() []EradicationAlert {
+	alerts := []EradicationAlert{}
+	for i := range gs.DiseaseData {
+		data := &gs.DiseaseData[i]
+		if !data.Cured || data.Eradicated {
+			continue
+		}
+		infected := []CityName{}
+		for _, city := range *gs.Cities {
+			if city.Cubes(data.Type) > 0 {
+				infected = append(infected, city.Name)
+			}
+		}
+		switch len(infected) {
+		case 0:
+			data.Eradicated = true
+			gs.record(Eradication, "%v eradicated", data.Type)
+			alerts = append(alerts, EradicationAlert{Disease: data.Type, Eradicated: true})
+		case 1:
+			alerts = append(alerts, EradicationAlert{Disease: data.Type, LastCity: infected[0]})
+		}
+	}
+	return alerts
+}
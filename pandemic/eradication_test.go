@@ -0,0 +1,110 @@
+package pandemic
+
+import (
+	"testing"
+)
+
+func TestCheckEradication(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", Disease: Yellow.Type, NumInfections: 1},
+		{Name: "b", Disease: Yellow.Type},
+		{Name: "c", Disease: Red.Type, NumInfections: 2},
+	})
+	gs := &GameState{
+		Cities:        &cities,
+		DiseaseData:   []DiseaseData{Yellow, Red, Blue},
+		InfectionDeck: NewInfectionDeck(cities.CityNames()),
+		Log:           &EventLog{},
+	}
+	if err := gs.Cure(Blue.Type); err == nil {
+		t.Fatal("Blue should not be curable")
+	}
+	if err := gs.Cure(Yellow.Type); err != nil {
+		t.Fatal(err)
+	}
+
+	alerts := gs.CheckEradication()
+	if len(alerts) != 1 || alerts[0].Eradicated || alerts[0].LastCity != "a" {
+		t.Fatalf("Expected yellow to be one treat away in a, got %+v", alerts)
+	}
+	if err := gs.SetInfections("a", 0); err != nil {
+		t.Fatal(err)
+	}
+	alerts = gs.CheckEradication()
+	if len(alerts) != 1 || !alerts[0].Eradicated || !gs.IsEradicated(Yellow.Type) {
+		t.Fatalf("Expected yellow to be eradicated, got %+v", alerts)
+	}
+
+	if _, err := gs.Infect("b"); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := cities.GetCity("b")
+	if b.NumInfections != 0 {
+		t.Fatal("Infecting a city with an eradicated disease should not place cubes")
+	}
+}
+
+func TestEradicate(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", Disease: Red.Type, NumInfections: 1},
+		{Name: "b", Disease: Yellow.Type, NumInfections: 1},
+	})
+	gs := &GameState{
+		Cities:        &cities,
+		DiseaseData:   []DiseaseData{Yellow, Red},
+		InfectionDeck: NewInfectionDeck(cities.CityNames()),
+		Log:           &EventLog{},
+	}
+	if err := gs.Eradicate(Red.Type); err == nil {
+		t.Fatal("Red should have to be cured first")
+	}
+	if err := gs.Cure(Red.Type); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.Eradicate(Red.Type); err != nil {
+		t.Fatal(err)
+	}
+	if a, _ := cities.GetCity("a"); a.NumInfections != 0 || !gs.IsEradicated(Red.Type) {
+		t.Fatalf("Expected red to be eradicated and its cubes cleared, got %+v", a)
+	}
+	if p := gs.ProbabilityOfCity("a"); p != 0.0 {
+		t.Fatalf("Expected no chance of cubes on an eradicated city, got %v", p)
+	}
+}
+
+func TestTreat(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", Disease: Yellow.Type, NumInfections: 3},
+		{Name: "b", Disease: Red.Type, NumInfections: 2},
+		{Name: "c", Disease: Red.Type, NumInfections: 2},
+	})
+	player := &Player{HumanName: "p1"}
+	medic := &Player{HumanName: "med", Character: &Character{Type: Medic}}
+	gs := GameState{
+		Cities:      &cities,
+		DiseaseData: []DiseaseData{Yellow, Red},
+		GameTurns:   InitGameTurns(player, medic),
+		Log:         &EventLog{},
+	}
+	if removed, actions, err := gs.Treat("a", 2, player); err != nil || removed != 2 || actions != 2 {
+		t.Fatalf("Expected two treats to take 2 cubes for 2 actions, got %v cubes, %v actions: %v", removed, actions, err)
+	}
+	if _, _, err := gs.Treat("a", 2, player); err == nil || cities[0].NumInfections != 1 {
+		t.Fatal("Should not treat more cubes than the city has")
+	}
+	if removed, actions, err := gs.Treat("b", 1, medic); err != nil || removed != 2 || actions != 1 || cities[1].NumInfections != 0 {
+		t.Fatalf("Expected the Medic to take every cube for 1 action, got %v cubes, %v actions: %v", removed, actions, err)
+	}
+	if _, _, err := gs.Treat("b", 1, player); err == nil {
+		t.Fatal("Should not treat a city without cubes")
+	}
+	if err := gs.Cure(Red.Type); err != nil {
+		t.Fatal(err)
+	}
+	if removed, actions, err := gs.Treat("c", 1, player); err != nil || removed != 2 || actions != 1 {
+		t.Fatalf("Expected a cured disease to come off all at once, got %v cubes, %v actions: %v", removed, actions, err)
+	}
+	if alerts := gs.CheckEradication(); len(alerts) != 1 || !alerts[0].Eradicated {
+		t.Fatalf("Expected treating the last red cubes to eradicate red, got %v", alerts)
+	}
+}
@@ -0,0 +1,112 @@
+package pandemic
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A TurnRange picks the turns an export covers, counting from 1 as the
+// players do. A zero First or Last leaves that end open.
+type TurnRange struct {
+	First int
+	Last  int
+}
+
+// ParseTurnRange reads a range like 10-20, 10- or 10.
+func ParseTurnRange(s string) (TurnRange, error) {
+	invalid := fmt.Errorf("Expected turns like 10-20, 10- or 10, got %q", s)
+	parts := strings.SplitN(s, "-", 2)
+	var r TurnRange
+	var err error
+	if parts[0] != "" {
+		if r.First, err = strconv.Atoi(parts[0]); err != nil || r.First < 1 {
+			return r, invalid
+		}
+	}
+	if len(parts) == 1 {
+		r.Last = r.First
+	} else if parts[1] != "" {
+		if r.Last, err = strconv.Atoi(parts[1]); err != nil || r.Last < 1 {
+			return r, invalid
+		}
+	}
+	if r.Last != 0 && r.Last < r.First {
+		return r, invalid
+	}
+	return r, nil
+}
+
+// Contains checks an event's turn, which counts from 0.
+func (r TurnRange) Contains(turn int) bool {
+	return turn+1 >= r.First && (r.Last == 0 || turn+1 <= r.Last)
+}
+
+func (r TurnRange) String() string {
+	switch {
+	case r.First == 0 && r.Last == 0:
+		return "all turns"
+	case r.First == r.Last:
+		return fmt.Sprintf("turn %v", r.First)
+	case r.Last == 0:
+		return fmt.Sprintf("turns %v onwards", r.First)
+	}
+	return fmt.Sprintf("turns %v-%v", r.First, r.Last)
+}
+
+// GameStart anchors the game clock: when the game was set up, or its first
+// event for games from before the metadata was kept.
+func (gs GameState) GameStart() time.Time {
+	if gs.Metadata != nil && !gs.Metadata.CreatedAt.IsZero() {
+		return gs.Metadata.CreatedAt
+	}
+	if gs.Log != nil && len(gs.Log.Events) > 0 {
+		return gs.Log.Events[0].Time
+	}
+	return time.Time{}
+}
+
+// GameClock is how far into the game the time is, to the second.
+func (gs GameState) GameClock(t time.Time) time.Duration {
+	start := gs.GameStart()
+	if start.IsZero() || t.Before(start) {
+		return 0
+	}
+	return t.Sub(start) - t.Sub(start)%time.Second
+}
+
+// EventsIn lists the logged events of the turns in the range.
+func (gs GameState) EventsIn(r TurnRange) []*GameEvent {
+	events := []*GameEvent{}
+	if gs.Log == nil {
+		return events
+	}
+	for _, event := range gs.Log.Events {
+		if r.Contains(event.Turn) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// WriteEventsCSV exports the events of the turns in the range, each with its
+// turn, wall clock time and game clock time.
+func (gs GameState) WriteEventsCSV(w io.Writer, r TurnRange) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"turn", "time", "game_clock", "kind", "city", "message"})
+	for _, event := range gs.EventsIn(r) {
+		out.Write([]string{
+			strconv.Itoa(event.Turn + 1),
+			event.Time.Format(time.RFC3339),
+			gs.GameClock(event.Time).String(),
+			string(event.Kind),
+			string(event.City),
+			event.Message,
+		})
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,42 @@
+package pandemic
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTurnRange(t *testing.T) {
+	for s, want := range map[string]TurnRange{"10-20": {10, 20}, "10-": {10, 0}, "7": {7, 7}} {
+		got, err := ParseTurnRange(s)
+		if err != nil || got != want {
+			t.Fatalf("Expected %v to be %+v, got %+v (%v)", s, want, got, err)
+		}
+	}
+	for _, s := range []string{"20-10", "a-b", "0"} {
+		if _, err := ParseTurnRange(s); err == nil {
+			t.Fatalf("Expected %v to be rejected", s)
+		}
+	}
+}
+
+func TestWriteEventsCSV(t *testing.T) {
+	start := time.Date(2016, 9, 1, 19, 0, 0, 0, time.UTC)
+	gs := GameState{
+		Metadata: &GameMetadata{CreatedAt: start},
+		Log: &EventLog{Events: []*GameEvent{
+			{Kind: Infected, Turn: 0, Time: start.Add(time.Minute), City: "a", Message: "a infected with 1 cubes"},
+			{Kind: Outbreak, Turn: 9, Time: start.Add(90 * time.Minute), City: "b", Message: "b outbreaks"},
+			{Kind: Infected, Turn: 20, Time: start.Add(2 * time.Hour), City: "c", Message: "c infected with 1 cubes"},
+		}},
+	}
+	var out bytes.Buffer
+	if err := gs.WriteEventsCSV(&out, TurnRange{First: 10, Last: 20}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || lines[1] != "10,2016-09-01T20:30:00Z,1h30m0s,outbreak,b,b outbreaks" {
+		t.Fatalf("Expected only turn 10's outbreak, got %q", lines)
+	}
+}
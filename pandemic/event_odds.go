@@ -0,0 +1,61 @@
+package pandemic
+
+import (
+	"fmt"
+)
+
+// ProbabilityOfEventBeforeEpidemic is the chance that the named funded event
+// turns up in the city deck before the next epidemic does. The event is
+// equally likely to be any city deck card that hasn't been seen, so unknown
+// draws already sitting in players' hands count as drawn in time. When no
+// epidemics are left the event will come up before one, so the answer is 1.
+func (c *CityDeck) ProbabilityOfEventBeforeEpidemic(cn CardName) (float64, error) {
+	card, err := c.GetCard(cn)
+	if err != nil {
+		return 0.0, err
+	}
+	if !card.IsFundedEvent() {
+		return 0.0, fmt.Errorf("%v is not an event card", cn)
+	}
+	if loc := c.Location(cn); loc != InDeck {
+		return 0.0, fmt.Errorf("%v has already been drawn, it is in the %v", cn, loc)
+	}
+	unseen := c.RemainingCards() - (c.NumEpidemics() - c.EpidemicsDrawn())
+	if unseen <= 0 {
+		return 0.0, fmt.Errorf("There are no cards left in the city deck")
+	}
+	model := c.ProbabilityModel
+	if len(model.Scenarios) == 0 {
+		return 0.0, fmt.Errorf("No deck scenario matches the epidemics drawn so far")
+	}
+	index := c.probabilityIndex()
+	var aggregate float64
+	for _, scenario := range model.Scenarios {
+		before, ok := scenario.cardsBeforeNextEpidemic(index, model.EpidemicsDrawn)
+		if !ok {
+			aggregate += 1.0
+			continue
+		}
+		aggregate += (float64(c.UnknownDraws) + before) / float64(unseen)
+	}
+	return aggregate / float64(len(model.Scenarios)), nil
+}
+
+// cardsBeforeNextEpidemic is the expected number of city cards drawn from
+// index onwards before the next epidemic. The epidemic is equally likely to
+// be any card left in its pile. It reports false once every epidemic in the
+// scenario has been drawn.
+func (c *cityDeckScenario) cardsBeforeNextEpidemic(index, epidemicsDrawn int) (float64, bool) {
+	pile, depth, size := c.pileAt(index)
+	if pile >= len(c.CardCounts) {
+		return 0.0, false
+	}
+	left := size - depth
+	if pile >= epidemicsDrawn {
+		return float64(left-1) / 2.0, true
+	}
+	if pile+1 >= len(c.CardCounts) {
+		return 0.0, false
+	}
+	return float64(left) + float64(c.CardCounts[pile+1]-1)/2.0, true
+}
@@ -0,0 +1,68 @@
+package pandemic
+
+import (
+	"fmt"
+	"time"
+)
+
+type EventKind string
+
+const (
+	ManualOverride = EventKind("manual_override")
+	Eradication    = EventKind("eradication")
+	Infected       = EventKind("infected")
+	Outbreak       = EventKind("outbreak")
+	CityFell       = EventKind("city_fell")
+	Treated        = EventKind("treated")
+	RateIncreased  = EventKind("rate_increased")
+	EventPlayed    = EventKind("event_played")
+	CardRemoved    = EventKind("card_removed")
+	StepSkipped    = EventKind("step_skipped")
+	StationBuilt   = EventKind("station_built")
+	GameEnded      = EventKind("game_ended")
+	PawnPlaced     = EventKind("pawn_placed")
+	PawnMoved      = EventKind("pawn_moved")
+	CityFaded      = EventKind("city_faded")
+	CardShared     = EventKind("card_shared")
+	CityExplored   = EventKind("city_explored")
+	// EpidemicResolved and Intensified start and end an epidemic.
+	EpidemicResolved = EventKind("epidemic")
+	Intensified      = EventKind("intensified")
+)
+
+// GameEvent is a single entry in the game's event log.
+type GameEvent struct {
+	Kind    EventKind `json:"kind"`
+	Turn    int       `json:"turn"`
+	Time    time.Time `json:"time"`
+	Message string    `json:"message"`
+	// City is set on events that happen to a single city.
+	City CityName `json:"city,omitempty"`
+}
+
+type EventLog struct {
+	Events []*GameEvent `json:"events"`
+}
+
+func (gs GameState) record(kind EventKind, format string, args ...interface{}) {
+	gs.recordCity(kind, "", format, args...)
+}
+
+func (gs GameState) recordCity(kind EventKind, city CityName, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	gs.publish(GameChanged{What: message})
+	if gs.Log == nil {
+		return
+	}
+	turn := 0
+	if gs.GameTurns != nil {
+		turn = gs.GameTurns.CurTurn
+	}
+	gs.Log.Events = append(gs.Log.Events, &GameEvent{
+		Kind:    kind,
+		Turn:    turn,
+		Time:    time.Now(),
+		Message: message,
+		City:    city,
+	})
+}
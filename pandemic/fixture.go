@@ -0,0 +1,548 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// A Fixture replays a recorded game as a regression test. It starts from
+// the first save of the game and steps through the commands that produced
+// the saves after it, checking the game against a snapshot of each save.
+// Fixtures are anonymized, so they can be bundled with the tests.
+type Fixture struct {
+	Name  string        `json:"name"`
+	Start *GameState    `json:"start"`
+	Steps []FixtureStep `json:"steps"`
+}
+
+// FixtureStep is one recorded command. Saves only record the command's
+// name, so its arguments are worked out from what changed. A step without
+// arguments changed nothing, e.g. because the command was mistyped.
+type FixtureStep struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+	Expect  Snapshot `json:"expect"`
+}
+
+// Snapshot is the part of a game that fixtures check after every step.
+type Snapshot struct {
+	Outbreaks       int              `json:"outbreaks"`
+	InfectionRate   int              `json:"infection_rate"`
+	Infections      map[CityName]int `json:"infections"`
+	Quarantined     []CityName       `json:"quarantined,omitempty"`
+	Striations      [][]string       `json:"striations"`
+	Discard         []string         `json:"discard"`
+	CityCardsDrawn  int              `json:"city_cards_drawn"`
+	EpidemicsDrawn  int              `json:"epidemics_drawn"`
+	EpidemicChances [2]float64       `json:"epidemic_chances"`
+}
+
+func (gs GameState) Snapshot() Snapshot {
+	snapshot := Snapshot{
+		Outbreaks:      gs.Outbreaks,
+		InfectionRate:  gs.InfectionRate,
+		Infections:     map[CityName]int{},
+		Discard:        gs.InfectionDeck.Drawn.Members(),
+		CityCardsDrawn: len(gs.CityDeck.Drawn),
+		EpidemicsDrawn: gs.CityDeck.EpidemicsDrawn(),
+	}
+	for _, city := range *gs.Cities {
+		if city.NumInfections > 0 {
+			snapshot.Infections[city.Name] = city.NumInfections
+		}
+		if city.Quarantined {
+			snapshot.Quarantined = append(snapshot.Quarantined, city.Name)
+		}
+	}
+	for _, striation := range gs.InfectionDeck.Striations {
+		snapshot.Striations = append(snapshot.Striations, striation.Members())
+	}
+	analysis := gs.CityDeck.EpidemicAnalysis()
+	snapshot.EpidemicChances = [2]float64{analysis.FirstCardProbability, analysis.SecondCardProbability}
+	return snapshot
+}
+
+// FixtureFromSaves turns the saves in a game's save directory into a
+// fixture, in the order they were written.
+func FixtureFromSaves(name, dir string) (*Fixture, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "game_*_*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) < 2 {
+		return nil, fmt.Errorf("Need at least two saves in %v, found %v", dir, len(paths))
+	}
+	sort.Sort(bySaveTime(paths))
+	games := []*GameState{}
+	for _, path := range paths {
+		gs, err := LoadGame(path)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", path, err)
+		}
+		gs.anonymize(name)
+		games = append(games, gs)
+	}
+	fixture := &Fixture{Name: name, Start: games[0]}
+	for i := 1; i < len(games); i++ {
+		command := saveCommand(paths[i])
+		args, err := fixtureArgs(command, games[i-1], games[i])
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", paths[i], err)
+		}
+		// the save's name leaves off the ! of an epidemic entered where the
+		// city deck couldn't have one
+		if _, impossible := games[i-1].CityDeck.CanDrawEpidemic().(*ImpossibleEpidemicError); impossible && (command == "epidemic" || command == "e") {
+			command += "!"
+		}
+		// nor that of a card given by pawns the tracker didn't place together
+		if shareCommands[command] && !games[i-1].replays(FixtureStep{Command: command, Args: args}) {
+			command += "!"
+		}
+		fixture.Steps = append(fixture.Steps, FixtureStep{Command: command, Args: args, Expect: games[i].Snapshot()})
+		// the tracker moves on by itself after the command that finished
+		// a turn
+		if command != "next-turn" && command != "n" && games[i].GameTurns != nil && games[i-1].GameTurns != nil && games[i].GameTurns.CurTurn > games[i-1].GameTurns.CurTurn {
+			fixture.Steps = append(fixture.Steps, FixtureStep{Command: "next-turn", Args: []string{"next"}, Expect: games[i].Snapshot()})
+		}
+	}
+	return fixture, nil
+}
+
+// shareCommands give a card, which older saves did wherever the pawns were.
+var shareCommands = map[string]bool{"give": true, "give-card": true, "g": true}
+
+// replays is whether the step can be applied to a copy of the game.
+func (gs GameState) replays(step FixtureStep) bool {
+	trial, err := gs.Clone()
+	if err != nil {
+		return false
+	}
+	return trial.applyFixtureStep(step) == nil
+}
+
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+	if fixture.Start == nil {
+		return nil, fmt.Errorf("%v has no starting game", path)
+	}
+	return &fixture, nil
+}
+
+func (f *Fixture) Save(path string) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// Replay plays the fixture's steps from the start and reports the first
+// step where the game no longer matches the recording.
+func (f *Fixture) Replay() error {
+	gs, err := f.Start.Clone()
+	if err != nil {
+		return err
+	}
+	for i, step := range f.Steps {
+		if len(step.Args) > 0 {
+			if err := gs.applyFixtureStep(step); err != nil {
+				return fmt.Errorf("Step %v (%v %v): %v", i, step.Command, step.Args, err)
+			}
+		}
+		if got := gs.Snapshot(); !reflect.DeepEqual(got, step.Expect) {
+			return fmt.Errorf("Step %v (%v %v): expected %+v, got %+v", i, step.Command, step.Args, step.Expect, got)
+		}
+	}
+	return nil
+}
+
+// anonymize replaces everything in a save that identifies the people who
+// played it.
+func (gs *GameState) anonymize(name string) {
+	gs.GameName = name
+	gs.Log = &EventLog{}
+	gs.Metadata = nil
+	if gs.GameTurns == nil {
+		return
+	}
+	names := map[string]string{}
+	for i, player := range gs.GameTurns.PlayerOrder {
+		names[player.HumanName] = fmt.Sprintf("Player %v", i+1)
+	}
+	players := gs.GameTurns.PlayerOrder
+	for _, turn := range gs.GameTurns.Turns {
+		if turn.Player != nil {
+			players = append(players, turn.Player)
+		}
+	}
+	for _, player := range players {
+		player.HumanName = names[player.HumanName]
+		if player.Character != nil {
+			player.Character.Name = ""
+		}
+	}
+}
+
+func saveCommand(path string) string {
+	base := strings.TrimSuffix(filepath.Base(path), ".json")
+	return base[strings.LastIndex(base, "_")+1:]
+}
+
+func saveTime(path string) int64 {
+	parts := strings.Split(filepath.Base(path), "_")
+	if len(parts) < 3 {
+		return 0
+	}
+	nanos, _ := strconv.ParseInt(parts[1], 10, 64)
+	return nanos
+}
+
+type bySaveTime []string
+
+func (b bySaveTime) Len() int           { return len(b) }
+func (b bySaveTime) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b bySaveTime) Less(i, j int) bool { return saveTime(b[i]) < saveTime(b[j]) }
+
+// fixtureArgs works out the arguments of a command from the saves before
+// and after it.
+func fixtureArgs(command string, before, after *GameState) ([]string, error) {
+	switch command {
+	case "infect", "i":
+		for _, city := range after.InfectionDeck.CitiesInDrawn() {
+			if !before.InfectionDeck.Drawn.Contains(city) {
+				return []string{string(city)}, nil
+			}
+		}
+	case "epidemic", "e":
+		bottom := after.InfectionDeck.BottomStriation()
+		for _, city := range before.InfectionDeck.BottomStriation().Members() {
+			if !bottom.Contains(CityName(city)) {
+				return []string{city}, nil
+			}
+		}
+	case "city-draw", "c", "draw":
+		if len(after.CityDeck.Drawn) > len(before.CityDeck.Drawn) {
+			card := after.CityDeck.Drawn[len(after.CityDeck.Drawn)-1]
+			// a card drawn into someone else's hand names them
+			turn, err := after.GameTurns.CurrentTurn()
+			if err != nil || turn.Player.HasCard(card.Name()) {
+				return []string{string(card.Name())}, nil
+			}
+			for _, player := range after.GameTurns.PlayerOrder {
+				if player.HasCard(card.Name()) {
+					return []string{string(card.Name()), player.HumanName}, nil
+				}
+			}
+			return []string{string(card.Name())}, nil
+		}
+	case "next-turn", "n":
+		if after.GameTurns.CurTurn != before.GameTurns.CurTurn {
+			return []string{"next"}, nil
+		}
+	case "infect-rate", "r":
+		if after.InfectionRate != before.InfectionRate {
+			return []string{strconv.Itoa(after.InfectionRate)}, nil
+		}
+	case "city-infect-level", "l":
+		for _, city := range *after.Cities {
+			old, err := before.GetCity(city.Name)
+			if err != nil {
+				return nil, err
+			}
+			if old.NumInfections != city.NumInfections {
+				return []string{string(city.Name), strconv.Itoa(city.NumInfections)}, nil
+			}
+		}
+	case "quarantine", "q", "remove-quarantine", "rq":
+		for _, city := range *after.Cities {
+			old, err := before.GetCity(city.Name)
+			if err != nil {
+				return nil, err
+			}
+			if old.Quarantined != city.Quarantined {
+				return []string{string(city.Name)}, nil
+			}
+		}
+	case "station", "move-station":
+		built, removed := "", ""
+		for _, city := range *after.Cities {
+			old, err := before.GetCity(city.Name)
+			if err != nil {
+				return nil, err
+			}
+			if city.ResearchStation && !old.ResearchStation {
+				built = string(city.Name)
+			} else if !city.ResearchStation && old.ResearchStation {
+				removed = string(city.Name)
+			}
+		}
+		if built != "" && removed != "" {
+			return []string{removed, built}, nil
+		}
+		if built != "" {
+			for i, player := range after.GameTurns.PlayerOrder {
+				if len(player.Cards) < len(before.GameTurns.PlayerOrder[i].Cards) {
+					return []string{built, player.HumanName}, nil
+				}
+			}
+			return []string{built}, nil
+		}
+	case "remove-infection":
+		for _, city := range after.InfectionDeck.Removed.Members() {
+			if !before.InfectionDeck.Removed.Contains(CityName(city)) {
+				return []string{city}, nil
+			}
+		}
+	case "give-card", "g":
+		for i, player := range after.GameTurns.PlayerOrder {
+			old := before.GameTurns.PlayerOrder[i]
+			for _, card := range player.Cards {
+				if !old.HasCard(card.Name()) {
+					return []string{player.HumanName, string(card.Name())}, nil
+				}
+			}
+		}
+	case "give":
+		for i, player := range after.GameTurns.PlayerOrder {
+			for _, card := range player.Cards {
+				if before.GameTurns.PlayerOrder[i].HasCard(card.Name()) {
+					continue
+				}
+				for _, old := range before.GameTurns.PlayerOrder {
+					if old.HasCard(card.Name()) {
+						return []string{string(card.Name()), old.HumanName, player.HumanName}, nil
+					}
+				}
+			}
+		}
+	case "discard", "d":
+		if len(after.CityDeck.Discarded) > len(before.CityDeck.Discarded) {
+			card := after.CityDeck.Discarded[len(after.CityDeck.Discarded)-1]
+			return []string{string(card.Name())}, nil
+		}
+	case "action":
+		turn, old := after.currentTurn(), before.currentTurn()
+		if turn != nil && old != nil && len(turn.Actions) > len(old.Actions) {
+			return []string{turn.Actions[len(turn.Actions)-1]}, nil
+		}
+	case "end-game":
+		if after.Ended != "" && before.Ended == "" {
+			return []string{after.Ended}, nil
+		}
+	case "treat":
+		for _, city := range *after.Cities {
+			old, err := before.GetCity(city.Name)
+			if err != nil {
+				continue
+			}
+			if city.NumInfections < old.NumInfections {
+				return []string{string(city.Name), strconv.Itoa(old.NumInfections - city.NumInfections)}, nil
+			}
+			for dt, cubes := range old.OtherCubes {
+				if city.OtherCubes[dt] < cubes {
+					return []string{string(city.Name), strconv.Itoa(cubes - city.OtherCubes[dt]), string(dt)}, nil
+				}
+			}
+		}
+	case "move", "fly", "charter", "shuttle":
+		for i, player := range after.GameTurns.PlayerOrder {
+			if player.Location != before.GameTurns.PlayerOrder[i].Location {
+				return []string{string(player.Location), player.HumanName}, nil
+			}
+		}
+	case "setup":
+		if before.InfectionDeck.Drawn.Size() == 0 && after.InfectionDeck.Drawn.Size() > 0 {
+			// the cubes give the order the cities were drawn in
+			args := []string{}
+			for _, cubes := range []int{3, 2, 1} {
+				for _, city := range after.InfectionDeck.Drawn.Members() {
+					if data, err := before.GetCity(CityName(city)); err == nil && data.NumInfections == 0 {
+						if infected, _ := after.GetCity(CityName(city)); infected.NumInfections == cubes {
+							args = append(args, city)
+						}
+					}
+				}
+			}
+			return append([]string{"infect"}, args...), nil
+		}
+		for i, player := range after.GameTurns.PlayerOrder {
+			if len(player.StartCards) > len(before.GameTurns.PlayerOrder[i].StartCards) {
+				args := []string{"deal", player.HumanName}
+				for _, card := range player.StartCards {
+					args = append(args, string(card))
+				}
+				return args, nil
+			}
+		}
+	case "mark":
+		if len(after.Marked) > len(before.Marked) {
+			return []string{after.Marked[len(after.Marked)-1]}, nil
+		}
+	case "quiet-night":
+		// takes no arguments
+	case "sigterm":
+		// saved on the way out, nothing changed
+	default:
+		return nil, fmt.Errorf("Cannot work out the arguments of %v from the saves", command)
+	}
+	return nil, nil
+}
+
+// fixtureMoves are the kinds of move made by the commands that move pawns.
+var fixtureMoves = map[string]MoveKind{
+	"move":    Drive,
+	"fly":     DirectFlight,
+	"charter": CharterFlight,
+	"shuttle": ShuttleFlight,
+}
+
+func (gs *GameState) applyFixtureStep(step FixtureStep) error {
+	force := strings.HasSuffix(step.Command, "!")
+	switch strings.TrimSuffix(step.Command, "!") {
+	case "infect", "i":
+		_, err := gs.Infect(CityName(step.Args[0]))
+		return err
+	case "epidemic", "e":
+		_, err := gs.Epidemic(CityName(step.Args[0]), force)
+		return err
+	case "city-draw", "c", "draw":
+		if len(step.Args) == 1 {
+			return gs.DrawCard(CardName(step.Args[0]))
+		}
+		for _, player := range gs.GameTurns.PlayerOrder {
+			if player.HumanName == step.Args[1] {
+				return gs.DrawCardTo(CardName(step.Args[0]), player)
+			}
+		}
+		return fmt.Errorf("No player named %v", step.Args[1])
+	case "next-turn", "n":
+		_, err := gs.NextTurn()
+		return err
+	case "infect-rate", "r":
+		rate, err := strconv.Atoi(step.Args[0])
+		gs.InfectionRate = rate
+		return err
+	case "city-infect-level", "l":
+		city, err := gs.GetCity(CityName(step.Args[0]))
+		if err != nil {
+			return err
+		}
+		infections, err := strconv.Atoi(step.Args[1])
+		city.SetInfections(infections)
+		return err
+	case "quarantine", "q":
+		return gs.Quarantine(CityName(step.Args[0]))
+	case "remove-quarantine", "rq":
+		return gs.RemoveQuarantine(CityName(step.Args[0]))
+	case "remove-infection":
+		return gs.RemoveInfectionCard(CityName(step.Args[0]))
+	case "quiet-night":
+		return gs.SkipInfectionStep()
+	case "action":
+		return gs.TakeAction(step.Args[0])
+	case "end-game":
+		// conceding doesn't change how a lost or won game ends, and a
+		// conceded one may have ended with the city deck run out
+		return gs.EndGame(true)
+	case "mark":
+		return gs.Mark(step.Args[0])
+	case "treat":
+		times, err := strconv.Atoi(step.Args[1])
+		if err != nil {
+			return err
+		}
+		turn, err := gs.GameTurns.CurrentTurn()
+		if err != nil {
+			return err
+		}
+		if len(step.Args) == 3 {
+			_, _, err = gs.TreatDisease(CityName(step.Args[0]), DiseaseType(step.Args[2]), times, turn.Player)
+		} else {
+			_, _, err = gs.Treat(CityName(step.Args[0]), times, turn.Player)
+		}
+		return err
+	case "move", "fly", "charter", "shuttle":
+		for _, player := range gs.GameTurns.PlayerOrder {
+			if player.HumanName == step.Args[1] {
+				_, err := gs.MovePawn(player, fixtureMoves[step.Command], CityName(step.Args[0]))
+				return err
+			}
+		}
+		return fmt.Errorf("No player named %v", step.Args[1])
+	case "setup":
+		if step.Args[0] == "infect" {
+			cities := []CityName{}
+			for _, city := range step.Args[1:] {
+				cities = append(cities, CityName(city))
+			}
+			return gs.InitialInfection(cities)
+		}
+		cards := []CardName{}
+		for _, card := range step.Args[2:] {
+			cards = append(cards, CardName(card))
+		}
+		for _, player := range gs.GameTurns.PlayerOrder {
+			if player.HumanName == step.Args[1] {
+				return gs.DealStartingHand(player, cards)
+			}
+		}
+		return fmt.Errorf("No player named %v", step.Args[1])
+	case "station":
+		var builder *Player
+		if len(step.Args) > 1 {
+			for _, player := range gs.GameTurns.PlayerOrder {
+				if player.HumanName == step.Args[1] {
+					builder = player
+				}
+			}
+		}
+		return gs.BuildStation(builder, CityName(step.Args[0]))
+	case "move-station":
+		return gs.MoveStation(CityName(step.Args[0]), CityName(step.Args[1]))
+	case "give-card", "g":
+		from, err := gs.GameTurns.CurrentTurn()
+		if err != nil {
+			return err
+		}
+		for _, to := range gs.GameTurns.PlayerOrder {
+			if to.HumanName == step.Args[0] {
+				return gs.ShareKnowledge(CardName(step.Args[1]), from.Player, to, force)
+			}
+		}
+		return fmt.Errorf("No player named %v", step.Args[0])
+	case "give":
+		var from, to *Player
+		for _, player := range gs.GameTurns.PlayerOrder {
+			if player.HumanName == step.Args[1] {
+				from = player
+			}
+			if player.HumanName == step.Args[2] {
+				to = player
+			}
+		}
+		if from == nil || to == nil {
+			return fmt.Errorf("No players named %v and %v", step.Args[1], step.Args[2])
+		}
+		return gs.ShareKnowledge(CardName(step.Args[0]), from, to, force)
+	case "discard", "d":
+		from, err := gs.GameTurns.CurrentTurn()
+		if err != nil {
+			return err
+		}
+		return gs.Discard(from.Player, CardName(step.Args[0]))
+	}
+	return fmt.Errorf("Cannot replay %v", step.Command)
+}
@@ -0,0 +1,82 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFixtures replays every game recorded in testdata/fixtures. Add one
+// with the fixture command whenever a real game turns up a bug.
+func TestFixtures(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "fixtures", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("Expected at least one fixture")
+	}
+	for _, path := range paths {
+		fixture, err := LoadFixture(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := fixture.Replay(); err != nil {
+			t.Errorf("%v: %v", fixture.Name, err)
+		}
+	}
+}
+
+func TestFixtureFromSaves(t *testing.T) {
+	fixture, err := FixtureFromSaves("example", filepath.Join("..", "sep"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	players := fixture.Start.GameTurns.PlayerOrder
+	for _, turn := range fixture.Start.GameTurns.Turns {
+		players = append(players, turn.Player)
+	}
+	for _, player := range players {
+		if !strings.HasPrefix(player.HumanName, "Player ") {
+			t.Fatalf("Expected player names to be anonymized, got %v", player.HumanName)
+		}
+	}
+	if err := fixture.Replay(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFixtureFromSavesWithoutTurns(t *testing.T) {
+	gs, err := LoadGame(filepath.Join("..", "sep", "game_1477535977414903635_i.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "fixture")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// a save from before the players were set up has no turns
+	gs.GameTurns = nil
+	for i, save := range []string{"game_1_i.json", "game_2_r.json"} {
+		gs.InfectionRate = 2 + i
+		data, err := json.Marshal(gs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, save), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fixture, err := FixtureFromSaves("turnless", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixture.Steps) != 1 || fixture.Steps[0].Command != "r" {
+		t.Fatalf("Expected one infect rate step, got %v", fixture.Steps)
+	}
+}
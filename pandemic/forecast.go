@@ -0,0 +1,131 @@
+package pandemic
+
+import (
+	"fmt"
+)
+
+// ForecastCards is how many infection cards the Forecast event looks at.
+const ForecastCards = 6
+
+// Forecast records a Forecast on the game's infection deck, see
+// InfectionDeck.Forecast.
+func (gs GameState) Forecast(seen []CityName, ordered int) error {
+	if err := gs.InfectionDeck.Forecast(seen, ordered); err != nil {
+		return err
+	}
+	gs.changed("forecast put back %v cards", len(seen))
+	return nil
+}
+
+// Forecast records the top of the infection deck after a Forecast. seen
+// lists the cards that were looked at, top first, and ordered is how many
+// of them, from the top, went back in a known order. The rest went back
+// just below them in an order nobody noted. Fewer cards than ForecastCards
+// can be seen when the deck is nearly empty.
+func (d *InfectionDeck) Forecast(seen []CityName, ordered int) error {
+	if len(seen) == 0 {
+		return fmt.Errorf("The forecast must list the cards that were seen")
+	}
+	if len(seen) > ForecastCards {
+		return fmt.Errorf("Forecast only looks at %v cards, not %v", ForecastCards, len(seen))
+	}
+	if len(seen) > d.Size() {
+		return fmt.Errorf("There are only %v cards left in the infection deck", d.Size())
+	}
+	if ordered < 0 || ordered > len(seen) {
+		return fmt.Errorf("Cannot know the order of %v of %v cards", ordered, len(seen))
+	}
+	unseen := Set{}
+	for _, city := range seen {
+		if unseen.Contains(city) {
+			return fmt.Errorf("%v was listed twice", city)
+		}
+		unseen.Add(city)
+	}
+	// the seen cards must be the top of the deck: whole striations, then
+	// part of the next one
+	var rest []Set
+	for i, striation := range d.Striations {
+		if unseen.Size() == 0 {
+			rest = d.Striations[i:]
+			break
+		}
+		left := Set{}
+		for _, member := range striation.Members() {
+			if _, ok := unseen.Remove(CityName(member)); !ok {
+				left.Add(CityName(member))
+			}
+		}
+		if left.Size() > 0 && unseen.Size() > 0 {
+			return fmt.Errorf("%v cannot be in the top %v cards, %v cards of infection %v are above it", unseen.Members()[0], len(seen), left.Size(), i)
+		}
+		if left.Size() > 0 {
+			rest = append([]Set{left}, d.Striations[i+1:]...)
+			break
+		}
+	}
+	if unseen.Size() > 0 {
+		return fmt.Errorf("%v is not in the infection deck", unseen.Members()[0])
+	}
+	top := []Set{}
+	for _, city := range seen[:ordered] {
+		top = append(top, Init(city))
+	}
+	if ordered < len(seen) {
+		unordered := Set{}
+		for _, city := range seen[ordered:] {
+			unordered.Add(city)
+		}
+		top = append(top, unordered)
+	}
+	d.Striations = append(top, rest...)
+	if d.Forecasted == nil {
+		d.Forecasted = Set{}
+	}
+	for _, city := range seen {
+		d.Forecasted.Add(city)
+		delete(d.Weights, city)
+	}
+	return nil
+}
+
+// FromForecast is whether the card's place comes from a forecast rather than
+// from the striations alone.
+func (d *InfectionDeck) FromForecast(city CityName) bool {
+	return d.Forecasted.Contains(city)
+}
+
+// A ForecastSlot is a run of places at the top of the infection deck, from
+// First to Last counting from 0, and the cards that could be in them: all
+// of them when they fill the run exactly, or some of them when the run
+// only reaches into their striation.
+type ForecastSlot struct {
+	First, Last int
+	Cities      []CityName
+}
+
+// Complete is whether the places hold every card listed, rather than only
+// some of them.
+func (s ForecastSlot) Complete() bool {
+	return len(s.Cities) == s.Last-s.First+1
+}
+
+// ForecastPreview lists what the striations know about the cards a Forecast
+// would look at, top first.
+func (d *InfectionDeck) ForecastPreview() []ForecastSlot {
+	slots := []ForecastSlot{}
+	place := 0
+	for i := range d.Striations {
+		if place >= ForecastCards {
+			break
+		}
+		cities := d.CitiesInStriation(i)
+		last := place + len(cities) - 1
+		if last >= ForecastCards {
+			last = ForecastCards - 1
+		}
+		slots = append(slots, ForecastSlot{First: place, Last: last, Cities: cities})
+		place += len(cities)
+	}
+	return slots
+}
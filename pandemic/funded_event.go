@@ -1,5 +1,197 @@
 package pandemic
 
+import (
+	"fmt"
+)
+
 type FundedEvent struct {
 	Name FundedEventName `json:"name"`
 }
+
+const (
+	AirliftEvent             = FundedEventName("airlift")
+	OneQuietNightEvent       = FundedEventName("one quiet night")
+	ResilientPopulationEvent = FundedEventName("resilient population")
+	ForecastEvent            = FundedEventName("forecast")
+	GovernmentGrantEvent     = FundedEventName("government grant")
+)
+
+// BaseEvents are the event cards of the base game, shuffled into the city
+// deck alongside any Legacy funded events.
+var BaseEvents = []FundedEventName{AirliftEvent, OneQuietNightEvent, ResilientPopulationEvent, ForecastEvent, GovernmentGrantEvent}
+
+// withBaseEvents adds the base game's events to the funded events, unless
+// the game already funds one of the same name.
+func withBaseEvents(funded []*FundedEvent) []*FundedEvent {
+	events := []*FundedEvent{}
+	named := Set{}
+	for _, event := range funded {
+		named.Add(event.Name)
+	}
+	for _, name := range BaseEvents {
+		if !named.Contains(name) {
+			events = append(events, &FundedEvent{Name: name})
+		}
+	}
+	return append(events, funded...)
+}
+
+// An EventStatus is where one event card is and who can play it.
+type EventStatus struct {
+	Name     FundedEventName
+	Location CardLocation
+	// Holder is the player holding the card in hand or stored on the
+	// Contingency Planner's role card.
+	Holder *Player
+	Stored bool
+}
+
+// Played events are on the discard pile or out of the game.
+func (s EventStatus) Played() bool {
+	return s.Holder == nil && (s.Location == InDiscard || s.Location == OutOfTheGame)
+}
+
+// EventLedger lists every event card in the game, in deck order, with where
+// it is now.
+func (gs GameState) EventLedger() []EventStatus {
+	ledger := []EventStatus{}
+	available := gs.AvailableEvents()
+	for _, card := range gs.CityDeck.All {
+		if !card.IsFundedEvent() {
+			continue
+		}
+		status := EventStatus{Name: card.FundedEventName, Location: gs.CityDeck.Location(card.Name())}
+		if holder, ok := available[card.FundedEventName]; ok {
+			status.Holder = holder
+			status.Stored = holder.StoredEvent != nil && holder.StoredEvent.FundedEventName == card.FundedEventName
+		}
+		ledger = append(ledger, status)
+	}
+	return ledger
+}
+
+// StoreEvent takes an event card from the player discard pile and places it
+// on the Contingency Planner's role card.
+func (gs GameState) StoreEvent(player *Player, name CardName) error {
+	if !player.IsCharacter(ContingencyPlanner) {
+		return fmt.Errorf("Only the Contingency Planner can store events, %v is not the Contingency Planner", player.HumanName)
+	}
+	if player.StoredEvent != nil {
+		return fmt.Errorf("%v is already storing %v", player.HumanName, player.StoredEvent.Name())
+	}
+	card, err := gs.CityDeck.GetCard(name)
+	if err != nil {
+		return err
+	}
+	if !card.IsFundedEvent() {
+		return fmt.Errorf("%v is not an event card", name)
+	}
+	if loc := gs.CityDeck.Location(name); loc != InDiscard {
+		return fmt.Errorf("%v must be on the discard pile to be stored, it is in the %v", name, loc)
+	}
+	gs.CityDeck.undiscard(*card)
+	player.StoredEvent = card
+	gs.changed("%v stored %v", player.HumanName, name)
+	return nil
+}
+
+// PlayEvent plays an event from the player's hand, moving it to the discard
+// pile, or from the Contingency Planner's stored slot, removing it from the
+// game. One Quiet Night also skips the current turn's infection step.
+func (gs GameState) PlayEvent(player *Player, name CardName) error {
+	if player.StoredEvent != nil && player.StoredEvent.Name() == name {
+		event := player.StoredEvent.FundedEventName
+		if err := gs.canResolve(event); err != nil {
+			return err
+		}
+		player.StoredEvent = nil
+		gs.record(EventPlayed, "%v played %v from the Contingency Planner's card", player.HumanName, name)
+		if err := gs.CityDeck.Remove(name); err != nil {
+			return err
+		}
+		return gs.resolve(event)
+	}
+	card, err := gs.CityDeck.GetCard(name)
+	if err != nil {
+		return err
+	}
+	if !card.IsFundedEvent() {
+		return fmt.Errorf("%v is not an event card", name)
+	}
+	if err := gs.canResolve(card.FundedEventName); err != nil {
+		return err
+	}
+	if err := gs.Discard(player, name); err != nil {
+		return err
+	}
+	gs.record(EventPlayed, "%v played %v", player.HumanName, name)
+	return gs.resolve(card.FundedEventName)
+}
+
+// canResolve checks that an event the engine resolves by itself can be
+// played right now.
+func (gs GameState) canResolve(event FundedEventName) error {
+	if event == OneQuietNightEvent {
+		if _, err := gs.skippableTurn(); err != nil {
+			return fmt.Errorf("Can't play %v: %v", event, err)
+		}
+	}
+	return nil
+}
+
+func (gs GameState) resolve(event FundedEventName) error {
+	if event == OneQuietNightEvent {
+		return gs.SkipInfectionStep()
+	}
+	return nil
+}
+
+// RemoveInfectionCard resolves Resilient Population, taking the city's card
+// from the infection discard pile out of the game.
+func (gs GameState) RemoveInfectionCard(cn CityName) error {
+	if err := gs.InfectionDeck.RemoveFromGame(cn); err != nil {
+		return err
+	}
+	gs.recordCity(CardRemoved, cn, "%v's infection card was removed from the game", cn)
+	return nil
+}
+
+// WhatIf is a copy of the game as it would be with the event played, so
+// its odds can be compared with the game's before the event is spent. The
+// city is the card Resilient Population removes. Only events that change
+// the infection odds can be tried.
+func (gs GameState) WhatIf(event FundedEventName, cn CityName) (*GameState, error) {
+	clone, err := gs.Clone()
+	if err != nil {
+		return nil, err
+	}
+	switch event {
+	case ResilientPopulationEvent:
+		err = clone.RemoveInfectionCard(cn)
+	case OneQuietNightEvent:
+		err = clone.SkipInfectionStep()
+	default:
+		err = fmt.Errorf("%v doesn't change the infection odds", event)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// AvailableEvents is the ledger of every event the team could play right
+// now, along with who holds it.
+func (gs GameState) AvailableEvents() map[FundedEventName]*Player {
+	events := map[FundedEventName]*Player{}
+	for _, player := range gs.GameTurns.PlayerOrder {
+		for _, card := range player.Cards {
+			if card.IsFundedEvent() {
+				events[card.FundedEventName] = player
+			}
+		}
+		if player.StoredEvent != nil {
+			events[player.StoredEvent.FundedEventName] = player
+		}
+	}
+	return events
+}
@@ -0,0 +1,219 @@
+package pandemic
+
+import (
+	"math"
+	"testing"
+)
+
+func TestContingencyPlannerStoredEvent(t *testing.T) {
+	cities := Cities([]*City{{Name: "a"}, {Name: "b"}})
+	deck, err := cities.GenerateCityDeck(1, []*FundedEvent{{Name: "forecast"}}, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	planner := &Player{HumanName: "p1", Character: &Character{Type: ContingencyPlanner}}
+	other := &Player{HumanName: "p2"}
+	gs := GameState{Cities: &cities, CityDeck: &deck, GameTurns: InitGameTurns(planner, other)}
+
+	if err := gs.DrawCard("forecast"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.StoreEvent(planner, "forecast"); err == nil {
+		t.Fatal("Should not be able to store an event that is not on the discard pile")
+	}
+	if err := gs.PlayEvent(planner, "forecast"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.StoreEvent(other, "forecast"); err == nil {
+		t.Fatal("Only the contingency planner should be able to store events")
+	}
+	if err := gs.StoreEvent(planner, "forecast"); err != nil {
+		t.Fatal(err)
+	}
+	if holder := gs.AvailableEvents()["forecast"]; holder != planner {
+		t.Fatalf("Expected the planner to hold forecast, got %+v", holder)
+	}
+	if err := gs.PlayEvent(planner, "forecast"); err != nil {
+		t.Fatal(err)
+	}
+	if planner.StoredEvent != nil || deck.Location("forecast") != OutOfTheGame {
+		t.Fatalf("Expected a stored event to be removed from the game after use, it is in the %v", deck.Location("forecast"))
+	}
+}
+
+func TestProbabilityOfEventBeforeEpidemic(t *testing.T) {
+	cities := Cities([]*City{{Name: "a"}, {Name: "b"}, {Name: "c"}})
+	deck, err := cities.GenerateCityDeck(2, []*FundedEvent{{Name: "resilient population"}}, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectOdds := func(expected float64) {
+		odds, err := deck.ProbabilityOfEventBeforeEpidemic("resilient population")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(odds-expected) > 0.0001 {
+			t.Fatalf("Expected %.4f odds of drawing the event first, got %.4f", expected, odds)
+		}
+	}
+
+	// two piles of three: the epidemic is equally likely to be any card of
+	// the first pile, and the event any of the four other cards.
+	expectOdds(0.25)
+	if _, err := deck.DrawCard("a"); err != nil {
+		t.Fatal(err)
+	}
+	expectOdds(1.0 / 6.0)
+	if err := deck.DrawEpidemic(); err != nil {
+		t.Fatal(err)
+	}
+	// one card left in the first pile, then the epidemic somewhere in the second
+	expectOdds(2.0 / 3.0)
+
+	if _, err := deck.ProbabilityOfEventBeforeEpidemic("b"); err == nil {
+		t.Fatal("Should not give odds for a city card")
+	}
+	if _, err := deck.DrawCard("resilient population"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := deck.ProbabilityOfEventBeforeEpidemic("resilient population"); err == nil {
+		t.Fatal("Should not give odds for an event that was already drawn")
+	}
+}
+
+func TestEventLedger(t *testing.T) {
+	cities := Cities([]*City{{Name: "a"}, {Name: "b"}})
+	// forecast is funded as well as in the base game, but only shuffled in once
+	events := withBaseEvents([]*FundedEvent{{Name: "forecast"}, {Name: "rapid vaccine deployment"}})
+	deck, err := cities.GenerateCityDeck(1, events, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deck.NumFundedEvents() != 6 || deck.Total() != 9 {
+		t.Fatalf("Expected 6 events among 9 cards, got %v of %v", deck.NumFundedEvents(), deck.Total())
+	}
+	player := &Player{HumanName: "p1"}
+	gs := GameState{Cities: &cities, CityDeck: &deck, GameTurns: InitGameTurns(player, &Player{HumanName: "p2"}), Log: &EventLog{}}
+	if err := gs.DrawCard("airlift"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.DrawCard("government grant"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.PlayEvent(player, "airlift"); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses := map[FundedEventName]EventStatus{}
+	for _, status := range gs.EventLedger() {
+		statuses[status.Name] = status
+	}
+	if len(statuses) != 6 {
+		t.Fatalf("Expected every event in the ledger, got %v", statuses)
+	}
+	if !statuses["airlift"].Played() {
+		t.Fatalf("Expected airlift to be played, got %+v", statuses["airlift"])
+	}
+	if grant := statuses["government grant"]; grant.Holder != player || grant.Played() {
+		t.Fatalf("Expected p1 to hold government grant, got %+v", grant)
+	}
+	if forecast := statuses["forecast"]; forecast.Location != InDeck || forecast.Played() {
+		t.Fatalf("Expected forecast to be in the deck, got %+v", forecast)
+	}
+	if last := gs.Log.Events[len(gs.Log.Events)-1]; last.Kind != EventPlayed {
+		t.Fatalf("Expected the play to be logged, got %+v", last)
+	}
+}
+
+func TestOneQuietNight(t *testing.T) {
+	cities := Cities([]*City{{Name: "a", Disease: Blue.Type}, {Name: "b", Disease: Blue.Type}})
+	deck, err := cities.GenerateCityDeck(1, []*FundedEvent{{Name: OneQuietNightEvent}}, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := &Player{HumanName: "p1"}
+	gs := &GameState{
+		Cities:        &cities,
+		CityDeck:      &deck,
+		InfectionDeck: NewInfectionDeck(cities.CityNames()),
+		InfectionRate: 2,
+		GameTurns:     InitGameTurns(player, &Player{HumanName: "p2"}),
+		Log:           &EventLog{},
+	}
+	if err := gs.DrawCard("one quiet night"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.PlayEvent(player, "one quiet night"); err != nil {
+		t.Fatal(err)
+	}
+	if !gs.QuietNight() || gs.InfectionsThisTurn() != 0 {
+		t.Fatalf("Expected this turn's infection step to be skipped, %v infections left", gs.InfectionsThisTurn())
+	}
+	if forecast := gs.ForecastPhases(1, -1); !forecast[0].Skipped || forecast[0].Total() != 0 {
+		t.Fatalf("Expected no draws this turn, got %+v", forecast[0])
+	}
+	if _, err := gs.Infect("a"); err == nil {
+		t.Fatal("Should not infect during a quiet night")
+	}
+	if err := gs.SkipInfectionStep(); err == nil {
+		t.Fatal("Should not skip the same infection step twice")
+	}
+	if _, err := gs.NextTurn(); err != nil {
+		t.Fatal(err)
+	}
+	if gs.QuietNight() || gs.InfectionsThisTurn() != 2 {
+		t.Fatal("Expected the next turn to infect again")
+	}
+	if _, err := gs.Infect("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.SkipInfectionStep(); err == nil {
+		t.Fatal("Should not skip an infection step that has started")
+	}
+}
+
+func TestWhatIf(t *testing.T) {
+	cities := Cities([]*City{{Name: "a", Disease: Blue.Type}, {Name: "b", Disease: Blue.Type}, {Name: "c", Disease: Blue.Type}})
+	deck, err := cities.GenerateCityDeck(1, nil, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := &GameState{
+		Cities:        &cities,
+		CityDeck:      &deck,
+		InfectionDeck: NewInfectionDeck(cities.CityNames()),
+		InfectionRate: 2,
+		GameTurns:     InitGameTurns(&Player{HumanName: "p1"}, &Player{HumanName: "p2"}),
+		Log:           &EventLog{},
+	}
+	quiet, err := gs.WhatIf(OneQuietNightEvent, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// an epidemic can still pull a from the bottom of the deck
+	if quiet.ProbabilityOfCity("a") >= gs.ProbabilityOfCity("a") {
+		t.Fatalf("Expected the quiet night to make a safer, got %v", quiet.ProbabilityOfCity("a"))
+	}
+	if gs.QuietNight() {
+		t.Fatal("Expected the game itself to keep its infection step")
+	}
+	if _, err := gs.WhatIf(ResilientPopulationEvent, "a"); err == nil {
+		t.Fatal("Should not remove a card that isn't in the discard pile")
+	}
+	if _, err := gs.Infect("a"); err != nil {
+		t.Fatal(err)
+	}
+	resilient, err := gs.WhatIf(ResilientPopulationEvent, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resilient.ProbabilityOfCity("a") != 0.0 || gs.ProbabilityOfCity("a") == 0.0 {
+		t.Fatalf("Expected removing a's card to take away the epidemic's chance of it, got %v", resilient.ProbabilityOfCity("a"))
+	}
+	if !gs.InfectionDeck.Drawn.Contains(CityName("a")) {
+		t.Fatal("Expected a's card to stay in the game's discard pile")
+	}
+	if _, err := gs.WhatIf(AirliftEvent, ""); err == nil {
+		t.Fatal("Should not try an event that doesn't change the odds")
+	}
+}
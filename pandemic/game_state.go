@@ -1,6 +1,7 @@
 package pandemic
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -10,7 +11,12 @@ import (
 	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic/combinations"
 )
 
+// EpidemicsPerGame is how many epidemics a ruleset shuffles in unless the
+// new game file asks for more or fewer, between MinEpidemics and
+// MaxEpidemics.
 const EpidemicsPerGame = 5
+const MinEpidemics = 4
+const MaxEpidemics = 7
 const CityCardsPerTurn = 2
 
 type GameState struct {
@@ -22,41 +28,137 @@ type GameState struct {
 	Outbreaks     int            `json:"outbreaks"`
 	GameName      string         `json:"game_name"`
 	GameTurns     *GameTurns     `json:"game_turns"`
+	Log           *EventLog      `json:"log,omitempty"`
+	Ruleset       string         `json:"ruleset,omitempty"`
+	Supply        *Supply        `json:"supply,omitempty"`
+	Modules       []string       `json:"modules,omitempty"`
+	Regions       []*Region      `json:"regions,omitempty"`
+	Railroads     []Railroad     `json:"railroads,omitempty"`
+	Metadata      *GameMetadata  `json:"metadata,omitempty"`
+	// ResolveOutbreaks spreads outbreaks to neighbors and moves the outbreak
+	// marker. Games saved before the tracker did this had them entered by
+	// hand, so they carry on that way.
+	ResolveOutbreaks bool `json:"resolve_outbreaks,omitempty"`
+	// Ended is why the game ended, set once the table acknowledges it with
+	// EndGame.
+	Ended string `json:"ended,omitempty"`
+	// WinConditions replace the ruleset's, e.g. for a month with its own
+	// objectives. Marked are the ones the table has marked done.
+	WinConditions []WinCondition `json:"win_conditions,omitempty"`
+	Marked        []string       `json:"marked,omitempty"`
+	// Unlocks are the campaign's spoilers revealed so far, e.g. FadedUnlock.
+	Unlocks []string `json:"unlocks,omitempty"`
+
+	bus *EventBus
 }
 
 type NewGameSettings struct {
+	Ruleset      string         `json:"ruleset,omitempty"`
+	Supply       *Supply        `json:"supply,omitempty"`
+	Modules      []string       `json:"modules,omitempty"`
+	Regions      []*Region      `json:"regions,omitempty"`
 	Cities       Cities         `json:"cities"`
 	Players      []*Player      `json:"players"`
 	FundedEvents []*FundedEvent `json:"funded_events"`
+	// BaseEvents shuffles the base game's event cards in with the funded
+	// events.
+	BaseEvents bool `json:"base_events,omitempty"`
+	// CityCardCopies adds a second city card for each city listed.
+	CityCardCopies []CityName `json:"city_card_copies,omitempty"`
+	// WinConditions replace the ruleset's for this game.
+	WinConditions []WinCondition `json:"win_conditions,omitempty"`
+	// Epidemics replaces the ruleset's number of epidemics, e.g. 4 for an
+	// easier game or 6 or 7 for a harder one.
+	Epidemics int `json:"epidemics,omitempty"`
+	// FundedEventCount shuffles in only the first funded events listed,
+	// when fewer were funded than the file lists. 0 shuffles in all of them.
+	FundedEventCount int `json:"funded_event_count,omitempty"`
+	// Unlocks are the spoilers the campaign has revealed, carried from game
+	// to game, e.g. "faded" once the Faded's rules are known.
+	Unlocks []string `json:"unlocks,omitempty"`
 }
 
-func NewGame(newGameFile string, gameName string) (*GameState, error) {
+func LoadNewGameSettings(newGameFile string) (NewGameSettings, error) {
 	var newGameSettings NewGameSettings
 	newGameData, err := ioutil.ReadFile(newGameFile)
 	if err != nil {
-		return nil, fmt.Errorf("Could not read new game file at %v: %v", newGameFile, err)
+		return newGameSettings, fmt.Errorf("Could not read new game file at %v: %v", newGameFile, err)
 	}
 	err = json.Unmarshal(newGameData, &newGameSettings)
 	if err != nil {
-		return nil, fmt.Errorf("Invalid new game JSON file at %v: %v", newGameFile, err)
+		return newGameSettings, fmt.Errorf("Invalid new game JSON file at %v: %v", newGameFile, err)
+	}
+	return newGameSettings, nil
+}
+
+func (s NewGameSettings) Save(newGameFile string) error {
+	data, err := json.MarshalIndent(s, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(newGameFile, data, 0644)
+}
+
+func NewGame(newGameFile string, gameName string) (*GameState, error) {
+	newGameSettings, err := LoadNewGameSettings(newGameFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewGameFrom(newGameSettings, gameName)
+}
+
+// NewGameFrom starts a game from settings already loaded, e.g. with the
+// epidemics changed on the command line.
+func NewGameFrom(newGameSettings NewGameSettings, gameName string) (*GameState, error) {
+	rules, err := GetRuleset(newGameSettings.Ruleset)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range newGameSettings.Modules {
+		if _, err := GetModule(name); err != nil {
+			return nil, err
+		}
+	}
+	if err := CheckWinConditions(newGameSettings.WinConditions); err != nil {
+		return nil, err
 	}
 	cities := Cities(newGameSettings.Cities)
 	players := newGameSettings.Players
 
+	// players without start cards are dealt them with setup
 	excludeFromCityDeck := Set{}
+	startCards := 0
 	for _, player := range players {
-		if len(player.StartCards) != 2 {
-			return nil, fmt.Errorf("Each player must start with 2 city cards")
+		if hand := StartingHandSize(len(players)); len(player.StartCards) != 0 && len(player.StartCards) != hand {
+			return nil, fmt.Errorf("Each of %v players must start with %v city cards, or none to deal them with setup", len(players), hand)
 		}
 		for _, cityName := range player.StartCards {
 			excludeFromCityDeck.Add(cityName)
 		}
+		startCards += len(player.StartCards)
 	}
-	if len(excludeFromCityDeck) != 2*len(players) {
+	if len(excludeFromCityDeck) != startCards {
 		return nil, fmt.Errorf("Duplicate cities detected, check the start information: %+v", excludeFromCityDeck)
 	}
 
-	cityDeck, err := cities.GenerateCityDeck(EpidemicsPerGame, newGameSettings.FundedEvents, excludeFromCityDeck)
+	epidemics := rules.EpidemicsPerGame()
+	if newGameSettings.Epidemics != 0 {
+		epidemics = newGameSettings.Epidemics
+	}
+	if epidemics < MinEpidemics || epidemics > MaxEpidemics {
+		return nil, fmt.Errorf("A game has between %v and %v epidemics, not %v", MinEpidemics, MaxEpidemics, epidemics)
+	}
+	if count := newGameSettings.FundedEventCount; count != 0 {
+		if count < 0 || count > len(newGameSettings.FundedEvents) {
+			return nil, fmt.Errorf("Can't shuffle in %v funded events, the new game file lists %v", count, len(newGameSettings.FundedEvents))
+		}
+		newGameSettings.FundedEvents = newGameSettings.FundedEvents[:count]
+	}
+	events := newGameSettings.FundedEvents
+	if newGameSettings.BaseEvents {
+		events = withBaseEvents(events)
+	}
+	cityDeck, err := cities.Explored().GenerateCityDeckWith(epidemics, events, excludeFromCityDeck, newGameSettings.CityCardCopies)
 	if err != nil {
 		return nil, err
 	}
@@ -71,68 +173,107 @@ func NewGame(newGameFile string, gameName string) (*GameState, error) {
 		}
 	}
 
-	infectionDeck := NewInfectionDeck(cities.CityNames())
+	infectionDeck := NewInfectionDeck(cities.Explored().CityNames())
 	return &GameState{
 		Cities:        &cities,
-		DiseaseData:   []DiseaseData{Yellow, Red, Black, Blue, Faded},
+		DiseaseData:   rules.Diseases(),
 		CityDeck:      &cityDeck,
 		InfectionDeck: infectionDeck,
 		InfectionRate: 2,
 		Outbreaks:     0,
 		GameName:      gameName,
 		GameTurns:     InitGameTurns(players...),
+		Log:           &EventLog{},
+		Ruleset:       rules.Name(),
+		Supply:        newGameSettings.Supply,
+		Modules:       newGameSettings.Modules,
+		Unlocks:       newGameSettings.Unlocks,
+		Regions:       newGameSettings.Regions,
+		Metadata:      newMetadata(gameName, newGameSettings, epidemics),
+		WinConditions: newGameSettings.WinConditions,
+
+		ResolveOutbreaks: true,
 	}, nil
 }
 
 func LoadGame(gameFile string) (*GameState, error) {
+	return loadGame(gameFile, false)
+}
+
+// LoadGameStrict loads a save like LoadGame, but refuses fields this
+// version doesn't know and saves that break the game's invariants, so a
+// hand edited or mismatched save fails at once instead of quietly skewing
+// the odds.
+func LoadGameStrict(gameFile string) (*GameState, error) {
+	return loadGame(gameFile, true)
+}
+
+func loadGame(gameFile string, strict bool) (*GameState, error) {
 	var gameState GameState
 	data, err := ioutil.ReadFile(gameFile)
 	if err != nil {
 		return nil, err
 	}
-	err = json.Unmarshal(data, &gameState)
+	if strict {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&gameState)
+	} else {
+		err = json.Unmarshal(data, &gameState)
+	}
 	if err != nil {
 		return nil, err
 	}
+	if gameState.Log == nil {
+		// saves from before the event log existed
+		gameState.Log = &EventLog{}
+	}
+	if _, err := GetRuleset(gameState.Ruleset); err != nil {
+		return nil, err
+	}
+	if strict {
+		if problems := gameState.CheckInvariants(); len(problems) > 0 {
+			return nil, fmt.Errorf("%v is inconsistent: %v", gameFile, strings.Join(problems, "; "))
+		}
+	}
 	return &gameState, nil
 }
 
 func (gs GameState) ProbabilityOfCuring(player *Player, dt DiseaseType) float64 {
 	// (diseaseColor choose requiredToCure)*(notDiseaseColor choose totalLessRequired)/(allCards choose totalExpectedDraws)
 	remainingCards := gs.CityDeck.RemainingCardsWith(dt, gs.Cities)
-	// TODO: make disease curability more programatic
-	totalRequired := 5
-	if dt == Red.Type || dt == Black.Type {
-		totalRequired = 4
-	}
-	for _, card := range player.Cards {
-		if !card.IsCity() {
-			continue
-		}
-		city, err := gs.Cities.GetCity(card.CityName)
-		if err != nil {
-			panic("City card with no corresponding city: " + card.CityName)
-		}
-		if city.Disease == dt {
-			totalRequired--
-		}
+	totalRequired, canCure := gs.CardsToCure(player, dt)
+	if !canCure {
+		return 0.0
 	}
-	if player.Character != nil {
-		if player.Character.Type == Scientist {
-			totalRequired--
-		} else if player.Character.Type == Colonel {
-			totalRequired += 2
-		} else if player.Character.Type == Soldier {
-			return 0.0
-		}
+	totalRequired -= gs.CardsHeldWith(player, dt)
+
+	if totalRequired > 0 && gs.CityDeck.AvailableCardsWith(dt, gs.Cities) < totalRequired {
+		// too many cards of this color have been discarded
+		return 0.0
 	}
 
 	allRemaining := gs.CityDeck.RemainingCards()
-	drawsRemaining := 2 * (gs.GameTurns.RemainingTurnsFor(allRemaining, player.HumanName) - 1) // you don't get to use your last draw
+	drawsRemaining := 2 * (gs.GameTurns.RemainingTurnsFor(gs.CityDeck.CardsLeftInDeck(), player.HumanName) - 1) // you don't get to use your last draw
+	// unknown cards in hand are as good as extra draws from the unseen cards
+	drawsRemaining += player.UnknownCards
 	return combinations.AtLeastNDraws(allRemaining, drawsRemaining, totalRequired, remainingCards)
 }
 
 func (gs GameState) DrawCard(cn CardName) error {
+	curTurn, err := gs.GameTurns.CurrentTurn()
+	if err != nil {
+		return err
+	}
+	return gs.DrawCardTo(cn, curTurn.Player)
+}
+
+// DrawCardTo draws a city deck card into the player's hand. It counts as one
+// of the current turn's draws whoever ends up holding it.
+func (gs GameState) DrawCardTo(cn CardName, player *Player) error {
+	if err := gs.CheckPlaying(); err != nil {
+		return err
+	}
 	curTurn, err := gs.GameTurns.CurrentTurn()
 	if err != nil {
 		return err
@@ -140,91 +281,303 @@ func (gs GameState) DrawCard(cn CardName) error {
 	if len(curTurn.DrawnCards) == CityCardsPerTurn {
 		return fmt.Errorf("%v has already drawn %v cards this turn.", curTurn.Player.HumanName, CityCardsPerTurn)
 	}
+	if err := gs.checkDeckLeft(); err != nil {
+		return err
+	}
 	card, err := gs.CityDeck.DrawCard(cn)
 	if err != nil {
 		return err
 	}
 	curTurn.DrawnCards = append(curTurn.DrawnCards, card)
-	curTurn.Player.Cards = append(curTurn.Player.Cards, card)
+	player.Cards = append(player.Cards, card)
+	gs.markDeckRanOut(curTurn)
+	gs.changed("%v drew %v", player.HumanName, cn)
+	return nil
+}
+
+// CheckHandLimits returns an error naming a player holding more than the
+// hand limit, who must discard or play events before play goes on.
+func (gs GameState) CheckHandLimits() error {
+	for _, player := range gs.GameTurns.PlayerOrder {
+		if size := player.HandSize(); size > HandLimit {
+			return fmt.Errorf("%v holds %v cards, over the hand limit of %v: discard or play events first", player.HumanName, size, HandLimit)
+		}
+	}
+	return nil
+}
+
+// currentTurn returns the turn in progress, or nil when turns are not
+// being tracked.
+func (gs GameState) currentTurn() *Turn {
+	if gs.GameTurns == nil {
+		return nil
+	}
+	turn, err := gs.GameTurns.CurrentTurn()
+	if err != nil {
+		return nil
+	}
+	return turn
+}
+
+// DrawUnknown records a card drawn by the player that nobody wrote down. It
+// counts as one of the current turn's draws like DrawCardTo, kept on the
+// turn as a blank card.
+func (gs GameState) DrawUnknown(player *Player) error {
+	if err := gs.CheckPlaying(); err != nil {
+		return err
+	}
+	curTurn, err := gs.GameTurns.CurrentTurn()
+	if err != nil {
+		return err
+	}
+	if len(curTurn.DrawnCards) == CityCardsPerTurn {
+		return fmt.Errorf("%v has already drawn %v cards this turn.", curTurn.Player.HumanName, CityCardsPerTurn)
+	}
+	if err := gs.checkDeckLeft(); err != nil {
+		return err
+	}
+	err = gs.CityDeck.DrawUnknown()
+	if err != nil {
+		return err
+	}
+	curTurn.DrawnCards = append(curTurn.DrawnCards, &CityCard{})
+	player.UnknownCards++
+	gs.markDeckRanOut(curTurn)
+	gs.changed("%v drew an unknown card", player.HumanName)
+	return nil
+}
+
+// RevealCard identifies one of the player's unknown cards.
+func (gs GameState) RevealCard(player *Player, cn CardName) error {
+	if player.UnknownCards == 0 {
+		return fmt.Errorf("%v has no unknown cards", player.HumanName)
+	}
+	card, err := gs.CityDeck.Reveal(cn)
+	if err != nil {
+		return err
+	}
+	player.UnknownCards--
+	player.Cards = append(player.Cards, card)
+	gs.changed("%v's unknown card was %v", player.HumanName, cn)
 	return nil
 }
 
 func (gs GameState) NextTurn() (*Turn, error) {
-	return gs.GameTurns.NextTurn()
+	if err := gs.CheckPlaying(); err != nil {
+		return nil, err
+	}
+	turn, err := gs.GameTurns.NextTurn()
+	if err != nil {
+		return nil, err
+	}
+	gs.changed("%v's turn started", turn.Player.HumanName)
+	return turn, nil
 }
 
 func (gs GameState) ExchangeCard(from, to *Player, name CardName) error {
-	var senderNewCards []*CityCard
-	var toGive *CityCard
-	for _, card := range from.Cards {
-		if card.Name() == name {
-			toGive = card
-		} else {
-			senderNewCards = append(senderNewCards, card)
-		}
-	}
-	if toGive == nil {
+	toGive, err := from.Discard(name)
+	if err != nil {
 		return fmt.Errorf("%v does not seem to have the card %v", from.HumanName, name)
 	}
-	from.Cards = senderNewCards
 	to.Cards = append(to.Cards, toGive)
+	gs.changed("%v gave %v to %v", from.HumanName, name, to.HumanName)
 	return nil
 }
 
-func (gs GameState) Infect(cn CityName) error {
-	err := gs.InfectionDeck.Draw(cn)
+// Discard moves a card from the player's hand to the player discard pile.
+func (gs GameState) Discard(player *Player, cn CardName) error {
+	return gs.takeFromHand(player, cn, gs.CityDeck.discard)
+}
+
+// RemoveCard takes a card in the player's hand out of the game entirely.
+func (gs GameState) RemoveCard(player *Player, cn CardName) error {
+	return gs.takeFromHand(player, cn, gs.CityDeck.remove)
+}
+
+// takeFromHand takes one copy of the card from the player's hand and puts
+// that copy where the deck function says, giving it back if the deck
+// refuses it.
+func (gs GameState) takeFromHand(player *Player, cn CardName, put func(CityCard) error) error {
+	hand := player.Cards
+	card, err := player.Discard(cn)
 	if err != nil {
 		return err
 	}
-	city, err := gs.Cities.GetCity(cn)
-	if err != nil {
+	if err := put(*card); err != nil {
+		player.Cards = hand
 		return err
 	}
-	if city.Quarantined {
-		if !gs.quarantineSpecialistPresent(cn) {
-			city.RemoveQuarantine()
-		}
-		return nil
-	}
-	// TODO: handle outbreaks
-	city.Infect()
+	gs.changed("%v let go of %v", player.HumanName, cn)
 	return nil
 }
 
-func (gs GameState) Epidemic(cn CityName) error {
-	err := gs.InfectionDeck.PullFromBottom(cn)
+// Infect draws the city's infection card and places its cube, returning the
+// outbreaks it set off, if any.
+func (gs *GameState) Infect(cn CityName) (OutbreakChain, error) {
+	if err := gs.CheckPlaying(); err != nil {
+		return nil, err
+	}
+	if gs.QuietNight() {
+		return nil, fmt.Errorf("One Quiet Night skips this turn's infection step, %v can't be infected", cn)
+	}
+	return gs.infect(cn)
+}
+
+// SkipInfectionStep marks the current turn's infection step as skipped by
+// One Quiet Night. It has to be played before the step starts.
+func (gs GameState) SkipInfectionStep() error {
+	turn, err := gs.skippableTurn()
 	if err != nil {
 		return err
 	}
-	err = gs.CityDeck.DrawEpidemic()
+	turn.QuietNight = true
+	gs.record(StepSkipped, "%v's infection step was skipped", turn.Player.HumanName)
+	return nil
+}
+
+func (gs GameState) skippableTurn() (*Turn, error) {
+	turn := gs.currentTurn()
+	if turn == nil {
+		return nil, fmt.Errorf("There is no turn to skip the infection step of")
+	}
+	if turn.QuietNight {
+		return nil, fmt.Errorf("This turn's infection step is already skipped")
+	}
+	if len(turn.Infected) > 0 {
+		return nil, fmt.Errorf("This turn's infection step has already started with %v", turn.Infected[0])
+	}
+	return turn, nil
+}
+
+// QuietNight is true when the current turn's infection step is skipped.
+func (gs GameState) QuietNight() bool {
+	turn := gs.currentTurn()
+	return turn != nil && turn.QuietNight
+}
+
+// InfectionsThisTurn is the number of cards the current turn's infection
+// step draws, none on a quiet night.
+func (gs GameState) InfectionsThisTurn() int {
+	if gs.QuietNight() {
+		return 0
+	}
+	return gs.InfectionRate
+}
+
+func (gs *GameState) infect(cn CityName) (OutbreakChain, error) {
+	err := gs.InfectionDeck.Draw(cn)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	gs.changed("%v was drawn from the infection deck", cn)
+	city, err := gs.Cities.GetCity(cn)
+	if err != nil {
+		return nil, err
 	}
-	city, _ := gs.Cities.GetCity(cn)
+	if curTurn := gs.currentTurn(); curTurn != nil {
+		curTurn.Infected = append(curTurn.Infected, cn)
+	}
+	chain, _ := gs.placeInfection(city, 1)
+	return chain, nil
+}
 
+// placeInfection places the cubes an infection card or an epidemic puts on
+// the city and resolves the outbreaks they set off. A quarantined city
+// loses its marker instead, unless the Quarantine Specialist is there to
+// keep it, and placeInfection says whether it did.
+func (gs *GameState) placeInfection(city *City, cubes int) (OutbreakChain, bool) {
 	if city.Quarantined {
-		if !gs.quarantineSpecialistPresent(cn) {
-			city.RemoveQuarantine()
+		if gs.quarantineSpecialistPresent(city.Name) {
+			return nil, false
 		}
-	} else {
-		// TODO: handle outbreak
-		city.Epidemic()
+		city.RemoveQuarantine()
+		return nil, true
 	}
-	gs.InfectionDeck.ShuffleDrawn()
-	return nil
+	if !gs.infectCity(city, cubes) {
+		return nil, false
+	}
+	return gs.resolveOutbreaks(city), false
+}
+
+// InfectionRatePosition is where the marker is on the infection rate track:
+// one space along for every epidemic drawn.
+func (gs GameState) InfectionRatePosition() int {
+	if gs.CityDeck == nil {
+		return 0
+	}
+	position := gs.CityDeck.EpidemicsDrawn()
+	if last := len(gs.rules().InfectionRateTrack()) - 1; position > last {
+		position = last
+	}
+	return position
+}
+
+// NextInfectionRate is the infection rate once the next epidemic moves the
+// marker along the track.
+func (gs GameState) NextInfectionRate() int {
+	track := gs.rules().InfectionRateTrack()
+	next := gs.InfectionRatePosition() + 1
+	if next >= len(track) {
+		next = len(track) - 1
+	}
+	if track[next] < gs.InfectionRate {
+		// the rate was set by hand past the marker
+		return gs.InfectionRate
+	}
+	return track[next]
+}
+
+// advanceInfectionRate moves the infection rate to the marker's space after
+// an epidemic. It never goes down, so a rate set by hand ahead of the
+// marker holds until the track catches up.
+func (gs *GameState) advanceInfectionRate() {
+	rate := gs.rules().InfectionRateTrack()[gs.InfectionRatePosition()]
+	if rate > gs.InfectionRate {
+		gs.record(RateIncreased, "infection rate went up from %v to %v", gs.InfectionRate, rate)
+		gs.InfectionRate = rate
+	}
+}
+
+// EpidemicsThisTurn counts the epidemics drawn in the current turn. Two
+// means a double epidemic: the second intensify only shuffles the second
+// epidemic's city onto the infection deck, so it is the next card drawn.
+func (gs GameState) EpidemicsThisTurn() int {
+	curTurn := gs.currentTurn()
+	if curTurn == nil {
+		return 0
+	}
+	epidemics := 0
+	for _, card := range curTurn.DrawnCards {
+		if card.IsEpidemic {
+			epidemics++
+		}
+	}
+	return epidemics
 }
 
 func (gs GameState) quarantineSpecialistPresent(cityName CityName) bool {
 	for _, player := range gs.GameTurns.PlayerOrder {
-		if player.Location == cityName &&
-			player.Character != nil && // TODO: actually support characters and remove null check
-			player.Character.Type == QuarantineSpecialist {
+		if player.Location == cityName && player.IsCharacter(QuarantineSpecialist) {
 			return true
 		}
 	}
 	return false
 }
 
+// QuarantineMarkersLeft is the number of quarantine markers that aren't on
+// the map.
+func (gs GameState) QuarantineMarkersLeft() int {
+	left := gs.rules().QuarantineMarkers() - gs.Cities.CountQuarantined()
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// Quarantine places a quarantine marker on the city. Only one marker fits
+// on a city, it can't be placed on a city that hasn't been explored yet, and
+// once every marker is on the map one has to be removed before another city
+// can be quarantined.
 func (gs GameState) Quarantine(cn CityName) error {
 	city, err := gs.Cities.GetCity(cn)
 	if err != nil {
@@ -233,7 +586,14 @@ func (gs GameState) Quarantine(cn CityName) error {
 	if city.Quarantined {
 		return fmt.Errorf("%v is already quarantined", cn)
 	}
+	if city.Unexplored {
+		return fmt.Errorf("%v has not been explored yet", cn)
+	}
+	if gs.QuarantineMarkersLeft() == 0 {
+		return fmt.Errorf("All %v quarantine markers are in use, remove one first", gs.rules().QuarantineMarkers())
+	}
 	city.Quarantine()
+	gs.changed("%v was quarantined", cn)
 	return nil
 }
 
@@ -243,22 +603,25 @@ func (gs GameState) RemoveQuarantine(cn CityName) error {
 		return err
 	}
 	if !city.Quarantined {
-		return fmt.Errorf("%v is not quarantined ", cn)
+		return fmt.Errorf("%v is not quarantined", cn)
 	}
 	city.RemoveQuarantine()
+	gs.changed("%v's quarantine was removed", cn)
 	return nil
 }
 
 // ProbabilityOfCity gives the aggregate probability of a city
-// becoming infected. Quarantines make the probabilty of infection
-// zero. This does not take into account the probability of infection
-// due to neighboring city outbreaks.
+// becoming infected. Quarantines, and roles such as the Quarantine
+// Specialist's that keep cubes off the city, make the probabilty of
+// infection zero. This does not take into account the probability of
+// infection due to neighboring city outbreaks.
 func (gs GameState) ProbabilityOfCity(cn CityName) float64 {
 	city, err := gs.Cities.GetCity(cn)
 	if err != nil {
 		return 0.0
 	}
-	if city.Quarantined {
+	// cards of an eradicated disease place no cubes
+	if city.Quarantined || gs.IsEradicated(city.Disease) || gs.protectedBy(city) != nil {
 		return 0.0
 	}
 	var cityDrawInfectRate float64
@@ -273,11 +636,11 @@ func (gs GameState) ProbabilityOfCity(cn CityName) float64 {
 	var pEpiDraw float64
 	if bottom.Contains(cn) {
 		pEpiDraw = 1.0 / float64(bottom.Size())
-	} else if gs.InfectionDeck.Drawn.Contains(cn) {
-		pEpiDraw = float64(gs.InfectionRate) / (1.0 + float64(len(gs.InfectionDeck.Drawn)))
+	} else if gs.InfectionDeck.Drawn.Contains(cn) && !gs.QuietNight() {
+		pEpiDraw = float64(gs.NextInfectionRate()) / (1.0 + float64(len(gs.InfectionDeck.Drawn)))
 	}
 
-	pNoEpiDraw := gs.InfectionDeck.ProbabilityOfDrawing(cn, gs.InfectionRate)
+	pNoEpiDraw := gs.InfectionDeck.ProbabilityOfDrawing(cn, gs.InfectionsThisTurn())
 	return cityDrawInfectRate + pEpi*pEpiDraw + (1.0-pEpi)*pNoEpiDraw
 }
 
@@ -293,7 +656,8 @@ func (gs GameState) CanOutbreak(cn CityName) bool {
 	if prob == 0.0 {
 		return false
 	}
-	return city.NumInfections == 3 || gs.InfectionDeck.BottomStriation().Contains(cn)
+	// Season 2 supply cubes are used up before any plague cube is placed
+	return (city.NumInfections == 3 && city.SupplyCubes == 0) || gs.InfectionDeck.BottomStriation().Contains(cn)
 }
 
 func (gs *GameState) GetCity(city CityName) (*City, error) {
@@ -315,6 +679,34 @@ func (gs *GameState) SortBySeverity(names []CityName) []CityName {
 	return b.names
 }
 
+// SortByProbability orders cities from most to least likely to be
+// infected, breaking ties by name.
+func (gs *GameState) SortByProbability(names []CityName) []CityName {
+	b := byProbability{names, gs}
+	sort.Sort(&b)
+	return b.names
+}
+
+type byProbability struct {
+	names []CityName
+	gs    *GameState
+}
+
+func (b byProbability) Len() int { return len(b.names) }
+
+func (b byProbability) Swap(i, j int) {
+	b.names[i], b.names[j] = b.names[j], b.names[i]
+}
+
+func (b byProbability) Less(i, j int) bool {
+	probI := b.gs.ProbabilityOfCity(b.names[i])
+	probJ := b.gs.ProbabilityOfCity(b.names[j])
+	if probI != probJ {
+		return probI > probJ
+	}
+	return strings.Compare(string(b.names[i]), string(b.names[j])) < 0
+}
+
 type bySeverity struct {
 	names []CityName
 	gs    *GameState
@@ -1,18 +1,22 @@
 package pandemic
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"math"
+	"reflect"
+	"strings"
 	"testing"
 )
 
 func getNumCards(count int, numEpis int) []CityCard {
 	cards := make([]CityCard, count)
 	for x := 0; x < count-numEpis; x++ {
-		cards[x] = CityCard{CityName(fmt.Sprintf("testCity%v", x)), false, ""}
+		cards[x] = CityCard{CityName: CityName(fmt.Sprintf("testCity%v", x))}
 	}
 	for x := count - numEpis; x < count; x++ {
-		cards[x] = CityCard{"", true, ""}
+		cards[x] = CityCard{IsEpidemic: true}
 	}
 	return cards
 }
@@ -336,6 +340,35 @@ func TestRunInfectTests(t *testing.T) {
 	}
 }
 
+func TestPilePosition(t *testing.T) {
+	// [2,1,1,1], [1,2,1,1], [1,1,2,1] and [1,1,1,2]
+	model := generateProbabilityModel(5, 4)
+
+	pos := model.PilePosition(0)
+	if pos.MinPile != 0 || pos.MaxPile != 0 || pos.NumPiles != 4 {
+		t.Fatalf("Expected to start in the first of 4 piles, got %+v", pos)
+	}
+	if pos.MinSize != 1 || pos.MaxSize != 2 {
+		t.Fatalf("Expected the first pile to have 1 or 2 cards, got %+v", pos)
+	}
+
+	pos = model.PilePosition(1)
+	if pos.MinPile != 0 || pos.MaxPile != 1 {
+		t.Fatalf("Expected the second card to be in the first or second pile, got %+v", pos)
+	}
+
+	// drawing a city first rules out every scenario where the first pile has 1 card
+	model.DrawCity(0)
+	pos = model.PilePosition(1)
+	if pos.MinPile != 0 || pos.MaxPile != 0 || pos.MinDepth != 1 || pos.MaxSize != 2 {
+		t.Fatalf("Expected to be 1 card deep into a 2 card first pile, got %+v", pos)
+	}
+
+	if pos = model.PilePosition(5); !pos.Exhausted() {
+		t.Fatalf("Expected the deck to be exhausted after 5 cards, got %+v", pos)
+	}
+}
+
 func TestSortByInfect(t *testing.T) {
 	cities := Cities([]*City{
 		{
@@ -358,3 +391,584 @@ func TestSortByInfect(t *testing.T) {
 		t.Fatalf("Incorrect order: %+v", sorted)
 	}
 }
+
+func TestSortByProbability(t *testing.T) {
+	cities, cityDeck, err := getTestCityDeck()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cityDeck.DrawEpidemic()
+	infectDeck := NewInfectionDeck(cities.CityNames())
+	infectDeck.Draw("a")
+	gs := GameState{
+		Cities:        &cities,
+		CityDeck:      &cityDeck,
+		InfectionDeck: infectDeck,
+		InfectionRate: 2,
+	}
+	sorted := gs.SortByProbability([]CityName{"a", "b", "g"})
+	// g is faded and can also be infected from the city deck, a was already drawn
+	if sorted[0] != "g" || sorted[1] != "b" || sorted[2] != "a" {
+		t.Fatalf("Incorrect order: %+v", sorted)
+	}
+}
+
+func TestCityDeckDiscard(t *testing.T) {
+	cities, deck, err := getTestCityDeck()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if available := deck.AvailableCardsWith(Blue.Type, &cities); available != 3 {
+		t.Fatalf("Expected 3 blue cards available, got %v", available)
+	}
+	if err := deck.Discard("a"); err == nil {
+		t.Fatal("Should not be able to discard a card still in the deck")
+	}
+	deck.DrawCard("a")
+	deck.DrawCard("b")
+	if loc := deck.Location("a"); loc != InHand {
+		t.Fatalf("Expected a to be in hand, was in %v", loc)
+	}
+	if err := deck.Discard("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := deck.Remove("b"); err != nil {
+		t.Fatal(err)
+	}
+	if loc := deck.Location("a"); loc != InDiscard {
+		t.Fatalf("Expected a to be discarded, was in %v", loc)
+	}
+	if loc := deck.Location("b"); loc != OutOfTheGame {
+		t.Fatalf("Expected b to be removed, was in %v", loc)
+	}
+	if available := deck.AvailableCardsWith(Blue.Type, &cities); available != 1 {
+		t.Fatalf("Expected only c to be available, got %v", available)
+	}
+	if remaining := deck.RemainingCardsWith(Blue.Type, &cities); remaining != 1 {
+		t.Fatalf("Discarding should not change the deck odds, expected 1 blue card remaining, got %v", remaining)
+	}
+
+	// a hand the deck disagrees with is left as it was
+	player := &Player{HumanName: "p1", Cards: []*CityCard{{CityName: "c"}}}
+	gs := GameState{Cities: &cities, CityDeck: &deck}
+	if err := gs.Discard(player, "c"); err == nil || !player.HasCard("c") {
+		t.Fatalf("Expected c to stay in hand when the deck can't discard it, got %v", err)
+	}
+	if err := gs.RemoveCard(player, "c"); err == nil || !player.HasCard("c") {
+		t.Fatalf("Expected c to stay in hand when the deck can't remove it, got %v", err)
+	}
+}
+
+func TestDrawCardTo(t *testing.T) {
+	cities, deck, err := getTestCityDeck()
+	if err != nil {
+		t.Fatal(err)
+	}
+	drawer := &Player{HumanName: "p1"}
+	holder := &Player{HumanName: "p2", UnknownCards: HandLimit}
+	gs := GameState{Cities: &cities, CityDeck: &deck, GameTurns: InitGameTurns(drawer, holder)}
+	if err := gs.CheckHandLimits(); err != nil {
+		t.Fatalf("Expected a full hand to be allowed, got %v", err)
+	}
+	if err := gs.DrawCardTo("a", holder); err != nil {
+		t.Fatal(err)
+	}
+	if !holder.HasCard("a") || drawer.HasCard("a") {
+		t.Fatal("Expected the card to go to p2's hand")
+	}
+	if turn, _ := gs.GameTurns.CurrentTurn(); len(turn.DrawnCards) != 1 {
+		t.Fatalf("Expected the card to count as one of p1's draws, got %v", turn.DrawnCards)
+	}
+	if err := gs.CheckHandLimits(); err == nil {
+		t.Fatal("Expected p2 to be over the hand limit")
+	}
+	if err := gs.Discard(holder, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.CheckHandLimits(); err != nil {
+		t.Fatalf("Expected discarding to bring p2 back to the limit, got %v", err)
+	}
+}
+
+func TestDoubleEpidemic(t *testing.T) {
+	cities := Cities{}
+	for _, name := range []CityName{"a", "b", "c", "d", "e", "f", "g"} {
+		cities = append(cities, &City{Name: name})
+	}
+	// piles of 5 and 4 in some order
+	cityDeck, err := cities.GenerateCityDeck(2, nil, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := GameState{
+		Cities:        &cities,
+		CityDeck:      &cityDeck,
+		InfectionDeck: NewInfectionDeck(cities.CityNames()),
+		InfectionRate: 2,
+		GameTurns:     InitGameTurns(&Player{HumanName: "p1"}, &Player{HumanName: "p2"}),
+		Log:           &EventLog{},
+	}
+	for i, card := range []CardName{"a", "b", "c", "d"} {
+		if i == 2 {
+			if _, err := gs.NextTurn(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := gs.DrawCard(card); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := gs.NextTurn(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gs.Infect("a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gs.Epidemic("g", false); err != nil {
+		t.Fatal(err)
+	}
+	// the first pile had 5 cards, so the second can start right away
+	if p := gs.CityDeck.EpidemicAnalysis().FirstCardProbability; math.Abs(p-0.25) > 0.0001 {
+		t.Fatalf("Expected a 1 in 4 chance of a second epidemic, got %v", p)
+	}
+	if _, err := gs.Epidemic("f", false); err != nil {
+		t.Fatal(err)
+	}
+	if gs.EpidemicsThisTurn() != 2 {
+		t.Fatalf("Expected a double epidemic, got %v epidemics", gs.EpidemicsThisTurn())
+	}
+	// only the second epidemic's city is shuffled onto the top
+	if p := gs.InfectionDeck.ProbabilityOfDrawing("f", 1); p != 1.0 {
+		t.Fatalf("Expected f to be infected next, got %v", p)
+	}
+	if p := gs.InfectionDeck.ProbabilityOfDrawing("a", 2); math.Abs(p-0.5) > 0.0001 {
+		t.Fatalf("Expected a to be in the striation after f, got %v", p)
+	}
+	// both epidemics move the marker, to the last space at rate 2
+	if gs.InfectionRatePosition() != 2 || gs.InfectionRate != 2 || gs.NextInfectionRate() != 3 {
+		t.Fatalf("Expected the third space on the track, got position %v rate %v", gs.InfectionRatePosition(), gs.InfectionRate)
+	}
+	gs.InfectionRate = 4
+	if gs.NextInfectionRate() != 4 {
+		t.Fatalf("A rate set by hand should not go down, got %v", gs.NextInfectionRate())
+	}
+}
+
+func TestEpidemicEmptiesBottomStriation(t *testing.T) {
+	deck := &InfectionDeck{Drawn: Set{}, Striations: []Set{Init(CityName("a")), Init(CityName("b"))}}
+	if err := deck.PullFromBottom("b"); err != nil {
+		t.Fatal(err)
+	}
+	deck.ShuffleDrawn()
+	// a is now the bottom card for a second epidemic
+	if err := deck.PullFromBottom("a"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOutbreakChain(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", Disease: Blue.Type, NumInfections: 3, Neighbors: []string{"b", "c"}},
+		{Name: "b", Disease: Blue.Type, NumInfections: 3, Neighbors: []string{"a", "d"}},
+		{Name: "c", Disease: Blue.Type, Quarantined: true, Neighbors: []string{"a"}},
+		{Name: "d", Disease: Yellow.Type, Neighbors: []string{"b"}},
+	})
+	gs := &GameState{
+		Cities:           &cities,
+		InfectionDeck:    NewInfectionDeck(cities.CityNames()),
+		GameTurns:        InitGameTurns(&Player{HumanName: "p1"}, &Player{HumanName: "p2"}),
+		ResolveOutbreaks: true,
+	}
+	chain, err := gs.Infect("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// b outbreaks in turn but can't set a off again
+	if len(chain) != 2 || chain[0].City != "a" || chain[1].City != "b" || gs.Outbreaks != 2 {
+		t.Fatalf("Expected a and then b to outbreak once each, got %+v and %v outbreaks", chain, gs.Outbreaks)
+	}
+	if len(chain[0].Quarantined) != 1 || cities[2].NumInfections != 0 || cities[2].Quarantined {
+		t.Fatalf("Expected c's quarantine to stop the cube and be removed, got %+v", cities[2])
+	}
+	if len(chain[1].OtherDisease) != 1 || cities[3].NumInfections != 0 || cities[3].Cubes(Blue.Type) != 1 {
+		t.Fatalf("Expected d to take a blue cube of its own, got %+v and %+v", chain[1], cities[3])
+	}
+}
+
+func TestOtherColorCubes(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", Disease: Blue.Type, NumInfections: 3, Neighbors: []string{"b"}},
+		{Name: "b", Disease: Yellow.Type, NumInfections: 1, OtherCubes: map[DiseaseType]int{Blue.Type: 3}, Neighbors: []string{"a", "c"}},
+		{Name: "c", Disease: Yellow.Type, Neighbors: []string{"b"}},
+	})
+	gs := &GameState{
+		Cities:           &cities,
+		DiseaseData:      []DiseaseData{Blue, Yellow},
+		InfectionDeck:    NewInfectionDeck(cities.CityNames()),
+		GameTurns:        InitGameTurns(&Player{HumanName: "p1"}, &Player{HumanName: "p2"}),
+		ResolveOutbreaks: true,
+		Log:              &EventLog{},
+	}
+	if left := gs.CubesLeft(Blue.Type); left != 18 {
+		t.Fatalf("Expected the blue cubes on b to come out of the supply, got %v left", left)
+	}
+	chain, err := gs.Infect("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// b already has 3 blue cubes, so it outbreaks in blue onto c
+	if len(chain) != 2 || chain[1].City != "b" || gs.Outbreaks != 2 {
+		t.Fatalf("Expected a and then b to outbreak, got %+v", chain)
+	}
+	if cities[1].NumInfections != 1 || cities[2].Cubes(Blue.Type) != 1 || cities[2].NumInfections != 0 {
+		t.Fatalf("Expected b's outbreak to spill a blue cube onto c, got %+v and %+v", cities[1], cities[2])
+	}
+	if left := gs.CubesLeft(Blue.Type); left != 17 {
+		t.Fatalf("Expected 7 blue cubes on the board, got %v left", left)
+	}
+
+	if _, _, err := gs.TreatDisease("b", Blue.Type, 2, nil); err != nil {
+		t.Fatal(err)
+	}
+	if cities[1].Cubes(Blue.Type) != 1 || cities[1].NumInfections != 1 || gs.CubesLeft(Blue.Type) != 19 {
+		t.Fatalf("Expected treating b's blue cubes to return them to the supply, got %+v", cities[1])
+	}
+	if _, _, err := gs.TreatDisease("c", Red.Type, 1, nil); err == nil {
+		t.Fatal("Expected treating a disease c has no cubes of to fail")
+	}
+}
+
+func TestQuarantineMarkerSupply(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", Disease: Blue.Type},
+		{Name: "b", Disease: Blue.Type},
+		{Name: "c", Disease: Blue.Type},
+		{Name: "d", Disease: Blue.Type},
+		{Name: "e", Disease: Blue.Type},
+		{Name: "f", Disease: Blue.Type, Unexplored: true},
+	})
+	gs := &GameState{Cities: &cities}
+	for _, name := range []CityName{"a", "b", "c", "d"} {
+		if err := gs.Quarantine(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if gs.QuarantineMarkersLeft() != 0 {
+		t.Fatalf("Expected every marker to be in use, %v left", gs.QuarantineMarkersLeft())
+	}
+	if err := gs.Quarantine("e"); err == nil || cities[4].Quarantined {
+		t.Fatal("Should not quarantine a fifth city")
+	}
+	if err := gs.Quarantine("a"); err == nil {
+		t.Fatal("Should not quarantine a city twice")
+	}
+	if err := gs.RemoveQuarantine("e"); err == nil {
+		t.Fatal("Should not remove a quarantine that isn't there")
+	}
+	if err := gs.RemoveQuarantine("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.Quarantine("f"); err == nil {
+		t.Fatal("Should not quarantine an unexplored city")
+	}
+	if err := gs.Quarantine("e"); err != nil {
+		t.Fatalf("Expected the removed marker to be placed again, got %v", err)
+	}
+}
+
+func TestNeighborSpillover(t *testing.T) {
+	cities, cityDeck, err := getTestCityDeck()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cities[0].NumInfections = 3
+	cities[0].Neighbors = []string{"b", "c"}
+	cities[1].Neighbors = []string{"a"}
+	cities[2].Neighbors = []string{"a", "b"}
+	gs := GameState{
+		Cities:        &cities,
+		CityDeck:      &cityDeck,
+		InfectionDeck: NewInfectionDeck(cities.CityNames()),
+		InfectionRate: 2,
+	}
+	outbreak := gs.ProbabilityOfOutbreak("a")
+	if outbreak == 0.0 || outbreak != gs.ProbabilityOfCity("a") {
+		t.Fatalf("Expected a to outbreak whenever it is drawn, got %v", outbreak)
+	}
+	if gs.ProbabilityOfOutbreak("b") != 0.0 {
+		t.Fatal("Expected an empty city not to outbreak")
+	}
+	if spillover := gs.NeighborSpillover("b"); spillover != outbreak {
+		t.Fatalf("Expected b's exposure to be a's chance of outbreaking, got %v", spillover)
+	}
+	if gs.NeighborSpillover("a") != 0.0 {
+		t.Fatal("Expected a's neighbors to be safe from outbreaking")
+	}
+	if err := gs.Quarantine("b"); err != nil {
+		t.Fatal(err)
+	}
+	if gs.NeighborSpillover("b") != 0.0 || gs.NeighborSpillover("c") != outbreak {
+		t.Fatal("Expected b's quarantine to keep out a's cubes, but not c's")
+	}
+}
+
+func TestLoadGameStrict(t *testing.T) {
+	cities := Cities([]*City{{Name: "a"}, {Name: "b"}, {Name: "c"}})
+	gs := &GameState{
+		Cities:        &cities,
+		CityDeck:      &CityDeck{All: []CityCard{{CityName: "a"}, {CityName: "b"}}},
+		InfectionDeck: NewInfectionDeck(cities.CityNames()),
+		InfectionRate: 2,
+		GameTurns:     InitGameTurns(&Player{HumanName: "p1"}, &Player{HumanName: "p2"}),
+	}
+	file, cleanup := tempFile(t, "game.json")
+	defer cleanup()
+	save := func(edit func(map[string]interface{})) {
+		data, err := json.Marshal(gs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fields := map[string]interface{}{}
+		json.Unmarshal(data, &fields)
+		edit(fields)
+		data, _ = json.Marshal(fields)
+		if err := ioutil.WriteFile(file, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	save(func(map[string]interface{}) {})
+	if _, err := LoadGameStrict(file); err != nil {
+		t.Fatalf("A clean save should load strictly: %v", err)
+	}
+	save(func(fields map[string]interface{}) { fields["infection_rte"] = 3 })
+	if _, err := LoadGame(file); err != nil {
+		t.Fatalf("Unknown fields should still load leniently: %v", err)
+	}
+	if _, err := LoadGameStrict(file); err == nil {
+		t.Fatal("Expected an unknown field to be refused")
+	}
+
+	gs.InfectionDeck.Drawn.Add(CityName("a"))
+	gs.Outbreaks = 9
+	save(func(map[string]interface{}) {})
+	_, err := LoadGameStrict(file)
+	if err == nil || !strings.Contains(err.Error(), "a is in both") || !strings.Contains(err.Error(), "9 outbreaks") {
+		t.Fatalf("Expected the card in two places and the outbreaks to be reported, got %v", err)
+	}
+}
+
+func TestQuarantineSpecialistRisk(t *testing.T) {
+	cities, cityDeck, err := getTestCityDeck()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cities[0].NumInfections = 3
+	cities[0].Neighbors = []string{"b"}
+	cities[1].Neighbors = []string{"a", "c"}
+	cities[2].Neighbors = []string{"b"}
+	specialist := &Player{HumanName: "qs", Character: &Character{Type: QuarantineSpecialist}}
+	gs := GameState{
+		Cities:        &cities,
+		CityDeck:      &cityDeck,
+		InfectionDeck: NewInfectionDeck(cities.CityNames()),
+		InfectionRate: 2,
+		GameTurns:     InitGameTurns(specialist),
+		Log:           &EventLog{},
+	}
+	if gs.ProbabilityOfCity("a") == 0.0 || gs.ProbabilityOfOutbreak("a") == 0.0 {
+		t.Fatal("Expected a to be at risk before the Quarantine Specialist arrives")
+	}
+	if err := gs.PlacePawn(specialist, "b"); err != nil {
+		t.Fatal(err)
+	}
+	for _, city := range []CityName{"a", "b", "c"} {
+		if p := gs.ProbabilityOfCity(city); p != 0.0 {
+			t.Fatalf("Expected the Quarantine Specialist to protect %v, got %v", city, p)
+		}
+		if gs.ProtectedBy(city) != specialist {
+			t.Fatalf("Expected %v to be protected by the Quarantine Specialist", city)
+		}
+	}
+	if gs.ProbabilityOfOutbreak("a") != 0.0 || gs.NeighborSpillover("b") != 0.0 {
+		t.Fatal("Expected no outbreak from a protected city")
+	}
+	if gs.ProbabilityOfCity("d") == 0.0 {
+		t.Fatal("Expected d, away from the Quarantine Specialist, to stay at risk")
+	}
+	if err := gs.PlacePawn(specialist, "nowhere"); err == nil {
+		t.Fatal("Expected a pawn not to be placed in an unknown city")
+	}
+}
+
+func TestEpidemicSteps(t *testing.T) {
+	cities := Cities{}
+	for _, name := range []CityName{"a", "b", "c", "d"} {
+		cities = append(cities, &City{Name: name, Disease: Yellow.Type})
+	}
+	cityDeck, err := cities.GenerateCityDeck(1, nil, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := &GameState{
+		Cities:        &cities,
+		CityDeck:      &cityDeck,
+		DiseaseData:   []DiseaseData{Yellow},
+		InfectionDeck: NewInfectionDeck(cities.CityNames()),
+		InfectionRate: 2,
+		GameTurns:     InitGameTurns(&Player{HumanName: "p1"}, &Player{HumanName: "p2"}),
+		Log:           &EventLog{},
+	}
+	if _, err := gs.Infect("a"); err != nil {
+		t.Fatal(err)
+	}
+	changes := 0
+	gs.Subscribe(func(Change) { changes++ })
+
+	// a is in the discard pile, not at the bottom of the deck
+	logged := len(gs.Log.Events)
+	if _, err := gs.Epidemic("a", false); err == nil {
+		t.Fatal("Should not pull a card that isn't at the bottom of the infection deck")
+	}
+	if gs.CityDeck.EpidemicsDrawn() != 0 || gs.EpidemicsThisTurn() != 0 || gs.InfectionDeck.Drawn.Size() != 1 {
+		t.Fatal("Expected the failed epidemic to be rolled back")
+	}
+	if len(gs.Log.Events) != logged || changes != 0 {
+		t.Fatal("Expected nothing to be logged or published for the failed epidemic")
+	}
+
+	result, err := gs.Epidemic("b", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := EpidemicResult{City: "b", RateBefore: 2, RateAfter: 2, Cubes: 3, Intensified: 2}
+	if !reflect.DeepEqual(*result, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, *result)
+	}
+	if gs.InfectionDeck.TopStriation().Size() != 2 || gs.InfectionDeck.Drawn.Size() != 0 {
+		t.Fatal("Expected a and b to be shuffled back on top of the deck")
+	}
+	if changes == 0 {
+		t.Fatal("Expected the epidemic to be published")
+	}
+}
+
+func TestDiscardOneCopy(t *testing.T) {
+	cities := Cities{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	cityDeck, err := cities.GenerateCityDeckWith(1, nil, Set{}, []CityName{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p1, p2 := &Player{HumanName: "p1"}, &Player{HumanName: "p2"}
+	gs := GameState{
+		Cities:        &cities,
+		CityDeck:      &cityDeck,
+		InfectionDeck: NewInfectionDeck(cities.CityNames()),
+		GameTurns:     InitGameTurns(p1, p2),
+		Log:           &EventLog{},
+	}
+	if err := gs.DrawCard("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.DrawCard("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.ExchangeCard(p1, p2, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if len(p1.Cards) != 1 || len(p2.Cards) != 1 {
+		t.Fatalf("Expected one copy of a each, got %v and %v cards", len(p1.Cards), len(p2.Cards))
+	}
+	kept := p1.Cards[0].ID
+	if err := gs.Discard(p2, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if !p1.HasCard("a") || p2.HasCard("a") {
+		t.Fatal("Expected only p2's copy of a to be discarded")
+	}
+	if len(gs.CityDeck.Discarded) != 1 || gs.CityDeck.Discarded[0].ID == kept {
+		t.Fatalf("Expected the discarded copy to be p2's, got %+v", gs.CityDeck.Discarded)
+	}
+	if err := gs.Discard(p1, "a"); err != nil {
+		t.Fatalf("Expected p1's copy of a to be discarded too: %v", err)
+	}
+}
+
+func TestForcedEpidemic(t *testing.T) {
+	cities := Cities{}
+	for _, name := range []CityName{"a", "b", "c", "d"} {
+		cities = append(cities, &City{Name: name, Disease: Yellow.Type})
+	}
+	cityDeck, err := cities.GenerateCityDeck(2, nil, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := &GameState{
+		Cities:        &cities,
+		CityDeck:      &cityDeck,
+		DiseaseData:   []DiseaseData{Yellow},
+		InfectionDeck: NewInfectionDeck(cities.CityNames()),
+		InfectionRate: 2,
+		GameTurns:     InitGameTurns(&Player{HumanName: "p1"}, &Player{HumanName: "p2"}),
+		Log:           &EventLog{},
+	}
+	if _, err := gs.Epidemic("a", false); err != nil {
+		t.Fatal(err)
+	}
+	// the first pile has at least two cards and already had its epidemic
+	if _, err := gs.Epidemic("b", false); err == nil {
+		t.Fatal("Expected the impossible epidemic to be refused")
+	} else if _, ok := err.(*ImpossibleEpidemicError); !ok {
+		t.Fatalf("Expected an impossible epidemic, got %v", err)
+	}
+	if _, err := gs.Epidemic("b", true); err != nil {
+		t.Fatalf("Expected the forced epidemic to be entered: %v", err)
+	}
+	if gs.CityDeck.EpidemicsDrawn() != 2 {
+		t.Fatalf("Expected 2 epidemics drawn, got %v", gs.CityDeck.EpidemicsDrawn())
+	}
+	if len(gs.CityDeck.ProbabilityModel.Scenarios) == 0 {
+		t.Fatal("Expected the forced epidemic to keep the pile layouts")
+	}
+}
+
+func TestNewGameEpidemics(t *testing.T) {
+	settings := NewGameSettings{
+		Cities:       Cities{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}, {Name: "f"}, {Name: "g"}, {Name: "h"}},
+		Players:      []*Player{{HumanName: "p1"}, {HumanName: "p2"}},
+		FundedEvents: []*FundedEvent{{Name: AirliftEvent}, {Name: "one_quiet_night"}, {Name: "forecast"}},
+	}
+	gs, err := NewGameFrom(settings, "jan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gs.CityDeck.NumEpidemics() != EpidemicsPerGame || gs.CityDeck.NumFundedEvents() != 3 {
+		t.Errorf("Expected the ruleset's %v epidemics and all 3 funded events, got %v and %v", EpidemicsPerGame, gs.CityDeck.NumEpidemics(), gs.CityDeck.NumFundedEvents())
+	}
+
+	settings.Epidemics = 6
+	settings.FundedEventCount = 2
+	gs, err = NewGameFrom(settings, "jan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gs.CityDeck.NumEpidemics() != 6 || gs.CityDeck.NumFundedEvents() != 2 {
+		t.Errorf("Expected 6 epidemics and 2 funded events, got %v and %v", gs.CityDeck.NumEpidemics(), gs.CityDeck.NumFundedEvents())
+	}
+	if len(gs.CityDeck.All) != 16 {
+		t.Errorf("Expected 8 cities, 6 epidemics and 2 events in the deck, got %v cards", len(gs.CityDeck.All))
+	}
+	if gs.Metadata.Difficulty != "6 epidemics, 2 funded events" {
+		t.Errorf("Unexpected difficulty %q", gs.Metadata.Difficulty)
+	}
+
+	settings.Epidemics = MaxEpidemics + 1
+	if _, err := NewGameFrom(settings, "jan"); err == nil {
+		t.Errorf("Expected %v epidemics to be refused", settings.Epidemics)
+	}
+	settings.Epidemics = 0
+	settings.FundedEventCount = 4
+	if _, err := NewGameFrom(settings, "jan"); err == nil {
+		t.Errorf("Expected more funded events than listed to be refused")
+	}
+}
@@ -0,0 +1,150 @@
+package pandemic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The Iberia modules: purification tokens placed on the regions between
+// cities, and railroads built between neighboring cities.
+
+const PurificationModuleName = "purification"
+const RailroadModuleName = "railroad"
+
+const Train = MoveKind("train")
+
+// A Region is an area of the board bordered by the listed cities.
+// Purification tokens in a region protect every city around it.
+type Region struct {
+	Name               string     `json:"name"`
+	Cities             []CityName `json:"cities"`
+	PurificationTokens int        `json:"purification_tokens"`
+}
+
+func (r *Region) Borders(cn CityName) bool {
+	for _, city := range r.Cities {
+		if city == cn {
+			return true
+		}
+	}
+	return false
+}
+
+type Railroad struct {
+	From CityName `json:"from"`
+	To   CityName `json:"to"`
+}
+
+type PurificationModule struct{}
+
+func (PurificationModule) Name() string { return PurificationModuleName }
+
+// ReduceInfection removes a purification token from a bordering region
+// instead of placing each cube, as long as there are tokens left.
+func (PurificationModule) ReduceInfection(gs GameState, city *City, cubes int) int {
+	for _, region := range gs.Regions {
+		for cubes > 0 && region.PurificationTokens > 0 && region.Borders(city.Name) {
+			region.PurificationTokens--
+			cubes--
+		}
+	}
+	return cubes
+}
+
+func (PurificationModule) ExtraMoves(gs GameState, from CityName) []Move {
+	return nil
+}
+
+type RailroadModule struct{}
+
+func (RailroadModule) Name() string { return RailroadModuleName }
+
+func (RailroadModule) ReduceInfection(gs GameState, city *City, cubes int) int {
+	return cubes
+}
+
+// ExtraMoves lets a player ride any distance along connected railroads for
+// a single action.
+func (RailroadModule) ExtraMoves(gs GameState, from CityName) []Move {
+	reached := Set{}
+	reached.Add(from)
+	frontier := []CityName{from}
+	for len(frontier) > 0 {
+		next := []CityName{}
+		for _, city := range frontier {
+			for _, railroad := range gs.Railroads {
+				other := railroad.To
+				if railroad.To == city {
+					other = railroad.From
+				} else if railroad.From != city {
+					continue
+				}
+				if !reached.Contains(other) {
+					reached.Add(other)
+					next = append(next, other)
+				}
+			}
+		}
+		frontier = next
+	}
+	moves := []Move{}
+	for _, city := range reached.Members() {
+		if CityName(city) != from {
+			moves = append(moves, Move{Train, CityName(city), ""})
+		}
+	}
+	return moves
+}
+
+func (gs GameState) GetRegionByPrefix(prefix string) (*Region, error) {
+	var ret *Region
+	for _, region := range gs.Regions {
+		if strings.HasPrefix(strings.ToLower(region.Name), strings.ToLower(prefix)) {
+			if ret != nil {
+				return nil, fmt.Errorf("Multiple regions match %v", prefix)
+			}
+			ret = region
+		}
+	}
+	if ret == nil {
+		return nil, fmt.Errorf("No region matches %v", prefix)
+	}
+	return ret, nil
+}
+
+func (gs GameState) Purify(region *Region, tokens int) error {
+	if !gs.HasModule(PurificationModuleName) {
+		return fmt.Errorf("This game is not using purification tokens")
+	}
+	region.PurificationTokens += tokens
+	gs.changed("%v purification tokens placed in %v", tokens, region.Name)
+	return nil
+}
+
+// BuildRailroad lays track between two neighboring cities.
+func (gs *GameState) BuildRailroad(from, to CityName) error {
+	if !gs.HasModule(RailroadModuleName) {
+		return fmt.Errorf("This game is not using railroads")
+	}
+	city, err := gs.GetCity(from)
+	if err != nil {
+		return err
+	}
+	neighbors := false
+	for _, neighbor := range city.Neighbors {
+		if CityName(neighbor) == to {
+			neighbors = true
+		}
+	}
+	if !neighbors {
+		return fmt.Errorf("Railroads can only be built between neighbors, %v does not border %v", from, to)
+	}
+	for _, railroad := range gs.Railroads {
+		if (railroad.From == from && railroad.To == to) || (railroad.From == to && railroad.To == from) {
+			return fmt.Errorf("There is already a railroad between %v and %v", from, to)
+		}
+	}
+	gs.Railroads = append(gs.Railroads, Railroad{from, to})
+	gs.changed("railroad built between %v and %v", from, to)
+	return nil
+}
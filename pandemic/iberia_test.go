@@ -0,0 +1,54 @@
+package pandemic
+
+import (
+	"testing"
+)
+
+func TestPurification(t *testing.T) {
+	cities := Cities([]*City{{Name: "a"}, {Name: "b"}})
+	gs := &GameState{
+		Cities:  &cities,
+		Modules: []string{PurificationModuleName},
+		Regions: []*Region{{Name: "north", Cities: []CityName{"a"}, PurificationTokens: 2}},
+	}
+	a, _ := cities.GetCity("a")
+	b, _ := cities.GetCity("b")
+	gs.infectCity(a, 3)
+	gs.infectCity(b, 1)
+	if a.NumInfections != 1 || gs.Regions[0].PurificationTokens != 0 {
+		t.Fatalf("Expected 2 tokens to prevent 2 of the 3 cubes on a, got %+v and %+v", a, gs.Regions[0])
+	}
+	if b.NumInfections != 1 {
+		t.Fatalf("b does not border the purified region and should have been infected, got %+v", b)
+	}
+}
+
+func TestRailroadRoute(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", Neighbors: []string{"b"}},
+		{Name: "b", Neighbors: []string{"a", "c"}},
+		{Name: "c", Neighbors: []string{"b", "d"}},
+		{Name: "d", Neighbors: []string{"c"}},
+	})
+	player := &Player{HumanName: "Anthony"}
+	gs := &GameState{
+		Cities:    &cities,
+		Modules:   []string{RailroadModuleName},
+		GameTurns: InitGameTurns(player),
+	}
+	if err := gs.BuildRailroad("a", "c"); err == nil {
+		t.Fatal("Should not be able to build a railroad between cities that aren't neighbors")
+	}
+	for _, railroad := range [][2]CityName{{"a", "b"}, {"b", "c"}, {"c", "d"}} {
+		if err := gs.BuildRailroad(railroad[0], railroad[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fastest, _, err := gs.PlanRoutes(player, "a", "d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fastest.Actions != 1 || fastest.Moves[0].Kind != Train {
+		t.Fatalf("Expected to ride the railroad to d in one action, got %v", fastest)
+	}
+}
@@ -0,0 +1,62 @@
+package pandemic
+
+import (
+	"fmt"
+)
+
+// InfectionCardChange is a city's infection card being added to or removed
+// from the infection deck from the given month on.
+type InfectionCardChange struct {
+	Month   string   `json:"month"`
+	City    CityName `json:"city"`
+	Removed bool     `json:"removed,omitempty"`
+}
+
+// CheckInfectionDeck compares a new game's infection deck with the changes
+// the campaign has recorded up to the game's month, describing every card
+// that should or shouldn't be there. The latest change to a city wins.
+func (c *Campaign) CheckInfectionDeck(gs *GameState) []string {
+	month, _ := monthIndex(gs.GameName)
+	latest := map[CityName]*InfectionCardChange{}
+	order := []CityName{}
+	for i := range Months {
+		for _, change := range c.InfectionCards {
+			if change.Month != Months[i] || i > month {
+				continue
+			}
+			if _, ok := latest[change.City]; !ok {
+				order = append(order, change.City)
+			}
+			latest[change.City] = change
+		}
+	}
+	problems := []string{}
+	for _, change := range c.InfectionCards {
+		if index, _ := monthIndex(change.Month); index == len(Months) {
+			problems = append(problems, fmt.Sprintf("%q is not a month, use jan to dec for the change to %v", change.Month, change.City))
+		}
+	}
+	for _, city := range order {
+		change := latest[city]
+		inDeck := gs.InfectionDeck.contains(city)
+		switch {
+		case change.Removed && inDeck:
+			problems = append(problems, fmt.Sprintf("%v's infection card was removed in %v but is in the deck", city, change.Month))
+		case !change.Removed && !inDeck:
+			problems = append(problems, fmt.Sprintf("%v's infection card was added in %v but is missing from the deck", city, change.Month))
+		}
+	}
+	return problems
+}
+
+func (d *InfectionDeck) contains(city CityName) bool {
+	if d.Drawn.Contains(city) {
+		return true
+	}
+	for _, striation := range d.Striations {
+		if striation.Contains(city) {
+			return true
+		}
+	}
+	return false
+}
@@ -7,6 +7,19 @@ import (
 type InfectionDeck struct {
 	Drawn      Set
 	Striations []Set // all Striations still present on the infection deck. the 0th is the top
+	Removed    Set   `json:",omitempty"` // cards taken out of the game, e.g. by Resilient Population
+	// DiscardOrder is the discard pile in the order the cards were played
+	// onto it, so the last card is on top.
+	DiscardOrder []CityName `json:"discard_order,omitempty"`
+	// ShuffledAt is where each card sat in the discard pile, counted from
+	// the top, when it was last shuffled back onto the deck.
+	ShuffledAt map[CityName]int `json:"shuffled_at,omitempty"`
+	// Weights make some cards of a weakly shuffled striation likelier to
+	// be drawn than others. Cards without a weight count as 1.
+	Weights map[CityName]float64 `json:"weights,omitempty"`
+	// Forecasted holds the cards put back by a Forecast that haven't been
+	// drawn since.
+	Forecasted Set `json:"forecasted,omitempty"`
 }
 
 type InfectionCard struct {
@@ -31,15 +44,25 @@ func (d *InfectionDeck) assertStriationCount() {
 }
 
 func (d *InfectionDeck) Draw(cityName CityName) error {
-	d.assertStriationCount()
+	d.dropEmptyStriations()
+	if len(d.Striations) == 0 {
+		return fmt.Errorf("The infection deck is empty, %v can't be drawn from it", cityName)
+	}
 	if _, ok := d.Striations[0].Remove(cityName); !ok {
 		return fmt.Errorf("Card %v is not present in the active striation - how the fuck did you draw this card?", cityName)
 	}
 	d.Drawn.Add(cityName)
-	for d.Striations[0].Size() == 0 {
+	d.DiscardOrder = append(d.DiscardOrder, cityName)
+	d.dropEmptyStriations()
+	return nil
+}
+
+// dropEmptyStriations pops striations that have been drawn through, so the
+// top striation always holds the next card. An empty deck has none left.
+func (d *InfectionDeck) dropEmptyStriations() {
+	for len(d.Striations) > 0 && d.Striations[0].Size() == 0 {
 		d.Striations = d.Striations[1:]
 	}
-	return nil
 }
 
 func (d *InfectionDeck) CitiesInStriation(strIndx int) []CityName {
@@ -68,14 +91,96 @@ func (d *InfectionDeck) PullFromBottom(card CityName) error {
 		return fmt.Errorf("Card %v should not be present in the bottom striation", card)
 	}
 	d.Drawn.Add(card)
+	d.DiscardOrder = append(d.DiscardOrder, card)
+	// a striation emptied from the bottom, e.g. by the second epidemic of a
+	// double epidemic, is gone from the deck
+	if bottomStriation.Size() == 0 {
+		d.Striations = d.Striations[:len(d.Striations)-1]
+	}
 	return nil
 }
 
 // We just prepend the currently drawn pile onto the front
 // of our deck Striations. Then we reset drawn.
 func (d *InfectionDeck) ShuffleDrawn() {
+	if d.ShuffledAt == nil {
+		d.ShuffledAt = map[CityName]int{}
+	}
+	for pos, city := range d.DiscardPile() {
+		d.ShuffledAt[city] = pos
+		delete(d.Weights, city)
+		d.Forecasted.Remove(city)
+	}
 	d.Striations = append([]Set{d.Drawn}, d.Striations...)
 	d.Drawn = Set{}
+	d.DiscardOrder = nil
+}
+
+// intensified is the order the infection deck is drawn in after an epidemic
+// in the bottom city: the discard pile and the epidemic city shuffled back on
+// top of the rest.
+func (d *InfectionDeck) intensified(bottom CityName) []Set {
+	top := Set{}
+	for _, drawn := range d.Drawn.Members() {
+		top.Add(CityName(drawn))
+	}
+	top.Add(bottom)
+	striations := []Set{top}
+	for _, striation := range d.Striations {
+		rest := Set{}
+		for _, other := range striation.Members() {
+			if CityName(other) != bottom {
+				rest.Add(CityName(other))
+			}
+		}
+		if rest.Size() > 0 {
+			striations = append(striations, rest)
+		}
+	}
+	return striations
+}
+
+// RemoveFromGame takes a card out of the discard pile and out of the game,
+// as Resilient Population does. It is never shuffled back onto the deck.
+func (d *InfectionDeck) RemoveFromGame(city CityName) error {
+	if _, ok := d.Drawn.Remove(city); !ok {
+		return fmt.Errorf("%v is not in the infection discard pile", city)
+	}
+	order := []CityName{}
+	for _, discarded := range d.DiscardOrder {
+		if discarded != city {
+			order = append(order, discarded)
+		}
+	}
+	d.DiscardOrder = order
+	delete(d.ShuffledAt, city)
+	delete(d.Weights, city)
+	d.Forecasted.Remove(city)
+	if d.Removed == nil {
+		d.Removed = Set{}
+	}
+	d.Removed.Add(city)
+	return nil
+}
+
+// DiscardPile lists the discard pile from the top, most recent card first.
+// Cards discarded before the order was tracked come last, by name.
+func (d *InfectionDeck) DiscardPile() []CityName {
+	pile := []CityName{}
+	seen := Set{}
+	for i := len(d.DiscardOrder) - 1; i >= 0; i-- {
+		city := d.DiscardOrder[i]
+		if d.Drawn.Contains(city) && !seen.Contains(city) {
+			pile = append(pile, city)
+			seen.Add(city)
+		}
+	}
+	for _, city := range d.CitiesInDrawn() {
+		if !seen.Contains(city) {
+			pile = append(pile, city)
+		}
+	}
+	return pile
 }
 
 func (d *InfectionDeck) CurrentStriationCount() int {
@@ -92,6 +197,80 @@ func (d *InfectionDeck) TopStriation() Set {
 	return d.Striations[0]
 }
 
+// Size is the number of cards left in the infection deck.
+func (d *InfectionDeck) Size() int {
+	var size int
+	for _, striation := range d.Striations {
+		size += striation.Size()
+	}
+	return size
+}
+
+// KnownPosition returns how many cards sit above the given city in the
+// infection deck, if that is known exactly. A card's position is known when
+// it is the only card in its striation.
+func (d *InfectionDeck) KnownPosition(city CityName) (int, bool) {
+	for _, card := range d.Cards() {
+		if card.City == city {
+			return card.Position, card.Location == InStriation && card.Certain()
+		}
+	}
+	return 0, false
+}
+
+type InfectionCardLocation int
+
+const (
+	InStriation = InfectionCardLocation(iota)
+	InInfectionDiscard
+	RemovedFromInfectionDeck
+)
+
+// InfectionCardState is everything the tracker knows about where a single
+// infection card is.
+type InfectionCardState struct {
+	City     CityName
+	Location InfectionCardLocation
+	// Striation is the index of the card's striation, 0 being the top.
+	Striation int
+	// Position is how many cards are certainly above this one: the card is
+	// somewhere between Position and Position+Candidates-1 from the top.
+	Position   int
+	Candidates int
+}
+
+// Certain is true when the card's exact place is known, which is always
+// the case once it is out of the draw pile.
+func (c InfectionCardState) Certain() bool {
+	return c.Location != InStriation || c.Candidates == 1
+}
+
+// Cards lists every infection card from the top of the deck down, then the
+// discard pile, then the cards removed from the game.
+func (d *InfectionDeck) Cards() []InfectionCardState {
+	cards := []InfectionCardState{}
+	var above int
+	for i, striation := range d.Striations {
+		for _, city := range striation.Members() {
+			cards = append(cards, InfectionCardState{
+				City:       CityName(city),
+				Location:   InStriation,
+				Striation:  i,
+				Position:   above,
+				Candidates: striation.Size(),
+			})
+		}
+		above += striation.Size()
+	}
+	for _, city := range d.Drawn.Members() {
+		cards = append(cards, InfectionCardState{City: CityName(city), Location: InInfectionDiscard, Striation: -1, Candidates: 1})
+	}
+	for _, city := range d.Removed.Members() {
+		cards = append(cards, InfectionCardState{City: CityName(city), Location: RemovedFromInfectionDeck, Striation: -1, Candidates: 1})
+	}
+	return cards
+}
+
 func (d *InfectionDeck) DrawnCount() int {
 	return d.Drawn.Size()
 }
@@ -113,6 +292,9 @@ func (d *InfectionDeck) ProbabilityOfDrawing(city CityName, infectionRate int) f
 	// P(C) = 1 - (9/10)*(8/9)*(7/8)*(6/7) = 1 - 6/10 = 40%
 	probability := 1.0
 	curStriationSize := dCopy.Striations[0].Size()
+	// draws that land in the city's striation, for weakly shuffled ones
+	var weighted []CityName
+	weightedDraws := 0
 	for draw := 0; draw < infectionRate; draw++ {
 		// if we've run out of cards in this striation, pop and
 		// start using the next striation down.
@@ -128,12 +310,19 @@ func (d *InfectionDeck) ProbabilityOfDrawing(city CityName, infectionRate int) f
 		// target card is 100%.
 		if dCopy.Striations[0].Contains(city) {
 			probability *= float64(curStriationSize-1) / float64(curStriationSize)
+			if weighted == nil && dCopy.WeaklyShuffled(0) {
+				weighted = dCopy.CitiesInStriation(0)
+			}
+			weightedDraws++
 		}
 		// Reduce the count of cards we know will remain in this
 		// striation after drawing a card
 		curStriationSize = curStriationSize - 1
 	}
 
+	if weighted != nil {
+		return d.weightedDrawProbability(weighted, city, weightedDraws)
+	}
 	return 1 - probability
 }
 
@@ -52,3 +52,214 @@ func TestProbabilityOfStriations(t *testing.T) {
 	checkProbability(t, deck, "Washington", 1, 0.0)
 	checkProbability(t, deck, "Washington", 2, 0.25)
 }
+
+func TestKnownPosition(t *testing.T) {
+	deck := testInfectionDeck()
+	if _, known := deck.KnownPosition("Miami"); known {
+		t.Fatal("Should not know the position of any card in a fresh deck")
+	}
+	deck.Draw("Miami")
+	deck.ShuffleDrawn()
+	if pos, known := deck.KnownPosition("Miami"); !known || pos != 0 {
+		t.Fatalf("Expected Miami to be known to be on top, got %v %v", pos, known)
+	}
+	if _, known := deck.KnownPosition("Montreal"); known {
+		t.Fatal("Should not know where Montreal is")
+	}
+	deck.PullFromBottom("Montreal")
+	deck.PullFromBottom("NewYork")
+	deck.PullFromBottom("Washington")
+	if pos, known := deck.KnownPosition("SanFrancisco"); !known || pos != deck.Size()-1 {
+		t.Fatalf("Expected SanFrancisco to be known to be on the bottom, got %v %v", pos, known)
+	}
+}
+
+func TestInfectionDeckCards(t *testing.T) {
+	deck := testInfectionDeck()
+	deck.Draw("SanFrancisco")
+	deck.Draw("NewYork")
+	deck.ShuffleDrawn()
+	deck.Draw("NewYork")
+
+	expected := map[CityName]InfectionCardState{
+		"SanFrancisco": {"SanFrancisco", InStriation, 0, 0, 1},
+		"Miami":        {"Miami", InStriation, 1, 1, 3},
+		"NewYork":      {"NewYork", InInfectionDiscard, -1, 0, 1},
+	}
+	cards := deck.Cards()
+	if len(cards) != 5 {
+		t.Fatalf("Expected all 5 cards, got %+v", cards)
+	}
+	for _, card := range cards {
+		if want, ok := expected[card.City]; ok && card != want {
+			t.Fatalf("Expected %+v, got %+v", want, card)
+		}
+	}
+	if !cards[0].Certain() || cards[1].Certain() {
+		t.Fatalf("Only San Francisco's position should be certain: %+v", cards)
+	}
+}
+
+func TestDiscardPile(t *testing.T) {
+	deck := testInfectionDeck()
+	deck.Draw("SanFrancisco")
+	deck.Draw("NewYork")
+	deck.ShuffleDrawn()
+	deck.Draw("NewYork")
+	deck.Draw("SanFrancisco")
+	deck.PullFromBottom("Miami")
+
+	pile := deck.DiscardPile()
+	expected := []CityName{"Miami", "SanFrancisco", "NewYork"}
+	if len(pile) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, pile)
+	}
+	for i := range expected {
+		if pile[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, pile)
+		}
+	}
+
+	// saves from before the order was tracked still list every card
+	deck.DiscardOrder = deck.DiscardOrder[2:]
+	if pile := deck.DiscardPile(); len(pile) != 3 || pile[0] != "Miami" || pile[2] != "SanFrancisco" {
+		t.Fatalf("Expected untracked cards at the bottom by name, got %v", pile)
+	}
+}
+
+func TestRemoveFromGame(t *testing.T) {
+	deck := testInfectionDeck()
+	deck.Draw("SanFrancisco")
+	deck.Draw("NewYork")
+	if err := deck.RemoveFromGame("Miami"); err == nil {
+		t.Fatal("Should only remove cards from the discard pile")
+	}
+	if err := deck.RemoveFromGame("SanFrancisco"); err != nil {
+		t.Fatal(err)
+	}
+	if pile := deck.DiscardPile(); len(pile) != 1 || pile[0] != "NewYork" {
+		t.Fatalf("Expected only NewYork left in the discard pile, got %v", pile)
+	}
+	deck.ShuffleDrawn()
+	if deck.Size() != 4 || deck.ProbabilityOfDrawing("SanFrancisco", 4) != 0.0 {
+		t.Fatalf("Expected SanFrancisco to stay out of the deck, got %v cards", deck.Size())
+	}
+	if cards := deck.Cards(); cards[len(cards)-1].Location != RemovedFromInfectionDeck {
+		t.Fatalf("Expected the removed card to be listed last, got %+v", cards)
+	}
+}
+
+func TestWeakShuffle(t *testing.T) {
+	deck := testInfectionDeck()
+	deck.Draw("SanFrancisco")
+	deck.Draw("NewYork")
+	deck.Draw("Montreal")
+	deck.ShuffleDrawn()
+
+	if p := deck.ProbabilityOfDrawing("SanFrancisco", 1); math.Abs(p-1.0/3) > 0.0001 {
+		t.Fatalf("A fair shuffle should give 1/3, got %v", p)
+	}
+	if err := deck.MarkWeaklyShuffled(0); err != nil {
+		t.Fatal(err)
+	}
+	if !deck.WeaklyShuffled(0) || deck.WeaklyShuffled(1) {
+		t.Fatal("Only the top striation should be weakly shuffled")
+	}
+	// the oldest discard weighs 2, the newest 1 and the one between 1.5
+	if p := deck.ProbabilityOfDrawing("SanFrancisco", 1); math.Abs(p-2/4.5) > 0.0001 {
+		t.Fatalf("Expected the oldest discard to be likeliest, got %v", p)
+	}
+	if p := deck.ProbabilityOfDrawing("Montreal", 3); math.Abs(p-1) > 0.0001 {
+		t.Fatalf("Drawing the whole striation must draw Montreal, got %v", p)
+	}
+	if err := deck.MarkWeaklyShuffled(1); err == nil {
+		t.Fatal("The discard order of the original deck is not known")
+	}
+
+	// cutting a third off the top sends San Francisco to the bottom
+	if err := deck.MarkCut(0, 1.0/3); err != nil {
+		t.Fatal(err)
+	}
+	if p := deck.ProbabilityOfDrawing("NewYork", 1); math.Abs(p-2/4.5) > 0.0001 {
+		t.Fatalf("Expected the card under the cut to be likeliest, got %v", p)
+	}
+	if p := deck.ProbabilityOfDrawing("SanFrancisco", 1); math.Abs(p-1/4.5) > 0.0001 {
+		t.Fatalf("Expected the cut card to be least likely, got %v", p)
+	}
+	if err := deck.MarkCut(0, 1.0); err == nil {
+		t.Fatal("Should not be able to cut the whole striation")
+	}
+
+	deck.MarkFairlyShuffled(0)
+	if p := deck.ProbabilityOfDrawing("SanFrancisco", 1); math.Abs(p-1.0/3) > 0.0001 {
+		t.Fatalf("Expected a fair shuffle again, got %v", p)
+	}
+}
+
+func TestForecast(t *testing.T) {
+	deck := testInfectionDeck()
+	deck.Draw("SanFrancisco")
+	deck.Draw("NewYork")
+	deck.ShuffleDrawn()
+
+	if err := deck.Forecast([]CityName{"NewYork", "Miami"}, 2); err == nil {
+		t.Fatal("Miami cannot be seen before San Francisco")
+	}
+	if err := deck.Forecast([]CityName{"NewYork", "NewYork"}, 2); err == nil {
+		t.Fatal("A card cannot be seen twice")
+	}
+	// New York was put on top, the other two back in an unknown order
+	if err := deck.Forecast([]CityName{"NewYork", "SanFrancisco", "Miami"}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if pos, known := deck.KnownPosition("NewYork"); !known || pos != 0 {
+		t.Fatalf("Expected New York to be known to be on top, got %v %v", pos, known)
+	}
+	for _, card := range deck.Cards() {
+		if card.City == "Miami" && (card.Position != 1 || card.Candidates != 2 || !deck.FromForecast("Miami")) {
+			t.Fatalf("Expected Miami to be second or third, got %+v", card)
+		}
+		if card.City == "Montreal" && (card.Position != 3 || deck.FromForecast("Montreal")) {
+			t.Fatalf("Expected Montreal to be below the forecast, got %+v", card)
+		}
+	}
+	if p := deck.ProbabilityOfDrawing("NewYork", 1); p != 1.0 {
+		t.Fatalf("New York is on top, got %v", p)
+	}
+	preview := deck.ForecastPreview()
+	if len(preview) != 3 || !preview[0].Complete() || preview[0].Cities[0] != "NewYork" || preview[1].First != 1 || preview[2].Last != 4 {
+		t.Fatalf("Expected New York, then two pairs of cards, got %+v", preview)
+	}
+	deck.Draw("NewYork")
+	deck.ShuffleDrawn()
+	if deck.FromForecast("NewYork") {
+		t.Fatal("New York was shuffled back, its forecast place is gone")
+	}
+}
+
+func TestRecordInfection(t *testing.T) {
+	gs := GameState{InfectionDeck: testInfectionDeck()}
+	calibration := &Calibration{}
+	gs.RecordInfection(calibration, "Miami")
+	bins := calibration.Kinds[InfectionPrediction]
+	// every card had a 1 in 5 chance of being next
+	if calibration.Predictions() != 5 || bins[2].Predictions != 5 || bins[2].Hits != 1 {
+		t.Fatalf("Expected five 20%% predictions with one hit, got %+v", bins)
+	}
+	if math.Abs(bins[2].PredictedSum-1.0) > 0.0001 {
+		t.Fatalf("Expected the predictions to add up to 1, got %v", bins[2].PredictedSum)
+	}
+}
+
+func TestDrawLastInfectionCard(t *testing.T) {
+	deck := NewInfectionDeck([]CityName{"Miami"})
+	if err := deck.Draw("Miami"); err != nil {
+		t.Fatalf("Did not expect error drawing the last card: %v", err)
+	}
+	if deck.Size() != 0 {
+		t.Fatalf("Expected an empty deck, had %v cards", deck.Size())
+	}
+	if err := deck.Draw("Miami"); err == nil {
+		t.Fatal("Expected an error drawing from an empty deck")
+	}
+}
@@ -0,0 +1,139 @@
+package pandemic
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The infection deck can be written out and read back in a small subset of
+// YAML so that a deck can be fixed by hand or shared:
+//
+//	# top striation first
+//	striations:
+//	  - [lagos, khartoum]
+//	  - [paris, essen, milan]
+//	discard: [cairo, bogota] # top card first
+//	removed: []
+
+func (d *InfectionDeck) WriteYAML(w io.Writer) error {
+	lines := []string{"# top striation first", "striations:"}
+	for i := range d.Striations {
+		lines = append(lines, fmt.Sprintf("  - %v", yamlList(d.Striations[i].Members())))
+	}
+	discard := []string{}
+	for _, city := range d.DiscardPile() {
+		discard = append(discard, string(city))
+	}
+	lines = append(lines, fmt.Sprintf("discard: %v # top card first", yamlList(discard)))
+	lines = append(lines, fmt.Sprintf("removed: %v", yamlList(d.Removed.Members())))
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+func yamlList(items []string) string {
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+func parseYAMLList(value string) (Set, error) {
+	set, _, err := parseYAMLPile(value)
+	return set, err
+}
+
+// parseYAMLPile reads a list of cities, keeping the order they were
+// written in as well as the set of them.
+func parseYAMLPile(value string) (Set, []CityName, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		return nil, nil, fmt.Errorf("Expected a list like [a, b], got %q", value)
+	}
+	set := Set{}
+	order := []CityName{}
+	for _, item := range strings.Split(value[1:len(value)-1], ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if set.Contains(CityName(item)) {
+			return nil, nil, fmt.Errorf("%v is listed twice", item)
+		}
+		set.Add(CityName(item))
+		order = append(order, CityName(item))
+	}
+	return set, order, nil
+}
+
+func ReadInfectionDeckYAML(r io.Reader) (*InfectionDeck, error) {
+	deck := &InfectionDeck{Drawn: Set{}, Removed: Set{}}
+	scanner := bufio.NewScanner(r)
+	section := ""
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		var err error
+		switch {
+		case strings.HasPrefix(trimmed, "- ") && section == "striations":
+			var striation Set
+			striation, err = parseYAMLList(trimmed[2:])
+			if striation.Size() > 0 {
+				deck.Striations = append(deck.Striations, striation)
+			}
+		case strings.HasPrefix(trimmed, "striations:"):
+			section = "striations"
+		case strings.HasPrefix(trimmed, "discard:"):
+			section = "discard"
+			var pile []CityName
+			if deck.Drawn, pile, err = parseYAMLPile(strings.TrimPrefix(trimmed, "discard:")); err == nil {
+				// the file lists the pile from the top
+				for i := len(pile) - 1; i >= 0; i-- {
+					deck.DiscardOrder = append(deck.DiscardOrder, pile[i])
+				}
+			}
+		case strings.HasPrefix(trimmed, "removed:"):
+			section = "removed"
+			deck.Removed, err = parseYAMLList(strings.TrimPrefix(trimmed, "removed:"))
+		default:
+			err = fmt.Errorf("Unexpected %q", trimmed)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Line %v: %v", lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(deck.Striations) == 0 {
+		return nil, fmt.Errorf("The infection deck needs at least one striation")
+	}
+	return deck, nil
+}
+
+// ImportInfectionDeck replaces the infection deck after checking that every
+// city in play is in exactly one place.
+func (gs *GameState) ImportInfectionDeck(deck *InfectionDeck) error {
+	seen := Set{}
+	for _, card := range deck.Cards() {
+		if _, err := gs.GetCity(card.City); err != nil {
+			return err
+		}
+		if seen.Contains(card.City) {
+			return fmt.Errorf("%v is in the deck more than once", card.City)
+		}
+		seen.Add(card.City)
+	}
+	for _, cn := range gs.Cities.Explored().CityNames() {
+		if !seen.Contains(cn) {
+			return fmt.Errorf("%v is missing from the deck", cn)
+		}
+	}
+	gs.record(ManualOverride, "infection deck imported with %v striations", len(deck.Striations))
+	gs.InfectionDeck = deck
+	return nil
+}
@@ -0,0 +1,66 @@
+package pandemic
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestInfectionDeckYAMLRoundTrip(t *testing.T) {
+	deck := testInfectionDeck()
+	deck.Draw("SanFrancisco")
+	deck.Draw("NewYork")
+	deck.ShuffleDrawn()
+	deck.Draw("NewYork")
+
+	var buf bytes.Buffer
+	if err := deck.WriteYAML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadInfectionDeckYAML(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read.Cards()) != len(deck.Cards()) {
+		t.Fatalf("Expected %+v, got %+v", deck.Cards(), read.Cards())
+	}
+	for i, card := range deck.Cards() {
+		if read.Cards()[i] != card {
+			t.Fatalf("Expected %+v, got %+v", card, read.Cards()[i])
+		}
+	}
+	if pile := read.DiscardPile(); len(pile) != 1 || pile[0] != "NewYork" {
+		t.Fatalf("Expected the discard pile to survive the round trip, got %v", pile)
+	}
+}
+
+func TestImportInfectionDeck(t *testing.T) {
+	cities := Cities([]*City{{Name: "a"}, {Name: "b"}, {Name: "c"}})
+	gs := &GameState{Cities: &cities, InfectionDeck: NewInfectionDeck(cities.CityNames()), Log: &EventLog{}}
+
+	for _, bad := range []string{
+		"striations:\n  - [a, b]\ndiscard: []\n",
+		"striations:\n  - [a, b]\ndiscard: [c, a]\n",
+		"striations:\n  - [a, b, c, d]\n",
+		"discard: [a, b, c]\n",
+	} {
+		deck, err := ReadInfectionDeckYAML(strings.NewReader(bad))
+		if err == nil {
+			err = gs.ImportInfectionDeck(deck)
+		}
+		if err == nil {
+			t.Fatalf("Should not have imported %q", bad)
+		}
+	}
+
+	deck, err := ReadInfectionDeckYAML(strings.NewReader("# fixed by hand\nstriations:\n  - [c]\n  - [a] # bottom\ndiscard: [b]\nremoved: []\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.ImportInfectionDeck(deck); err != nil {
+		t.Fatal(err)
+	}
+	if pos, known := gs.InfectionDeck.KnownPosition("a"); !known || pos != 1 {
+		t.Fatalf("Expected a to be second from the top, got %v %v", pos, known)
+	}
+}
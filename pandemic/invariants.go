@@ -0,0 +1,66 @@
+package pandemic
+
+import (
+	"fmt"
+)
+
+// CheckInvariants lists what is impossible about the game, such as an
+// infection card that is both in the deck and in the discard pile, or a
+// city with more cubes than it can hold. A game played only through the
+// tracker never breaks them, so any problem means the save was edited or
+// written by a version that disagrees with this one.
+func (gs GameState) CheckInvariants() []string {
+	problems := []string{}
+	if gs.Cities == nil || gs.InfectionDeck == nil || gs.CityDeck == nil || gs.GameTurns == nil {
+		return append(problems, "the save is missing the cities, a deck or the turns")
+	}
+
+	known := map[CityName]bool{}
+	for _, city := range *gs.Cities {
+		if known[city.Name] {
+			problems = append(problems, fmt.Sprintf("%v is listed twice", city.Name))
+		}
+		known[city.Name] = true
+		if city.NumInfections < 0 || city.NumInfections > MaxInfections {
+			problems = append(problems, fmt.Sprintf("%v has %v cubes", city.Name, city.NumInfections))
+		}
+	}
+
+	// every infection card is in exactly one place
+	seen := map[string]string{}
+	place := func(where string, cards Set) {
+		for _, card := range cards.Members() {
+			if !known[CityName(card)] {
+				problems = append(problems, fmt.Sprintf("%v in the %v is not a city", card, where))
+			}
+			if other, ok := seen[card]; ok {
+				problems = append(problems, fmt.Sprintf("%v is in both the %v and the %v", card, other, where))
+			}
+			seen[card] = where
+		}
+	}
+	for i, striation := range gs.InfectionDeck.Striations {
+		place(fmt.Sprintf("infection deck striation %v", i), striation)
+	}
+	place("infection discard pile", gs.InfectionDeck.Drawn)
+	place("removed infection cards", gs.InfectionDeck.Removed)
+	for _, card := range gs.InfectionDeck.DiscardOrder {
+		if !gs.InfectionDeck.Drawn.Contains(card) {
+			problems = append(problems, fmt.Sprintf("%v is ordered in the discard pile but not in it", card))
+		}
+	}
+
+	if max := gs.rules().MaxOutbreaks(); gs.Outbreaks < 0 || gs.Outbreaks > max {
+		problems = append(problems, fmt.Sprintf("%v outbreaks, expected 0 to %v", gs.Outbreaks, max))
+	}
+	if gs.InfectionRate < 1 {
+		problems = append(problems, fmt.Sprintf("an infection rate of %v", gs.InfectionRate))
+	}
+	if drawn := len(gs.CityDeck.Drawn) + gs.CityDeck.UnknownDraws; drawn > len(gs.CityDeck.All) {
+		problems = append(problems, fmt.Sprintf("%v city cards drawn from a deck of %v", drawn, len(gs.CityDeck.All)))
+	}
+	if turns := len(gs.GameTurns.Turns); gs.GameTurns.CurTurn < 0 || (turns > 0 && gs.GameTurns.CurTurn >= turns) {
+		problems = append(problems, fmt.Sprintf("turn %v of %v", gs.GameTurns.CurTurn+1, turns))
+	}
+	return problems
+}
@@ -0,0 +1,156 @@
+package pandemic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// bestDraw is the mirror of worstDraw: the lowest score any legal draw of n
+// cards can reach.
+func bestDraw(striations []Set, n int, score func(CityName) int) int {
+	total, _ := worstDraw(striations, n, func(cn CityName) int { return -score(cn) })
+	return -total
+}
+
+// GuaranteedLoss checks whether every way the rest of this turn can go
+// loses the game, and if so says why. Draws are the best case for the team:
+// no epidemic unless one is certain, and the least harmful infection cards.
+func (gs GameState) GuaranteedLoss() (bool, string) {
+	needed := CityCardsPerTurn
+	if turn := gs.currentTurn(); turn != nil {
+		needed -= len(turn.DrawnCards)
+	}
+	if left := gs.CityDeck.CardsLeftInDeck(); needed > 0 && left < needed {
+		return true, fmt.Sprintf("the city deck has %v cards left but %v must be drawn", left, needed)
+	}
+
+	// the infection phase as the team would have it, played out on the board
+	// as it is, or after the least harmful epidemic if one is certain
+	outbreaks := -1
+	cubes := map[DiseaseType]int{}
+	consider := func(board GameState, striations []Set, rate int, epidemic infectionEffect) {
+		effect := board.infectionEffects()
+		scenario := epidemic.Outbreaks + bestDraw(striations, rate, func(cn CityName) int {
+			return effect(cn).Outbreaks
+		})
+		if outbreaks < 0 || scenario < outbreaks {
+			outbreaks = scenario
+		}
+		for _, supply := range gs.CubeSupply() {
+			dt := supply.Disease
+			placed := epidemic.Cubes[dt] + bestDraw(striations, rate, func(cn CityName) int {
+				return effect(cn).Cubes[dt]
+			})
+			if least, ok := cubes[dt]; !ok || placed < least {
+				cubes[dt] = placed
+			}
+		}
+	}
+
+	analysis := gs.CityDeck.EpidemicAnalysis()
+	if analysis.FirstCardProbability+analysis.SecondCardProbability >= 1 {
+		rate := gs.NextInfectionRate()
+		if gs.QuietNight() {
+			rate = 0
+		}
+		for _, member := range gs.InfectionDeck.BottomStriation().Members() {
+			bottom := CityName(member)
+			if _, err := gs.GetCity(bottom); err != nil {
+				continue
+			}
+			epidemic, board := gs.playOut(bottom, MaxInfections)
+			consider(*board, gs.InfectionDeck.intensified(bottom), rate, epidemic)
+		}
+	}
+	if outbreaks < 0 {
+		consider(gs, gs.InfectionDeck.Striations, gs.InfectionsThisTurn(), infectionEffect{})
+	}
+
+	if max := gs.rules().MaxOutbreaks(); gs.Outbreaks+outbreaks >= max {
+		return true, fmt.Sprintf("even the best infection draw brings outbreaks to %v of %v", gs.Outbreaks+outbreaks, max)
+	}
+	for _, supply := range gs.CubeSupply() {
+		if placed, ok := cubes[supply.Disease]; ok && supply.Left-placed < 0 {
+			return true, fmt.Sprintf("even the best infection draw places %v %v cubes with %v left", placed, strings.ToLower(string(supply.Disease)), supply.Left)
+		}
+	}
+	return false, ""
+}
+
+// Loss says how the game was lost, or "" while it can still be won:
+// outbreaks reached the limit, a cube was needed with none left in the
+// supply, or a card had to be drawn from the empty city deck.
+func (gs GameState) Loss() string {
+	if max := gs.rules().MaxOutbreaks(); gs.Outbreaks >= max {
+		return fmt.Sprintf("outbreaks reached %v", max)
+	}
+	for _, supply := range gs.CubeSupply() {
+		if supply.Left < 0 {
+			return fmt.Sprintf("the %v cubes ran out", strings.ToLower(string(supply.Disease)))
+		}
+	}
+	if gs.CityDeck != nil && gs.CityDeck.RanOut {
+		return "the city deck ran out"
+	}
+	return ""
+}
+
+// CheckPlaying refuses changes to a game that is over. A lost game only
+// takes EndGame, so the loss is acknowledged before the save is archived.
+// A game of an unknown ruleset can't be played on either, since its rules
+// aren't known.
+func (gs GameState) CheckPlaying() error {
+	if _, err := gs.Rules(); err != nil {
+		return err
+	}
+	if gs.Ended != "" {
+		return fmt.Errorf("The game is over: %v", gs.Ended)
+	}
+	if loss := gs.Loss(); loss != "" {
+		return fmt.Errorf("The game is lost: %v", loss)
+	}
+	return nil
+}
+
+// EndGame acknowledges the end of the game, after which nothing can change
+// it. A game that is neither lost nor won can only be ended by conceding it.
+func (gs *GameState) EndGame(concede bool) error {
+	if gs.Ended != "" {
+		return fmt.Errorf("The game is already over: %v", gs.Ended)
+	}
+	gs.Ended = gs.Loss()
+	if gs.Ended == "" && gs.Won() {
+		gs.Ended = "won"
+	}
+	if gs.Ended == "" {
+		if !concede {
+			return fmt.Errorf("The game isn't over, concede to end it anyway")
+		}
+		gs.Ended = "conceded"
+		if gs.CityDeck != nil && gs.CityDeck.CardsLeftInDeck() == 0 {
+			gs.Ended = "the city deck ran out"
+		}
+	}
+	gs.record(GameEnded, "game ended: %v", gs.Ended)
+	return nil
+}
+
+// checkDeckLeft refuses a draw from the empty city deck. The draw is
+// refused without marking the deck as run out: with the last card drawn at
+// the end of a turn the next player still gets their actions, so only the
+// table knows when the card was due, and end-game! records the loss.
+func (gs GameState) checkDeckLeft() error {
+	if gs.CityDeck.CardsLeftInDeck() > 0 {
+		return nil
+	}
+	return fmt.Errorf("The city deck is empty, so the game is lost: end it with end-game!")
+}
+
+// markDeckRanOut is called after a successful draw and marks the city deck
+// as run out when it emptied with this turn's draws still to make, which
+// loses the game.
+func (gs GameState) markDeckRanOut(turn *Turn) {
+	if turn != nil && gs.CityDeck.CardsLeftInDeck() == 0 && len(turn.DrawnCards) < CityCardsPerTurn {
+		gs.CityDeck.RanOut = true
+	}
+}
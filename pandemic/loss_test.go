@@ -0,0 +1,171 @@
+package pandemic
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestGuaranteedLoss(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", NumInfections: 3},
+		{Name: "b", NumInfections: 3},
+		{Name: "c"},
+	})
+	cityDeck, err := cities.GenerateCityDeck(1, nil, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cityDeck.DrawEpidemic()
+	deck := NewInfectionDeck(cities.CityNames())
+	deck.Striations = []Set{
+		Set{}.Add(CityName("a")).Add(CityName("b")),
+		Set{}.Add(CityName("c")),
+	}
+	gs := GameState{
+		Cities:        &cities,
+		CityDeck:      &cityDeck,
+		InfectionDeck: deck,
+		InfectionRate: 2,
+		Outbreaks:     6,
+	}
+	if lost, reason := gs.GuaranteedLoss(); !lost {
+		t.Fatal("Both cards on top outbreak, which takes outbreaks to 8")
+	} else if reason == "" {
+		t.Fatal("Expected a reason for the loss")
+	}
+
+	gs.Outbreaks = 5
+	if lost, reason := gs.GuaranteedLoss(); lost {
+		t.Fatalf("Outbreaks only reach 7, but got a loss: %v", reason)
+	}
+
+	cityDeck.DrawCard(CardName("a"))
+	cityDeck.DrawCard(CardName("b"))
+	if lost, _ := gs.GuaranteedLoss(); !lost {
+		t.Fatal("Only one city card is left for a turn that needs two")
+	}
+}
+
+func TestGuaranteedLossChain(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", NumInfections: 3, Neighbors: []string{"b"}},
+		{Name: "b", NumInfections: 3, Neighbors: []string{"a"}},
+		{Name: "c"},
+	})
+	cityDeck, err := cities.GenerateCityDeck(1, nil, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cityDeck.DrawEpidemic()
+	deck := NewInfectionDeck(cities.CityNames())
+	deck.Striations = []Set{
+		Set{}.Add(CityName("a")),
+		Set{}.Add(CityName("b")).Add(CityName("c")),
+	}
+	gs := GameState{
+		Cities:           &cities,
+		CityDeck:         &cityDeck,
+		InfectionDeck:    deck,
+		InfectionRate:    1,
+		Outbreaks:        6,
+		ResolveOutbreaks: true,
+	}
+	// a is certain to be drawn and outbreaks into b, the 8th outbreak
+	if lost, reason := gs.GuaranteedLoss(); !lost {
+		t.Fatal("The outbreak in a chains into b, which takes outbreaks to 8")
+	} else if reason == "" {
+		t.Fatal("Expected a reason for the loss")
+	}
+	if city, _ := gs.GetCity("b"); city.NumInfections != 3 || gs.Outbreaks != 6 {
+		t.Fatal("Checking for a loss should not change the board")
+	}
+}
+
+func TestGameLoss(t *testing.T) {
+	cities := Cities([]*City{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}})
+	cityDeck, err := cities.GenerateCityDeck(1, nil, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := &GameState{
+		Cities:        &cities,
+		CityDeck:      &cityDeck,
+		InfectionDeck: NewInfectionDeck(cities.CityNames()),
+		InfectionRate: 2,
+		GameTurns:     InitGameTurns(&Player{HumanName: "p1"}, &Player{HumanName: "p2"}),
+		Log:           &EventLog{},
+	}
+	if err := gs.EndGame(false); err == nil {
+		t.Fatal("A game that isn't lost should only end by conceding")
+	}
+
+	gs.Outbreaks = MaxOutbreaks
+	if loss := gs.Loss(); loss == "" {
+		t.Fatal("Expected the 8th outbreak to lose the game")
+	}
+	if _, err := gs.Infect("a"); err == nil {
+		t.Fatal("Expected infections to be refused once the game is lost")
+	}
+	if err := gs.EndGame(false); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.CheckPlaying(); err == nil || gs.Ended != gs.Loss() {
+		t.Fatalf("Expected the game to be over because %v, ended %q", gs.Loss(), gs.Ended)
+	}
+	if err := gs.EndGame(false); err == nil {
+		t.Fatal("A game can only end once")
+	}
+
+	gs.Outbreaks, gs.Ended = 0, ""
+	for _, card := range []CardName{"a", "b", "c"} {
+		if _, err := gs.CityDeck.DrawCard(card); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := gs.CityDeck.DrawEpidemic(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.DrawCard("d"); err != nil || gs.Loss() != "the city deck ran out" {
+		t.Fatalf("Expected the deck emptying with a draw still to make to lose the game, got %v and %q", err, gs.Loss())
+	}
+
+	gs.CityDeck.RanOut = false
+	if err := gs.DrawUnknown(gs.GameTurns.PlayerOrder[0]); err == nil || gs.Loss() != "" {
+		t.Fatalf("Expected a refused draw from the empty deck to change nothing, got %v and %q", err, gs.Loss())
+	}
+	if err := gs.EndGame(true); err != nil || gs.Ended != "the city deck ran out" {
+		t.Fatalf("Expected conceding with the deck empty to record it ran out, got %v and %q", err, gs.Ended)
+	}
+}
+
+func TestCubeSupply(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", Disease: Yellow.Type, NumInfections: 3},
+		{Name: "b", Disease: Yellow.Type, NumInfections: 2},
+		{Name: "c", Disease: Red.Type, NumInfections: 1},
+	})
+	gs := GameState{
+		Cities:      &cities,
+		DiseaseData: []DiseaseData{Yellow, Red, Blue},
+	}
+	expected := []CubeCount{{Yellow.Type, 19}, {Red.Type, 23}}
+	if supply := gs.CubeSupply(); !reflect.DeepEqual(supply, expected) {
+		t.Fatalf("Expected %v, got %v", expected, supply)
+	}
+	if left := gs.CubesLeft(Yellow.Type); left != 19 {
+		t.Fatalf("Expected 19 yellow cubes left, got %v", left)
+	}
+
+	for i := 0; i < 7; i++ {
+		cities = append(cities, &City{Name: CityName(fmt.Sprintf("y%v", i)), Disease: Yellow.Type, NumInfections: 3})
+	}
+	if loss := gs.Loss(); loss != "the yellow cubes ran out" {
+		t.Fatalf("Expected 26 yellow cubes on the board to lose the game, got %q", loss)
+	}
+
+	gs.Supply = &Supply{PlagueCubes: 2}
+	if supply := gs.CubeSupply(); len(supply) != 1 || supply[0].Disease != Plague.Type || supply[0].Left != 2 {
+		t.Fatalf("Season 2 only counts plague cubes, got %v", supply)
+	}
+}
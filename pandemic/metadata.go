@@ -0,0 +1,107 @@
+package pandemic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var Months = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+
+var monthNames = map[string]string{
+	"jan": "january",
+	"feb": "february",
+	"mar": "march",
+	"apr": "april",
+	"may": "may",
+	"jun": "june",
+	"jul": "july",
+	"aug": "august",
+	"sep": "september",
+	"oct": "october",
+	"nov": "november",
+	"dec": "december",
+}
+
+// GameMetadata is stamped on a game when it is created so that saves and
+// reports can say which game they are without anyone taking notes.
+type GameMetadata struct {
+	CreatedAt  time.Time `json:"created_at"`
+	Month      string    `json:"month"`
+	Attempt    int       `json:"attempt"`
+	Roster     []string  `json:"roster"`
+	Difficulty string    `json:"difficulty"`
+}
+
+func (m *GameMetadata) String() string {
+	return fmt.Sprintf("%v attempt %v, started %v. %v. %v", m.Month, m.Attempt, m.CreatedAt.Format("Jan 2 15:04"), strings.Join(m.Roster, ", "), m.Difficulty)
+}
+
+// GameNameFor names a game after its campaign month and attempt, e.g.
+// march-attempt-2.
+func GameNameFor(month string, attempt int) string {
+	return fmt.Sprintf("%v-attempt-%v", monthNames[month], attempt)
+}
+
+// MonthName is the full name of the campaign month a game is named after,
+// e.g. "march" for mar2, or "" if the game isn't named after a month.
+func MonthName(gameName string) string {
+	month, _ := ParseMonth(gameName)
+	return monthNames[month]
+}
+
+// NextGame is the month and attempt the campaign is up to: a second attempt
+// after losing a month for the first time, otherwise the following month.
+func (c *Campaign) NextGame() (string, int) {
+	if len(c.Months) == 0 {
+		return Months[0], 1
+	}
+	last := c.Months[len(c.Months)-1]
+	if !last.Won && last.Attempt == 1 {
+		return last.Month, 2
+	}
+	for i, month := range Months {
+		if month == last.Month && i+1 < len(Months) {
+			return Months[i+1], 1
+		}
+	}
+	return FinalMonth, last.Attempt
+}
+
+func newMetadata(gameName string, settings NewGameSettings, epidemics int) *GameMetadata {
+	month, attempt := ParseMonth(gameName)
+	roster := []string{}
+	for _, player := range settings.Players {
+		if player.Character != nil {
+			roster = append(roster, fmt.Sprintf("%v (%v)", player.HumanName, player.Character.Type))
+		} else {
+			roster = append(roster, player.HumanName)
+		}
+	}
+	return &GameMetadata{
+		CreatedAt:  time.Now(),
+		Month:      month,
+		Attempt:    attempt,
+		Roster:     roster,
+		Difficulty: fmt.Sprintf("%v epidemics, %v funded events", epidemics, len(settings.FundedEvents)),
+	}
+}
+
+// parseLongGameName reads names made by GameNameFor.
+func parseLongGameName(gameName string) (string, int, bool) {
+	parts := strings.Split(gameName, "-attempt-")
+	if len(parts) != 2 {
+		return "", 0, false
+	}
+	attempt, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return "", 0, false
+	}
+	for month, name := range monthNames {
+		if name == parts[0] {
+			return month, attempt, true
+		}
+	}
+	return "", 0, false
+}
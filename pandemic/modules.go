@@ -0,0 +1,106 @@
+package pandemic
+
+import (
+	"fmt"
+	"sort"
+)
+
+// A Module adds optional mechanics to a ruleset, typically board elements
+// that aren't cities with cubes on them. Modules are chosen in the new game
+// file and keep their state in the game state so that saves round-trip.
+type Module interface {
+	Name() string
+	// ReduceInfection is called before cubes are placed on a city and
+	// returns how many cubes should still be placed.
+	ReduceInfection(gs GameState, city *City, cubes int) int
+	// ExtraMoves lists the moves from a city that the module allows for a
+	// single action.
+	ExtraMoves(gs GameState, from CityName) []Move
+}
+
+var modules = map[string]Module{}
+
+func RegisterModule(module Module) {
+	modules[module.Name()] = module
+}
+
+func init() {
+	RegisterModule(PurificationModule{})
+	RegisterModule(RailroadModule{})
+}
+
+func GetModule(name string) (Module, error) {
+	module, ok := modules[name]
+	if !ok {
+		return nil, fmt.Errorf("Unknown module %q, expected one of %v", name, ModuleNames())
+	}
+	return module, nil
+}
+
+func ModuleNames() []string {
+	names := []string{}
+	for name := range modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (gs GameState) modules() []Module {
+	enabled := []Module{}
+	for _, name := range gs.Modules {
+		if module, err := GetModule(name); err == nil {
+			enabled = append(enabled, module)
+		}
+	}
+	return enabled
+}
+
+func (gs GameState) HasModule(name string) bool {
+	for _, enabled := range gs.Modules {
+		if enabled == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Unlocked is whether the campaign has revealed the spoiler, e.g.
+// FadedUnlock.
+func (gs GameState) Unlocked(name string) bool {
+	for _, unlocked := range gs.Unlocks {
+		if unlocked == name {
+			return true
+		}
+	}
+	return false
+}
+
+// infectCity gives each role and module a chance to prevent the infection
+// before the ruleset places whatever cubes are left.
+func (gs GameState) infectCity(city *City, cubes int) bool {
+	// infection cards of an eradicated disease place no cubes
+	if gs.IsEradicated(city.Disease) {
+		return false
+	}
+	if gs.protectedBy(city) != nil {
+		return false
+	}
+	for _, module := range gs.modules() {
+		cubes = module.ReduceInfection(gs, city, cubes)
+	}
+	if cubes <= 0 {
+		return false
+	}
+	panicked := city.PanicLevel
+	outbreak := gs.rules().InfectCity(gs, city, cubes)
+	gs.recordCity(Infected, city.Name, "%v infected with %v cubes", city.Name, cubes)
+	if outbreak {
+		gs.recordCity(Outbreak, city.Name, "%v outbreaks", city.Name)
+		if city.PanicLevel == Fallen && panicked != Fallen {
+			gs.recordCity(CityFell, city.Name, "%v has fallen", city.Name)
+		}
+	}
+	gs.publish(CityInfected{City: city.Name, Cubes: cubes, Outbreak: outbreak})
+	return outbreak
+}
@@ -0,0 +1,134 @@
+package pandemic
+
+import (
+	"fmt"
+)
+
+// MovePawn moves the player's pawn from where it is to the city, checking
+// the move is allowed and discarding the card a flight takes: the
+// destination's for a direct flight and the origin's for a charter flight.
+// Driving reaches the neighbors and the cities modules add, and shuttle
+// flights go between research stations. Roles react to the pawn arriving
+// just as they do to PlacePawn.
+func (gs GameState) MovePawn(player *Player, kind MoveKind, to CityName) (Move, error) {
+	if err := gs.CheckPlaying(); err != nil {
+		return Move{}, err
+	}
+	from, err := gs.GetCity(player.Location)
+	if err != nil {
+		return Move{}, fmt.Errorf("Nobody knows where %v's pawn is, put it down with set location first", player.HumanName)
+	}
+	destination, err := gs.GetCity(to)
+	if err != nil {
+		return Move{}, err
+	}
+	if from == destination {
+		return Move{}, fmt.Errorf("%v is already in %v", player.HumanName, to)
+	}
+	move := Move{Kind: kind, To: destination.Name}
+	switch kind {
+	case Drive:
+		if !gs.drivable(from, destination.Name) {
+			return Move{}, fmt.Errorf("%v isn't next to %v", to, from.Name)
+		}
+	case DirectFlight:
+		move.Card = destination.Name.CardName()
+	case CharterFlight:
+		move.Card = from.Name.CardName()
+	case ShuttleFlight:
+		if !from.ResearchStation || !destination.ResearchStation {
+			return Move{}, fmt.Errorf("Shuttle flights only go between research stations")
+		}
+	default:
+		return Move{}, fmt.Errorf("%v can't be made with a single action", kind)
+	}
+	if err := gs.CheckMove(from.Name, move); err != nil {
+		return Move{}, err
+	}
+	if !move.Card.Empty() {
+		if !player.HasCard(move.Card) {
+			return Move{}, fmt.Errorf("A %v to %v needs the %v card, %v doesn't have it", kind, to, move.Card, player.HumanName)
+		}
+		if err := gs.Discard(player, move.Card); err != nil {
+			return Move{}, err
+		}
+	}
+	player.Location = destination.Name
+	gs.recordCity(PawnMoved, destination.Name, "%v took a %v from %v to %v", player.HumanName, kind, from.Name, destination.Name)
+	player.Role().Enter(gs, player, destination)
+	return move, nil
+}
+
+func (gs GameState) drivable(from *City, to CityName) bool {
+	for _, neighbor := range from.Neighbors {
+		if CityName(neighbor) == to {
+			return true
+		}
+	}
+	for _, module := range gs.modules() {
+		for _, move := range module.ExtraMoves(gs, from.Name) {
+			if move.To == to {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ShareKnowledge has one player give another a city card. Both pawns have to
+// be in the same city, and the card has to be that city's unless the giver
+// is the Researcher, who may give any city card. Forcing it skips the checks
+// on where the pawns are, for a table that doesn't track them.
+func (gs GameState) ShareKnowledge(card CardName, from, to *Player, force bool) error {
+	if err := gs.CheckPlaying(); err != nil {
+		return err
+	}
+	if from == to {
+		return fmt.Errorf("%v can't give a card to themselves", from.HumanName)
+	}
+	if !from.HasCard(card) {
+		return fmt.Errorf("%v does not seem to have the card %v", from.HumanName, card)
+	}
+	if _, err := gs.GetCity(CityName(card)); err != nil {
+		return fmt.Errorf("Only city cards can be shared, %v isn't one", card)
+	}
+	if err := sharingPosition(card, from, to); err != nil && !force {
+		return err
+	}
+	if err := gs.ExchangeCard(from, to, card); err != nil {
+		return err
+	}
+	if from.Location.Empty() {
+		gs.record(CardShared, "%v gave %v to %v", from.HumanName, card, to.HumanName)
+		return nil
+	}
+	gs.recordCity(CardShared, from.Location, "%v gave %v to %v in %v", from.HumanName, card, to.HumanName, from.Location)
+	return nil
+}
+
+// A SharingPositionError is a card given by pawns that aren't known to stand
+// where it can change hands. The table may not be tracking pawns, so the
+// share can be forced.
+type SharingPositionError struct {
+	Reason string
+}
+
+func (e *SharingPositionError) Error() string {
+	return e.Reason
+}
+
+// sharingPosition checks the pawns stand where the card can change hands.
+func sharingPosition(card CardName, from, to *Player) error {
+	for _, player := range []*Player{from, to} {
+		if player.Location.Empty() {
+			return &SharingPositionError{fmt.Sprintf("Nobody knows where %v's pawn is, put it down with set location first", player.HumanName)}
+		}
+	}
+	if from.Location != to.Location {
+		return &SharingPositionError{fmt.Sprintf("%v is in %v and %v in %v, they have to be in the same city", from.HumanName, from.Location, to.HumanName, to.Location)}
+	}
+	if CityName(card) != from.Location && !from.IsCharacter(Researcher) {
+		return &SharingPositionError{fmt.Sprintf("Only the %v card can be shared in %v, unless the Researcher gives it", from.Location, from.Location)}
+	}
+	return nil
+}
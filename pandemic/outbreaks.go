@@ -0,0 +1,166 @@
+package pandemic
+
+// An OutbreakStep is one city's outbreak in a chain, with what happened to
+// each of its neighbors.
+type OutbreakStep struct {
+	City CityName
+	// Infected neighbors took a cube, and Outbroke those already at 3 cubes
+	// that outbreak next in the chain.
+	Infected []CityName
+	Outbroke []CityName
+	// Quarantined neighbors were protected by their quarantine marker and
+	// Protected ones by a player's role. OtherDisease neighbors took a cube
+	// of the outbreaking disease, kept among their OtherCubes, and are in
+	// Outbroke as well if they already had 3 of them.
+	Quarantined  []CityName
+	Protected    []CityName
+	OtherDisease []CityName
+	// Faded neighbors were taken over by a Faded outbreak, and Spared ones
+	// can't be, so the Faded figures pass them by.
+	Faded  []CityName
+	Spared []CityName
+}
+
+// An OutbreakChain is every outbreak set off by one infection, in the order
+// they are resolved on the board.
+type OutbreakChain []OutbreakStep
+
+// Cities lists every city that outbroke in the chain.
+func (c OutbreakChain) Cities() []CityName {
+	cities := []CityName{}
+	for _, step := range c {
+		cities = append(cities, step.City)
+	}
+	return cities
+}
+
+// FadedRules is whether the Faded spread by their own rules in this game:
+// it has the Faded and has unlocked FadedUnlock.
+func (gs GameState) FadedRules() bool {
+	if _, err := gs.diseaseIndex(Faded.Type); err != nil {
+		return false
+	}
+	return gs.Unlocked(FadedUnlock)
+}
+
+// becomingFaded is whether the Faded can take over cities of the disease,
+// going by the game's own disease data.
+func (gs GameState) becomingFaded(dt DiseaseType) bool {
+	i, err := gs.diseaseIndex(dt)
+	return err == nil && gs.DiseaseData[i].BecomingFaded
+}
+
+// resolveOutbreaks plays out the outbreak in the city and any it sets off.
+// Each outbreak moves the outbreak marker and places a cube of the
+// outbreaking disease on every neighbor, except those that already outbroke
+// in this chain: a city only outbreaks once per chain. A neighbor of another
+// disease keeps the cube among its OtherCubes, and outbreaks in that disease
+// once it has 3. Under the Faded rules a Faded outbreak places figures
+// rather than cubes. They take over the neighbors whose disease is becoming
+// faded and pass every other neighbor by. Games that have outbreaks entered
+// by hand only get the first outbreak back.
+func (gs *GameState) resolveOutbreaks(first *City) OutbreakChain {
+	if !gs.ResolveOutbreaks {
+		chain := OutbreakChain{{City: first.Name}}
+		gs.publish(OutbreakChained{Chain: chain})
+		return chain
+	}
+	type outbreak struct {
+		city    *City
+		disease DiseaseType
+	}
+	fadedRules := gs.FadedRules()
+	chain := OutbreakChain{}
+	outbroke := map[CityName]bool{first.Name: true}
+	queue := []outbreak{{first, first.Disease}}
+	for len(queue) > 0 {
+		city, disease := queue[0].city, queue[0].disease
+		queue = queue[1:]
+		gs.Outbreaks++
+		step := OutbreakStep{City: city.Name}
+		for _, name := range city.Neighbors {
+			neighbor, err := gs.Cities.GetCity(CityName(name))
+			if err != nil || outbroke[neighbor.Name] {
+				continue
+			}
+			fading := fadedRules && disease == Faded.Type && neighbor.Disease != Faded.Type
+			switch {
+			case fading && !gs.becomingFaded(neighbor.Disease):
+				step.Spared = append(step.Spared, neighbor.Name)
+			case neighbor.Quarantined:
+				if !gs.quarantineSpecialistPresent(neighbor.Name) {
+					neighbor.RemoveQuarantine()
+				}
+				step.Quarantined = append(step.Quarantined, neighbor.Name)
+			case neighbor.Disease != disease && !fading:
+				step.OtherDisease = append(step.OtherDisease, neighbor.Name)
+				if gs.placeOtherCube(neighbor, disease) {
+					outbroke[neighbor.Name] = true
+					step.Outbroke = append(step.Outbroke, neighbor.Name)
+					queue = append(queue, outbreak{neighbor, disease})
+				}
+			case gs.protectedBy(neighbor) != nil:
+				step.Protected = append(step.Protected, neighbor.Name)
+			default:
+				if fading {
+					neighbor.Disease = Faded.Type
+					gs.recordCity(CityFaded, neighbor.Name, "%v was taken over by the Faded", neighbor.Name)
+					step.Faded = append(step.Faded, neighbor.Name)
+				}
+				if gs.infectCity(neighbor, 1) {
+					outbroke[neighbor.Name] = true
+					step.Outbroke = append(step.Outbroke, neighbor.Name)
+					queue = append(queue, outbreak{neighbor, neighbor.Disease})
+				} else {
+					step.Infected = append(step.Infected, neighbor.Name)
+				}
+			}
+		}
+		chain = append(chain, step)
+	}
+	gs.publish(OutbreakChained{Chain: chain})
+	return chain
+}
+
+// ProbabilityOfOutbreak is the chance the city outbreaks this turn by being
+// drawn with 3 cubes, or in an epidemic while it has any. Chains set off by
+// neighbors aren't counted.
+func (gs GameState) ProbabilityOfOutbreak(cn CityName) float64 {
+	city, err := gs.Cities.GetCity(cn)
+	if err != nil || city.Quarantined || gs.IsEradicated(city.Disease) || gs.protectedBy(city) != nil {
+		return 0.0
+	}
+	if city.NumInfections == MaxInfections && city.SupplyCubes == 0 {
+		return gs.ProbabilityOfCity(cn)
+	}
+	bottom := gs.InfectionDeck.BottomStriation()
+	if city.NumInfections > city.SupplyCubes && bottom.Contains(cn) {
+		return gs.CityDeck.probabilityOfEpidemic() / float64(bottom.Size())
+	}
+	return 0.0
+}
+
+// NeighborSpillover is the city's exposure to its neighbors' outbreaks: the
+// sum of the chance of each neighbor outbreaking, which is also the number
+// of cubes they are expected to spill onto it. A city that is unlikely to be
+// drawn can still gain cubes this way. Under the Faded rules, Faded neighbors
+// only spill onto cities that can fade.
+func (gs GameState) NeighborSpillover(cn CityName) float64 {
+	city, err := gs.Cities.GetCity(cn)
+	if err != nil || city.Quarantined || gs.protectedBy(city) != nil {
+		return 0.0
+	}
+	fadedRules := gs.FadedRules()
+	var spillover float64
+	for _, name := range city.Neighbors {
+		neighbor, err := gs.Cities.GetCity(CityName(name))
+		if err != nil {
+			continue
+		}
+		if fadedRules && neighbor.Disease == Faded.Type && !gs.becomingFaded(city.Disease) {
+			continue
+		}
+		spillover += gs.ProbabilityOfOutbreak(neighbor.Name)
+	}
+	return spillover
+}
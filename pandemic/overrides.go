@@ -0,0 +1,60 @@
+package pandemic
+
+import (
+	"fmt"
+)
+
+const MaxOutbreaks = 8
+const MaxInfections = 3
+
+// MaxQuarantineMarkers is the number of quarantine markers in the box.
+const MaxQuarantineMarkers = 4
+
+var InfectionRates = []int{2, 3, 4}
+
+// InfectionRateTrack is the infection rate after each epidemic, starting
+// from the rate before the first.
+var InfectionRateTrack = []int{2, 2, 2, 3, 3, 4, 4}
+
+// The Set* functions correct tracking mistakes by hand. Each one checks the
+// new value against the rules of the game and records a manual override in
+// the event log.
+
+func (gs *GameState) SetOutbreaks(outbreaks int) error {
+	maxOutbreaks := gs.rules().MaxOutbreaks()
+	if outbreaks < 0 || outbreaks > maxOutbreaks {
+		return fmt.Errorf("Outbreaks must be between 0 and %v, got %v", maxOutbreaks, outbreaks)
+	}
+	gs.record(ManualOverride, "outbreaks set from %v to %v", gs.Outbreaks, outbreaks)
+	gs.Outbreaks = outbreaks
+	return nil
+}
+
+func (gs *GameState) SetInfectionRate(rate int) error {
+	rates := gs.rules().InfectionRates()
+	valid := false
+	for _, legal := range rates {
+		if rate == legal {
+			valid = true
+		}
+	}
+	if !valid {
+		return fmt.Errorf("%v is not on the infection rate track %v", rate, rates)
+	}
+	gs.record(ManualOverride, "infection rate set from %v to %v", gs.InfectionRate, rate)
+	gs.InfectionRate = rate
+	return nil
+}
+
+func (gs *GameState) SetInfections(cn CityName, infections int) error {
+	city, err := gs.GetCity(cn)
+	if err != nil {
+		return err
+	}
+	if infections < 0 || infections > MaxInfections {
+		return fmt.Errorf("A city must have between 0 and %v cubes, got %v", MaxInfections, infections)
+	}
+	gs.record(ManualOverride, "infections in %v set from %v to %v", cn, city.NumInfections, infections)
+	city.SetInfections(infections)
+	return nil
+}
@@ -0,0 +1,40 @@
+package pandemic
+
+import (
+	"testing"
+)
+
+func TestManualOverrides(t *testing.T) {
+	cities := Cities([]*City{{Name: "a"}})
+	gs := &GameState{Cities: &cities, InfectionRate: 2, Log: &EventLog{}}
+
+	if err := gs.SetOutbreaks(9); err == nil {
+		t.Fatal("Should not be able to set more than 8 outbreaks")
+	}
+	if err := gs.SetInfectionRate(5); err == nil {
+		t.Fatal("Should not be able to set an infection rate that is not on the track")
+	}
+	if err := gs.SetInfections("a", 4); err == nil {
+		t.Fatal("Should not be able to put 4 cubes on a city")
+	}
+	if len(gs.Log.Events) != 0 {
+		t.Fatalf("Rejected overrides should not be logged, got %+v", gs.Log.Events)
+	}
+
+	if err := gs.SetOutbreaks(3); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.SetInfectionRate(3); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.SetInfections("a", 2); err != nil {
+		t.Fatal(err)
+	}
+	city, _ := gs.GetCity("a")
+	if gs.Outbreaks != 3 || gs.InfectionRate != 3 || city.NumInfections != 2 {
+		t.Fatalf("Overrides were not applied: %+v", gs)
+	}
+	if len(gs.Log.Events) != 3 || gs.Log.Events[0].Kind != ManualOverride {
+		t.Fatalf("Expected 3 manual override events, got %+v", gs.Log.Events)
+	}
+}
@@ -11,6 +11,17 @@ func (p PanicLevel) CanBuildResearchStations() bool {
 	return int(p) < 2
 }
 
+// CanFlyInto is false once a city is rioting: players may only reach it by
+// land or sea.
+func (p PanicLevel) CanFlyInto() bool {
+	return p < Rioting2
+}
+
+// CanFlyOutOf is false once a city is collapsing.
+func (p PanicLevel) CanFlyOutOf() bool {
+	return p < Collapsing
+}
+
 const (
 	Nothing = PanicLevel(iota)
 	Unstable
@@ -0,0 +1,65 @@
+package pandemic
+
+import (
+	"fmt"
+)
+
+// IsFlight is true of the moves that take a plane, which panicking cities
+// restrict. Airlifts are events rather than flights, and trains and
+// driving go overland.
+func (k MoveKind) IsFlight() bool {
+	switch k {
+	case DirectFlight, CharterFlight, ShuttleFlight, StationFlight:
+		return true
+	}
+	return false
+}
+
+// CheckMove checks that the panic levels of the cities at either end allow
+// the move.
+func (gs GameState) CheckMove(from CityName, move Move) error {
+	if !move.Kind.IsFlight() {
+		return nil
+	}
+	origin, err := gs.GetCity(from)
+	if err != nil {
+		return err
+	}
+	if !origin.PanicLevel.CanFlyOutOf() {
+		return fmt.Errorf("No %v out of %v, it is %v", move.Kind, from, origin.PanicLevel)
+	}
+	destination, err := gs.GetCity(move.To)
+	if err != nil {
+		return err
+	}
+	if !destination.PanicLevel.CanFlyInto() {
+		return fmt.Errorf("No %v into %v, it is %v", move.Kind, move.To, destination.PanicLevel)
+	}
+	return nil
+}
+
+// CheckRoute checks every move of a route starting from the city.
+func (gs GameState) CheckRoute(from CityName, route *Route) error {
+	for _, move := range route.Moves {
+		if err := gs.CheckMove(from, move); err != nil {
+			return err
+		}
+		from = move.To
+	}
+	return nil
+}
+
+// FlightRestrictions describes the cities whose panic levels limit flights,
+// so plans can say why they avoid them.
+func (gs GameState) FlightRestrictions() []string {
+	restrictions := []string{}
+	for _, city := range *gs.Cities {
+		switch {
+		case !city.PanicLevel.CanFlyOutOf():
+			restrictions = append(restrictions, fmt.Sprintf("no flights into or out of %v (%v)", city.Name, city.PanicLevel))
+		case !city.PanicLevel.CanFlyInto():
+			restrictions = append(restrictions, fmt.Sprintf("no flights into %v (%v)", city.Name, city.PanicLevel))
+		}
+	}
+	return restrictions
+}
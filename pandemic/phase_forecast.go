@@ -0,0 +1,92 @@
+package pandemic
+
+// A PhaseForecast is where the cards of one infection phase come from.
+type PhaseForecast struct {
+	// Turn counts from the current turn, which is 0.
+	Turn int
+	Rate int
+	// Draws counts the cards the phase takes from each of the striations
+	// the deck has now, top first.
+	Draws []int
+	// Epidemic is set on the phase of the turn the forecast puts an
+	// epidemic in, which is drawn at the rate the epidemic moves up to.
+	// Reshuffled counts the cards taken from the discard pile the epidemic
+	// put back on top, which are drawn before the striations.
+	Epidemic   bool
+	Reshuffled int
+	// Skipped is set on this turn's phase when One Quiet Night skips it.
+	Skipped bool
+}
+
+// Total is the number of cards the phase draws.
+func (f PhaseForecast) Total() int {
+	total := f.Reshuffled
+	for _, draws := range f.Draws {
+		total += draws
+	}
+	return total
+}
+
+// ForecastPhases lays out the next infection phases, starting with this
+// turn's. Cards already infected this turn count toward its phase. With an
+// epidemicTurn of 0 or more, an epidemic is drawn in that turn: the rate
+// moves along the track before its infection phase, and the discard pile,
+// with the bottom card, goes back on top, so the striations are only
+// reached again once those cards are used up. The forecast stops early if
+// the deck runs out.
+func (gs GameState) ForecastPhases(phases int, epidemicTurn int) []PhaseForecast {
+	sizes := []int{}
+	for _, striation := range gs.InfectionDeck.Striations {
+		sizes = append(sizes, striation.Size())
+	}
+	discarded := gs.InfectionDeck.Drawn.Size()
+	reshuffled := 0
+	rate := gs.InfectionRate
+	infected := 0
+	if turn := gs.currentTurn(); turn != nil {
+		infected = len(turn.Infected)
+	}
+
+	forecasts := []PhaseForecast{}
+	for t := 0; t < phases; t++ {
+		forecast := PhaseForecast{Turn: t, Rate: rate, Draws: make([]int, len(sizes))}
+		if t == epidemicTurn {
+			for i := len(sizes) - 1; i >= 0; i-- {
+				if sizes[i] > 0 {
+					sizes[i]--
+					discarded++
+					break
+				}
+			}
+			rate = gs.NextInfectionRate()
+			forecast.Rate, forecast.Epidemic = rate, true
+			reshuffled, discarded = discarded, 0
+		}
+		draws := forecast.Rate
+		if t == 0 {
+			draws -= infected
+			if gs.QuietNight() {
+				draws, forecast.Skipped = 0, true
+			}
+		}
+		take := func(pile *int) int {
+			n := draws
+			if n > *pile {
+				n = *pile
+			}
+			*pile -= n
+			draws -= n
+			return n
+		}
+		forecast.Reshuffled = take(&reshuffled)
+		for i := range sizes {
+			forecast.Draws[i] = take(&sizes[i])
+		}
+		if draws > 0 {
+			break
+		}
+		discarded += forecast.Total()
+		forecasts = append(forecasts, forecast)
+	}
+	return forecasts
+}
@@ -0,0 +1,61 @@
+package pandemic
+
+import (
+	"reflect"
+	"testing"
+)
+
+func forecastDraws(forecasts []PhaseForecast) [][]int {
+	draws := [][]int{}
+	for _, forecast := range forecasts {
+		draws = append(draws, forecast.Draws)
+	}
+	return draws
+}
+
+func TestForecastPhases(t *testing.T) {
+	gs := GameState{
+		// two epidemics in, so the next one moves the rate from 2 to 3
+		CityDeck: &CityDeck{Drawn: []CityCard{{IsEpidemic: true}, {IsEpidemic: true}}},
+		InfectionDeck: &InfectionDeck{
+			Drawn: Init(CityName("i"), CityName("j")),
+			Striations: []Set{
+				Init(CityName("a"), CityName("b"), CityName("c")),
+				Init(CityName("d"), CityName("e"), CityName("f"), CityName("g"), CityName("h")),
+			},
+		},
+		InfectionRate: 2,
+	}
+
+	quiet := gs.ForecastPhases(3, -1)
+	if expected := [][]int{{2, 0}, {1, 1}, {0, 2}}; !reflect.DeepEqual(forecastDraws(quiet), expected) {
+		t.Fatalf("Expected draws %v without an epidemic, got %v", expected, forecastDraws(quiet))
+	}
+
+	// the epidemic puts the 4 discarded cards and the bottom card on top,
+	// and the phases after it draw 3 and then 2 from them
+	epidemic := gs.ForecastPhases(3, 1)
+	if expected := [][]int{{2, 0}, {0, 0}, {1, 0}}; !reflect.DeepEqual(forecastDraws(epidemic), expected) {
+		t.Fatalf("Expected draws %v with an epidemic, got %v", expected, forecastDraws(epidemic))
+	}
+	if epidemic[1].Reshuffled != 3 || epidemic[2].Reshuffled != 2 {
+		t.Fatalf("Expected the reshuffled cards to be drawn first, got %+v", epidemic)
+	}
+	if epidemic[0].Rate != 2 || epidemic[1].Rate != 3 || epidemic[2].Rate != 3 || !epidemic[1].Epidemic {
+		t.Fatalf("Expected the rate to go up for the epidemic's phase, got %+v", epidemic)
+	}
+
+	// a rate of 3 drawn from a discard pile of 2 plus the bottom card
+	// empties it, where a rate of 2 would not
+	if now := gs.ForecastPhases(1, 0); now[0].Reshuffled != 3 || now[0].Total() != 3 {
+		t.Fatalf("Expected all 3 draws from the reshuffled cards, got %+v", now[0])
+	}
+	gs.InfectionDeck.Drawn = Init(CityName("i"))
+	if now := gs.ForecastPhases(1, 0); now[0].Reshuffled != 2 || now[0].Draws[0] != 1 {
+		t.Fatalf("Expected the third card to come from the next striation, got %v", now[0].Draws)
+	}
+
+	if len(gs.ForecastPhases(10, -1)) != 4 {
+		t.Fatal("Expected the forecast to stop when the deck runs out")
+	}
+}
@@ -0,0 +1,88 @@
+package pandemic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ActionsPerTurn is how many actions a player takes on their turn.
+const ActionsPerTurn = 4
+
+// CostStep is how many actions of one kind a plan takes.
+type CostStep struct {
+	Kind  string
+	Count int
+}
+
+// PlanCost breaks the actions a plan takes down by kind, and says on which
+// turn the player would finish it.
+type PlanCost struct {
+	Actions int
+	Steps   []CostStep
+	Turn    int // counting from 1, as in the turn tracker
+}
+
+func (c PlanCost) String() string {
+	steps := []string{}
+	for _, step := range c.Steps {
+		steps = append(steps, fmt.Sprintf("%v %v", step.Count, step.Kind))
+	}
+	if len(steps) == 0 {
+		return "no actions"
+	}
+	return fmt.Sprintf("%v actions (%v), done on turn %v", c.Actions, strings.Join(steps, ", "), c.Turn)
+}
+
+func (c *PlanCost) add(kind string, actions int) {
+	c.Actions += actions
+	if actions == 0 {
+		kind += " (free)"
+	}
+	for i, step := range c.Steps {
+		if step.Kind == kind {
+			c.Steps[i].Count++
+			return
+		}
+	}
+	c.Steps = append(c.Steps, CostStep{kind, 1})
+}
+
+// Cost is what the route costs the player in actions. Airlifts are free.
+func (gs GameState) Cost(player *Player, route *Route) PlanCost {
+	cost := PlanCost{}
+	for _, move := range route.Moves {
+		if move.Kind == Airlift {
+			cost.add(string(move.Kind), 0)
+		} else {
+			cost.add(string(move.Kind), 1)
+		}
+	}
+	cost.Turn = gs.CompletionTurn(player, cost.Actions)
+	return cost
+}
+
+// CompletionTurn is the turn on which the player would have taken the given
+// number of actions, starting with the current turn if it is theirs and they
+// are still taking actions, less the actions already taken this turn.
+func (gs GameState) CompletionTurn(player *Player, actions int) int {
+	turns := gs.GameTurns
+	cur, err := turns.CurrentTurn()
+	if err != nil || actions == 0 {
+		return turns.CurTurn + 1
+	}
+	first := turns.CurTurn
+	if cur.Player.HumanName != player.HumanName || cur.Phase() != ActionPhase {
+		for first = turns.CurTurn + 1; turns.PlayerOrder[first%len(turns.PlayerOrder)].HumanName != player.HumanName; first++ {
+			if first > turns.CurTurn+len(turns.PlayerOrder) {
+				return turns.CurTurn + 1
+			}
+		}
+	}
+	if first == turns.CurTurn && len(cur.Actions) > 0 {
+		// the actions already taken push the plan back as if they were
+		// part of it
+		actions += len(cur.Actions)
+	}
+	ownTurns := (actions + ActionsPerTurn - 1) / ActionsPerTurn
+	return first + (ownTurns-1)*len(turns.PlayerOrder) + 1
+}
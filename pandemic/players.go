@@ -18,32 +18,62 @@ const (
 	Generalist           = "Generalist"
 	Soldier              = "Soldier"
 	Virologist           = "Virologist"
+	ContingencyPlanner   = "ContingencyPlanner"
 )
 
+// HandLimit is the most cards a player may hold. An event a Contingency
+// Planner has stored doesn't count.
+const HandLimit = 7
+
 type Player struct {
 	HumanName  string     `json:"human_name"`
 	Character  *Character `json:"character"`
 	Location   CityName
 	StartCards []CardName `json:"start_cards"`
 	Cards      []*CityCard
+	// StoredEvent is the event card a Contingency Planner has taken back
+	// from the discard pile. It is removed from the game once played.
+	StoredEvent *CityCard `json:"stored_event,omitempty"`
+	// UnknownCards is how many cards in the player's hand we failed to
+	// record. They could be any card that has not been seen elsewhere.
+	UnknownCards int `json:"unknown_cards,omitempty"`
+}
+
+// HandSize counts the cards in the player's hand, recorded or not.
+func (p *Player) HandSize() int {
+	return len(p.Cards) + p.UnknownCards
+}
+
+func (p *Player) IsCharacter(characterType CharacterType) bool {
+	return p.Character != nil && p.Character.Type == characterType
 }
 
-func (p *Player) Discard(cardName CardName) error {
-	filtered := []*CityCard{}
+func (p *Player) HasCard(cardName CardName) bool {
 	for _, card := range p.Cards {
-		if card.Name() != cardName {
-			filtered = append(filtered, card)
+		if card.Name() == cardName {
+			return true
 		}
 	}
-	if len(filtered) == len(p.Cards) {
-		return fmt.Errorf("%v does not seem to have %v\n", p.HumanName, cardName)
+	return false
+}
+
+// Discard takes one card of the given name out of the player's hand and
+// returns it. A player holding two copies keeps the other.
+func (p *Player) Discard(cardName CardName) (*CityCard, error) {
+	for i, card := range p.Cards {
+		if card.Name() == cardName {
+			p.Cards = append(append([]*CityCard{}, p.Cards[:i]...), p.Cards[i+1:]...)
+			return card, nil
+		}
 	}
-	p.Cards = filtered
-	return nil
+	return nil, fmt.Errorf("%v does not seem to have %v\n", p.HumanName, cardName)
 }
 
 type Character struct {
 	Name        string        `json:"name"`
 	Type        CharacterType `json:"type"`
 	TurnMessage string        `json:"turn_message"`
+	// Scars are the Legacy scars the character has taken, carried from
+	// game to game.
+	Scars []string `json:"scars,omitempty"`
 }
@@ -0,0 +1,228 @@
+package pandemic
+
+import (
+	"fmt"
+	"math"
+)
+
+// The reference engine works out the same odds as the tracker the slow way,
+// by trying every possibility, so that changes to the real math can be
+// checked against something too simple to get wrong. It is only fast
+// enough for small decks or a turn's worth of draws.
+
+// maxReferenceLayouts caps how many epidemic placements the reference will
+// try before giving up on a deck.
+const maxReferenceLayouts = 5000000
+
+// referenceTolerance is how far apart the engines may be before verify
+// reports it, to allow for rounding.
+const referenceTolerance = 1e-9
+
+// ReferenceInfectionProbability is the chance that the city is among the
+// next draws infection cards, trying every order the cards could come off
+// the deck. Each draw takes a card from the top striation in proportion to
+// its weight.
+func ReferenceInfectionProbability(d *InfectionDeck, city CityName, draws int) float64 {
+	striations := [][]CityName{}
+	for i := range d.Striations {
+		striations = append(striations, d.CitiesInStriation(i))
+	}
+	return d.referenceDraws(striations, city, draws)
+}
+
+func (d *InfectionDeck) referenceDraws(striations [][]CityName, city CityName, draws int) float64 {
+	for len(striations) > 0 && len(striations[0]) == 0 {
+		striations = striations[1:]
+	}
+	if draws == 0 || len(striations) == 0 {
+		return 0.0
+	}
+	top := striations[0]
+	var total float64
+	for _, card := range top {
+		total += d.weight(card)
+	}
+	var probability float64
+	for i, card := range top {
+		p := d.weight(card) / total
+		if card == city {
+			probability += p
+			continue
+		}
+		rest := append([][]CityName{append(append([]CityName{}, top[:i]...), top[i+1:]...)}, striations[1:]...)
+		probability += p * d.referenceDraws(rest, city, draws-1)
+	}
+	return probability
+}
+
+// ReferenceEpidemicProbability is the chance that the city deck card at
+// index is an epidemic, given whether each card before it was one. It tries
+// every way of splitting the deck into piles and every place the epidemic
+// could be in each pile, keeping those that agree with the draws so far.
+// Like the tracker, every pile split still possible counts the same.
+func (c *CityDeck) ReferenceEpidemicProbability(history []bool, index int) (float64, error) {
+	cards := len(c.All) - len(c.StartCities)
+	epidemics := c.NumEpidemics()
+	if epidemics == 0 {
+		return 0.0, nil
+	}
+	var aggregate float64
+	possible := 0
+	tried := 0
+	for _, sizes := range pileSplits(cards, epidemics) {
+		matching, hits := 0, 0
+		positions := make([]int, epidemics)
+		var place func(pile, start int) error
+		place = func(pile, start int) error {
+			if pile == len(sizes) {
+				tried++
+				if tried > maxReferenceLayouts {
+					return fmt.Errorf("The deck is too big for the reference engine")
+				}
+				if !layoutMatches(positions, history) {
+					return nil
+				}
+				matching++
+				for _, position := range positions {
+					if position == index {
+						hits++
+					}
+				}
+				return nil
+			}
+			for offset := 0; offset < sizes[pile]; offset++ {
+				positions[pile] = start + offset
+				if err := place(pile+1, start+sizes[pile]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if err := place(0, 0); err != nil {
+			return 0.0, err
+		}
+		if matching > 0 {
+			possible++
+			aggregate += float64(hits) / float64(matching)
+		}
+	}
+	if possible == 0 {
+		return 0.0, errNoMatchingDeck
+	}
+	return aggregate / float64(possible), nil
+}
+
+var errNoMatchingDeck = fmt.Errorf("No way of building the city deck matches the draws so far")
+
+// pileSplits lists every order of pile sizes when the cards are split as
+// evenly as possible.
+func pileSplits(cards, piles int) [][]int {
+	small := cards / piles
+	big := cards % piles
+	splits := [][]int{}
+	var build func(sizes []int, bigLeft int)
+	build = func(sizes []int, bigLeft int) {
+		if len(sizes) == piles {
+			if bigLeft == 0 {
+				splits = append(splits, append([]int{}, sizes...))
+			}
+			return
+		}
+		if bigLeft > 0 {
+			build(append(sizes, small+1), bigLeft-1)
+		}
+		build(append(sizes, small), bigLeft)
+	}
+	build(nil, big)
+	return splits
+}
+
+// layoutMatches checks that the epidemics drawn so far are exactly the ones
+// the layout puts at the top of the deck.
+func layoutMatches(positions []int, history []bool) bool {
+	drawn := 0
+	for _, position := range positions {
+		if position >= len(history) {
+			continue
+		}
+		if !history[position] {
+			return false
+		}
+		drawn++
+	}
+	for _, wasEpidemic := range history {
+		if wasEpidemic {
+			drawn--
+		}
+	}
+	return drawn == 0
+}
+
+// epidemicHistory is whether each city deck draw after the starting hands
+// was an epidemic, in the order they were entered.
+func (c *CityDeck) epidemicHistory() ([]bool, error) {
+	if c.UnknownDraws > 0 {
+		return nil, fmt.Errorf("The order of the city deck is unknown while there are unknown draws")
+	}
+	history := []bool{}
+	for _, card := range c.Drawn[len(c.StartCities):] {
+		history = append(history, card.IsEpidemic)
+	}
+	return history, nil
+}
+
+// Discrepancy is an answer the tracker and the reference engine disagree on.
+type Discrepancy struct {
+	What      string
+	Tracker   float64
+	Reference float64
+}
+
+func (d Discrepancy) String() string {
+	return fmt.Sprintf("%v: tracker %.6f, reference %.6f", d.What, d.Tracker, d.Reference)
+}
+
+// Verify checks the tracker's odds for the next infections and the next two
+// city cards against the reference engine.
+func (gs GameState) Verify() ([]Discrepancy, error) {
+	discrepancies := []Discrepancy{}
+	check := func(what string, tracker, reference float64) {
+		if math.Abs(tracker-reference) > referenceTolerance {
+			discrepancies = append(discrepancies, Discrepancy{What: what, Tracker: tracker, Reference: reference})
+		}
+	}
+	for _, card := range gs.InfectionDeck.Cards() {
+		if card.Location != InStriation {
+			continue
+		}
+		check(fmt.Sprintf("Infecting %v", card.City),
+			gs.InfectionDeck.ProbabilityOfDrawing(card.City, gs.InfectionRate),
+			ReferenceInfectionProbability(gs.InfectionDeck, card.City, gs.InfectionRate))
+	}
+	history, err := gs.CityDeck.epidemicHistory()
+	if err != nil {
+		return discrepancies, err
+	}
+	if gs.CityDeck.EpidemicsDrawn() >= gs.CityDeck.NumEpidemics() {
+		return discrepancies, nil
+	}
+	index := len(history)
+	first, err := gs.CityDeck.ReferenceEpidemicProbability(history, index)
+	if err != nil {
+		return discrepancies, err
+	}
+	// a next card no deck allows has no chance, so what follows it doesn't
+	// matter
+	afterEpidemic, err := gs.CityDeck.ReferenceEpidemicProbability(append(history[:index:index], true), index+1)
+	if err != nil && err != errNoMatchingDeck {
+		return discrepancies, err
+	}
+	afterCity, err := gs.CityDeck.ReferenceEpidemicProbability(append(history[:index:index], false), index+1)
+	if err != nil && err != errNoMatchingDeck {
+		return discrepancies, err
+	}
+	analysis := gs.CityDeck.EpidemicAnalysis()
+	check("Epidemic on the next city card", analysis.FirstCardProbability, first)
+	check("Epidemic on the city card after", analysis.SecondCardProbability, first*afterEpidemic+(1.0-first)*afterCity)
+	return discrepancies, nil
+}
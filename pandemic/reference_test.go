@@ -0,0 +1,61 @@
+package pandemic
+
+import (
+	"math"
+	"testing"
+)
+
+// TestReferenceInfection checks the infection odds against the reference
+// engine for every card, through striation boundaries and weak shuffles.
+func TestReferenceInfection(t *testing.T) {
+	deck := testInfectionDeck()
+	deck.Draw("NewYork")
+	deck.Draw("Miami")
+	deck.ShuffleDrawn()
+	if err := deck.MarkWeaklyShuffled(0); err != nil {
+		t.Fatal(err)
+	}
+	for _, city := range []CityName{"SanFrancisco", "NewYork", "Montreal", "Miami", "Washington"} {
+		for draws := 1; draws <= 4; draws++ {
+			got := deck.ProbabilityOfDrawing(city, draws)
+			want := ReferenceInfectionProbability(deck, city, draws)
+			if math.Abs(got-want) > referenceTolerance {
+				t.Errorf("%v in %v draws: tracker %v, reference %v", city, draws, got, want)
+			}
+		}
+	}
+}
+
+// TestReferenceEpidemic plays a small deck through, checking the epidemic
+// odds against the reference engine before every draw.
+func TestReferenceEpidemic(t *testing.T) {
+	cities := Cities{}
+	for _, name := range []CityName{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		cities = append(cities, &City{Name: name})
+	}
+	cityDeck, err := cities.GenerateCityDeck(3, nil, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	infectionDeck := NewInfectionDeck(cities.CityNames())
+	gs := GameState{Cities: &cities, CityDeck: &cityDeck, InfectionDeck: infectionDeck, InfectionRate: 2, GameTurns: InitGameTurns()}
+	// piles of 4, 4 and 3 cards, epidemics drawn 2nd, 5th and 10th
+	draws := []CardName{"a", "", "b", "c", "", "d", "e", "f", "g", "", "h"}
+	for i, card := range draws {
+		discrepancies, err := gs.Verify()
+		if err != nil {
+			t.Fatalf("Draw %v: %v", i, err)
+		}
+		for _, discrepancy := range discrepancies {
+			t.Errorf("Draw %v: %v", i, discrepancy)
+		}
+		if card == "" {
+			err = cityDeck.DrawEpidemic()
+		} else {
+			_, err = cityDeck.DrawCard(card)
+		}
+		if err != nil {
+			t.Fatalf("Draw %v: %v", i, err)
+		}
+	}
+}
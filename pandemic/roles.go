@@ -0,0 +1,194 @@
+package pandemic
+
+// A Role is the special ability a character gives its player. The engine
+// asks the role wherever the ability bends the rules, so that abilities
+// are played for the table rather than remembered.
+type Role interface {
+	Type() CharacterType
+	// CardsToCure takes the number of cards the rules ask for to discover a
+	// cure and returns the number the player needs, or false if the player
+	// can't discover cures at all.
+	CardsToCure(required int) (int, bool)
+	// Protects reports whether the player keeps cubes off the city.
+	Protects(gs GameState, player *Player, city *City) bool
+	// Enter is called when the board around the player's pawn may have
+	// changed, e.g. after a cure, with the city the pawn is in.
+	Enter(gs GameState, player *Player, city *City)
+	// Reminder is what the player is told at the start of their turn so
+	// the ability isn't forgotten, or "" for none.
+	Reminder() string
+}
+
+var roles = map[CharacterType]Role{}
+
+func RegisterRole(role Role) {
+	roles[role.Type()] = role
+}
+
+func init() {
+	RegisterRole(MedicRole{})
+	RegisterRole(QuarantineSpecialistRole{})
+	RegisterRole(ScientistRole{})
+	RegisterRole(ColonelRole{})
+	RegisterRole(SoldierRole{})
+}
+
+// Role is the player's ability. Characters without one, or players without
+// a character, get the plain rules.
+func (p *Player) Role() Role {
+	if p.Character == nil {
+		return plainRole{}
+	}
+	if role, ok := roles[p.Character.Type]; ok {
+		return role
+	}
+	return plainRole{p.Character.Type}
+}
+
+// protectedBy is the player whose role keeps cubes off the city, if any.
+func (gs GameState) protectedBy(city *City) *Player {
+	if gs.GameTurns == nil {
+		return nil
+	}
+	for _, player := range gs.GameTurns.PlayerOrder {
+		if player.Location != "" && player.Role().Protects(gs, player, city) {
+			return player
+		}
+	}
+	return nil
+}
+
+// PlacePawn records where the player's pawn is, so that roles acting on the
+// cities around it, such as the Quarantine Specialist's, cover the right
+// ones.
+func (gs GameState) PlacePawn(player *Player, cn CityName) error {
+	city, err := gs.Cities.GetCity(cn)
+	if err != nil {
+		return err
+	}
+	player.Location = city.Name
+	gs.recordCity(PawnPlaced, city.Name, "%v's pawn is in %v", player.HumanName, city.Name)
+	player.Role().Enter(gs, player, city)
+	return nil
+}
+
+// ProtectedBy is the player whose role keeps cubes off the city, or nil if
+// nobody's does.
+func (gs GameState) ProtectedBy(cn CityName) *Player {
+	city, err := gs.Cities.GetCity(cn)
+	if err != nil {
+		return nil
+	}
+	return gs.protectedBy(city)
+}
+
+// enterRoles lets every role react to a change on the board.
+func (gs GameState) enterRoles() {
+	if gs.GameTurns == nil {
+		return
+	}
+	for _, player := range gs.GameTurns.PlayerOrder {
+		if city, err := gs.Cities.GetCity(player.Location); err == nil {
+			player.Role().Enter(gs, player, city)
+		}
+	}
+}
+
+type plainRole struct {
+	characterType CharacterType
+}
+
+func (r plainRole) Type() CharacterType { return r.characterType }
+
+func (plainRole) CardsToCure(required int) (int, bool) { return required, true }
+
+func (plainRole) Protects(gs GameState, player *Player, city *City) bool { return false }
+
+func (plainRole) Enter(gs GameState, player *Player, city *City) {}
+
+func (plainRole) Reminder() string { return "" }
+
+// MedicRole removes every cube of a cured disease from the city the Medic is
+// in, and keeps them from being placed there.
+type MedicRole struct {
+	plainRole
+}
+
+func (MedicRole) Type() CharacterType { return Medic }
+
+func (MedicRole) Protects(gs GameState, player *Player, city *City) bool {
+	return city.Name == player.Location && gs.isCured(city.Disease)
+}
+
+func (MedicRole) Reminder() string {
+	return "Medic: cured cubes come off every city you enter, and stay off while you are there"
+}
+
+func (MedicRole) Enter(gs GameState, player *Player, city *City) {
+	if city.NumInfections == 0 || !gs.isCured(city.Disease) {
+		return
+	}
+	cubes := city.NumInfections
+	gs.recordCity(Treated, city.Name, "%v's Medic removed %v cubes of cured %v from %v", player.HumanName, cubes, city.Disease, city.Name)
+	city.SetInfections(0)
+	gs.publish(CityTreated{City: city.Name, Cubes: cubes})
+}
+
+// QuarantineSpecialistRole keeps cubes off the city the Quarantine
+// Specialist is in and every city connected to it.
+type QuarantineSpecialistRole struct {
+	plainRole
+}
+
+func (QuarantineSpecialistRole) Type() CharacterType { return QuarantineSpecialist }
+
+func (QuarantineSpecialistRole) Reminder() string {
+	return "Quarantine Specialist: no cubes are placed on your city or the cities connected to it"
+}
+
+func (QuarantineSpecialistRole) Protects(gs GameState, player *Player, city *City) bool {
+	if city.Name == player.Location {
+		return true
+	}
+	for _, neighbor := range city.Neighbors {
+		if CityName(neighbor) == player.Location {
+			return true
+		}
+	}
+	return false
+}
+
+// ScientistRole needs one card fewer to discover a cure.
+type ScientistRole struct {
+	plainRole
+}
+
+func (ScientistRole) Type() CharacterType { return Scientist }
+
+func (ScientistRole) CardsToCure(required int) (int, bool) { return required - 1, true }
+
+func (ScientistRole) Reminder() string {
+	return "Scientist: you need one card fewer to discover a cure"
+}
+
+// ColonelRole needs two cards more to discover a cure.
+type ColonelRole struct {
+	plainRole
+}
+
+func (ColonelRole) Type() CharacterType { return Colonel }
+
+func (ColonelRole) CardsToCure(required int) (int, bool) { return required + 2, true }
+
+func (ColonelRole) Reminder() string { return "Colonel: you need two cards more to discover a cure" }
+
+// SoldierRole can't discover cures.
+type SoldierRole struct {
+	plainRole
+}
+
+func (SoldierRole) Type() CharacterType { return Soldier }
+
+func (SoldierRole) CardsToCure(required int) (int, bool) { return 0, false }
+
+func (SoldierRole) Reminder() string { return "Soldier: you can't discover cures" }
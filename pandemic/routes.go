@@ -0,0 +1,232 @@
+package pandemic
+
+import (
+	"container/heap"
+	"fmt"
+	"strings"
+)
+
+type MoveKind string
+
+const (
+	Drive         = MoveKind("drive")
+	DirectFlight  = MoveKind("direct flight")
+	CharterFlight = MoveKind("charter flight")
+	ShuttleFlight = MoveKind("shuttle flight")
+	StationFlight = MoveKind("station flight") // Operations Expert only
+	Airlift       = MoveKind("airlift")
+)
+
+type Move struct {
+	Kind MoveKind
+	To   CityName
+	Card CardName // the card spent on this move, if any
+}
+
+// A Route is a sequence of moves along with what it costs the team to make
+// them.
+type Route struct {
+	Moves   []Move
+	Actions int
+	Cards   []CardName
+	// Unplanned are the cards past MaxRouteCards that the search left out.
+	Unplanned []CardName
+}
+
+// MaxRouteCards is how many cards PlanRoutes considers spending, since
+// every card doubles the routes it has to search.
+const MaxRouteCards = 16
+
+func (r *Route) String() string {
+	steps := []string{}
+	for _, move := range r.Moves {
+		if move.Card.Empty() {
+			steps = append(steps, fmt.Sprintf("%v to %v", move.Kind, move.To))
+		} else {
+			steps = append(steps, fmt.Sprintf("%v to %v (%v)", move.Kind, move.To, move.Card))
+		}
+	}
+	if len(steps) == 0 {
+		return "already there"
+	}
+	return fmt.Sprintf("%v actions, %v cards: %v", r.Actions, len(r.Cards), strings.Join(steps, ", "))
+}
+
+// PlanRoutes finds the route using the fewest actions and the route using
+// the fewest cards for the player to reach the destination. City cards in
+// the player's hand can be spent on direct and charter flights, and Airlift
+// events held or stored by anyone on the team can be spent to move for free.
+// An Operations Expert may also once discard any city card to fly from a
+// research station to anywhere. Flights the panic levels forbid are left out.
+func (gs GameState) PlanRoutes(player *Player, from, to CityName) (fastest *Route, cheapest *Route, err error) {
+	if _, err := gs.GetCity(from); err != nil {
+		return nil, nil, err
+	}
+	if _, err := gs.GetCity(to); err != nil {
+		return nil, nil, err
+	}
+	cards := []*CityCard{}
+	for _, card := range player.Cards {
+		if card.IsCity() || card.FundedEventName == AirliftEvent {
+			cards = append(cards, card)
+		}
+	}
+	for _, other := range gs.GameTurns.PlayerOrder {
+		if other != player {
+			for _, card := range other.Cards {
+				if card.FundedEventName == AirliftEvent {
+					cards = append(cards, card)
+				}
+			}
+		}
+		if other.StoredEvent != nil && other.StoredEvent.FundedEventName == AirliftEvent {
+			cards = append(cards, other.StoredEvent)
+		}
+	}
+	unplanned := []CardName{}
+	if len(cards) > MaxRouteCards {
+		for _, card := range cards[MaxRouteCards:] {
+			unplanned = append(unplanned, card.Name())
+		}
+		cards = cards[:MaxRouteCards]
+	}
+	opsExpert := player.IsCharacter(OperationsExpert)
+	fastest = gs.searchRoute(from, to, cards, opsExpert, func(a, b routeCost) bool {
+		return a.actions < b.actions || (a.actions == b.actions && a.cards < b.cards)
+	})
+	cheapest = gs.searchRoute(from, to, cards, opsExpert, func(a, b routeCost) bool {
+		return a.cards < b.cards || (a.cards == b.cards && a.actions < b.actions)
+	})
+	if fastest == nil || cheapest == nil {
+		return nil, nil, fmt.Errorf("There is no way to get from %v to %v", from, to)
+	}
+	fastest.Unplanned, cheapest.Unplanned = unplanned, unplanned
+	return fastest, cheapest, nil
+}
+
+type routeCost struct {
+	actions int
+	cards   int
+}
+
+type routeState struct {
+	city          CityName
+	spent         uint32 // bitmask of spent cards
+	stationFlight bool   // the Operations Expert may only do this once per turn
+}
+
+type routeNode struct {
+	state routeState
+	cost  routeCost
+	route *Route
+}
+
+type routeQueue struct {
+	nodes []*routeNode
+	less  func(a, b routeCost) bool
+}
+
+func (q routeQueue) Len() int            { return len(q.nodes) }
+func (q routeQueue) Less(i, j int) bool  { return q.less(q.nodes[i].cost, q.nodes[j].cost) }
+func (q routeQueue) Swap(i, j int)       { q.nodes[i], q.nodes[j] = q.nodes[j], q.nodes[i] }
+func (q *routeQueue) Push(x interface{}) { q.nodes = append(q.nodes, x.(*routeNode)) }
+func (q *routeQueue) Pop() interface{} {
+	last := q.nodes[len(q.nodes)-1]
+	q.nodes = q.nodes[:len(q.nodes)-1]
+	return last
+}
+
+// searchRoute is a uniform cost search over (city, spent cards) where the
+// cost ordering decides whether actions or cards are more precious.
+func (gs GameState) searchRoute(from, to CityName, cards []*CityCard, opsExpert bool, less func(a, b routeCost) bool) *Route {
+	queue := &routeQueue{less: less}
+	heap.Push(queue, &routeNode{routeState{from, 0, false}, routeCost{}, &Route{}})
+	stations := gs.Cities.ResearchStations()
+	visited := map[routeState]bool{}
+
+	for queue.Len() > 0 {
+		node := heap.Pop(queue).(*routeNode)
+		if node.state.city == to {
+			return node.route
+		}
+		if visited[node.state] {
+			continue
+		}
+		visited[node.state] = true
+
+		push := func(move Move, actions int, cardIndex int) {
+			if gs.CheckMove(node.state.city, move) != nil {
+				return
+			}
+			state := routeState{move.To, node.state.spent, node.state.stationFlight || move.Kind == StationFlight}
+			cost := routeCost{node.cost.actions + actions, node.cost.cards}
+			route := &Route{
+				Moves:   append(append([]Move{}, node.route.Moves...), move),
+				Actions: cost.actions,
+				Cards:   append([]CardName{}, node.route.Cards...),
+			}
+			if cardIndex >= 0 {
+				state.spent |= 1 << uint(cardIndex)
+				cost.cards++
+				route.Cards = append(route.Cards, move.Card)
+			}
+			if !visited[state] {
+				heap.Push(queue, &routeNode{state, cost, route})
+			}
+		}
+
+		city, err := gs.GetCity(node.state.city)
+		if err != nil {
+			continue
+		}
+		for _, neighbor := range city.Neighbors {
+			push(Move{Drive, CityName(neighbor), ""}, 1, -1)
+		}
+		for _, module := range gs.modules() {
+			for _, move := range module.ExtraMoves(gs, city.Name) {
+				push(move, 1, -1)
+			}
+		}
+		if city.ResearchStation {
+			for _, station := range stations {
+				if station != city.Name {
+					push(Move{ShuttleFlight, station, ""}, 1, -1)
+				}
+			}
+		}
+		stationFlight := opsExpert && city.ResearchStation && !node.state.stationFlight
+		for i, card := range cards {
+			if node.state.spent&(1<<uint(i)) != 0 {
+				continue
+			}
+			switch {
+			case card.FundedEventName == AirliftEvent:
+				push(Move{Airlift, to, card.Name()}, 0, i)
+			case card.CityName == node.state.city:
+				push(Move{CharterFlight, to, card.Name()}, 1, i)
+			case card.IsCity():
+				push(Move{DirectFlight, card.CityName, card.Name()}, 1, i)
+				if stationFlight {
+					push(Move{StationFlight, to, card.Name()}, 1, i)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// StationCost is the card the player would have to discard to build a
+// research station in the given city. The Operations Expert builds stations
+// without discarding anything, in which case the card name is empty.
+func (gs GameState) StationCost(player *Player, cn CityName) (CardName, error) {
+	if _, err := gs.canHoldStation(cn); err != nil {
+		return "", err
+	}
+	if player.IsCharacter(OperationsExpert) {
+		return "", nil
+	}
+	if !player.HasCard(cn.CardName()) {
+		return "", fmt.Errorf("%v needs the %v card to build a research station there", player.HumanName, cn)
+	}
+	return cn.CardName(), nil
+}
@@ -0,0 +1,323 @@
+package pandemic
+
+import (
+	"testing"
+)
+
+// a - b - c - d - e in a line
+func routeTestState(cards ...*CityCard) (*GameState, *Player) {
+	cities := Cities([]*City{
+		{Name: "a", Neighbors: []string{"b"}},
+		{Name: "b", Neighbors: []string{"a", "c"}},
+		{Name: "c", Neighbors: []string{"b", "d"}},
+		{Name: "d", Neighbors: []string{"c", "e"}},
+		{Name: "e", Neighbors: []string{"d"}},
+	})
+	player := &Player{HumanName: "p1", Location: "a", Cards: cards}
+	return &GameState{
+		Cities:    &cities,
+		GameTurns: InitGameTurns(player, &Player{HumanName: "p2"}),
+	}, player
+}
+
+func TestPlanRoutesDriving(t *testing.T) {
+	gs, player := routeTestState()
+	fastest, cheapest, err := gs.PlanRoutes(player, "a", "e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fastest.Actions != 4 || len(fastest.Cards) != 0 || cheapest.Actions != 4 {
+		t.Fatalf("Expected to drive 4 cities, got %v and %v", fastest, cheapest)
+	}
+}
+
+func TestPlanRoutesWithCards(t *testing.T) {
+	gs, player := routeTestState(&CityCard{CityName: "a"}, &CityCard{CityName: "d"})
+	fastest, cheapest, err := gs.PlanRoutes(player, "a", "e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fastest.Actions != 1 || len(fastest.Cards) != 1 || fastest.Moves[0].Kind != CharterFlight {
+		t.Fatalf("Expected a charter flight to be fastest, got %v", fastest)
+	}
+	if cheapest.Actions != 4 || len(cheapest.Cards) != 0 {
+		t.Fatalf("Expected driving to be cheapest, got %v", cheapest)
+	}
+}
+
+func TestPlanRoutesAirlift(t *testing.T) {
+	gs, player := routeTestState()
+	gs.GameTurns.PlayerOrder[1].Cards = []*CityCard{{FundedEventName: AirliftEvent}}
+	fastest, _, err := gs.PlanRoutes(player, "a", "e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fastest.Actions != 0 || fastest.Moves[0].Kind != Airlift {
+		t.Fatalf("Expected an airlift from a teammate to be fastest, got %v", fastest)
+	}
+}
+
+func TestPlanRoutesUnplannedCards(t *testing.T) {
+	cards := []*CityCard{}
+	for i := 0; i < MaxRouteCards+2; i++ {
+		cards = append(cards, &CityCard{CityName: "b"})
+	}
+	gs, player := routeTestState(cards...)
+	fastest, cheapest, err := gs.PlanRoutes(player, "a", "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fastest.Unplanned) != 2 || len(cheapest.Unplanned) != 2 {
+		t.Fatalf("Expected 2 cards to be left out of the plan, got %v and %v", fastest.Unplanned, cheapest.Unplanned)
+	}
+}
+
+func TestPlanRoutesStoredAirlift(t *testing.T) {
+	gs, player := routeTestState()
+	player.StoredEvent = &CityCard{FundedEventName: AirliftEvent}
+	fastest, _, err := gs.PlanRoutes(player, "a", "e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fastest.Actions != 0 || fastest.Moves[0].Kind != Airlift {
+		t.Fatalf("Expected the stored airlift to be fastest, got %v", fastest)
+	}
+}
+
+func TestPlanRoutesStations(t *testing.T) {
+	gs, player := routeTestState(&CityCard{CityName: "c"})
+	a, _ := gs.GetCity("a")
+	d, _ := gs.GetCity("d")
+	a.ResearchStation = true
+	d.ResearchStation = true
+
+	fastest, cheapest, err := gs.PlanRoutes(player, "a", "e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fastest.Actions != 2 || len(cheapest.Cards) != 0 || fastest.Moves[0].Kind != ShuttleFlight {
+		t.Fatalf("Expected to shuttle to d then drive, got %v", fastest)
+	}
+
+	player.Character = &Character{Type: OperationsExpert}
+	fastest, _, err = gs.PlanRoutes(player, "a", "e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fastest.Actions != 1 || fastest.Moves[0].Kind != StationFlight {
+		t.Fatalf("Expected the operations expert to fly straight from the station, got %v", fastest)
+	}
+}
+
+func TestStationCost(t *testing.T) {
+	gs, player := routeTestState(&CityCard{CityName: "b"})
+	if card, err := gs.StationCost(player, "b"); err != nil || card != "b" {
+		t.Fatalf("Expected building in b to cost the b card, got %v %v", card, err)
+	}
+	if _, err := gs.StationCost(player, "c"); err == nil {
+		t.Fatal("Should not be able to build in c without the c card")
+	}
+	player.Character = &Character{Type: OperationsExpert}
+	if card, err := gs.StationCost(player, "c"); err != nil || card != "" {
+		t.Fatalf("Expected the operations expert to build for free, got %v %v", card, err)
+	}
+}
+
+func TestBuildStationDiscards(t *testing.T) {
+	gs, player := routeTestState()
+	deck, err := gs.Cities.GenerateCityDeck(1, []*FundedEvent{}, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs.CityDeck = &deck
+	if err := gs.DrawCardTo("b", player); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.BuildStation(player, "c"); err == nil {
+		t.Fatal("Should not be able to build in c without the c card")
+	}
+	if err := gs.BuildStation(player, "b"); err != nil {
+		t.Fatal(err)
+	}
+	if player.HasCard("b") || len(deck.Discarded) != 1 {
+		t.Fatalf("Expected the b card to be discarded, got %v", player.Cards)
+	}
+	player.Character = &Character{Type: OperationsExpert}
+	if err := gs.BuildStation(player, "c"); err != nil {
+		t.Fatalf("Expected the operations expert to build without the c card, got %v", err)
+	}
+}
+
+func TestRouteCost(t *testing.T) {
+	gs, player := routeTestState()
+	fastest, _, err := gs.PlanRoutes(player, "a", "e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cost := gs.Cost(player, fastest)
+	if cost.Actions != 4 || len(cost.Steps) != 1 || cost.Steps[0].Count != 4 || cost.Turn != 1 {
+		t.Fatalf("Expected 4 drives done this turn, got %v", cost)
+	}
+	// one more action spills over to p1's next turn, after p2's
+	cost = gs.actionCost(player, "treat", "e")
+	if cost.Actions != 5 || cost.Turn != 3 {
+		t.Fatalf("Expected to treat e on turn 3, got %v", cost)
+	}
+	if s := cost.String(); s != "5 actions (4 drive, 1 treat), done on turn 3" {
+		t.Fatalf("Unexpected cost %q", s)
+	}
+	other := gs.GameTurns.PlayerOrder[1]
+	if turn := gs.CompletionTurn(other, 2); turn != 2 {
+		t.Fatalf("p2 plays next, expected turn 2, got %v", turn)
+	}
+	// an action already taken leaves only 3 for the drives this turn
+	gs.TakeAction("treat")
+	if turn := gs.CompletionTurn(player, 4); turn != 3 {
+		t.Fatalf("Expected 4 drives after an action to finish on turn 3, got %v", turn)
+	}
+}
+
+func TestPlanRoutesPanic(t *testing.T) {
+	gs, player := routeTestState(&CityCard{CityName: "d"}, &CityCard{CityName: "a"})
+	d, _ := gs.GetCity("d")
+	d.PanicLevel = Rioting2
+	fastest, _, err := gs.PlanRoutes(player, "a", "e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// charter flights still go from a, but not into rioting d
+	if fastest.Actions != 1 || fastest.Moves[0].Kind != CharterFlight {
+		t.Fatalf("Expected a charter flight to e, got %v", fastest)
+	}
+	fastest, _, err = gs.PlanRoutes(player, "a", "d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.CheckRoute("a", fastest); err != nil || fastest.Moves[len(fastest.Moves)-1].Kind != Drive {
+		t.Fatalf("Expected to drive into d, got %v (%v)", fastest, err)
+	}
+	a, _ := gs.GetCity("a")
+	a.PanicLevel = Collapsing
+	fastest, _, err = gs.PlanRoutes(player, "a", "e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fastest.Moves[0].Kind != Drive {
+		t.Fatalf("Expected to drive out of collapsing a, got %v", fastest)
+	}
+	if err := gs.CheckMove("a", Move{Kind: CharterFlight, To: "e", Card: "a"}); err == nil {
+		t.Fatal("Expected a charter flight out of collapsing a to be refused")
+	}
+}
+
+func TestResearchStations(t *testing.T) {
+	gs, _ := routeTestState()
+	*gs.Cities = append(*gs.Cities, &City{Name: "f"}, &City{Name: "g", PanicLevel: Rioting2})
+	if err := gs.BuildStation(nil, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.BuildStation(nil, "a"); err == nil {
+		t.Fatal("Should not build two stations in one city")
+	}
+	if err := gs.BuildStation(nil, "g"); err == nil {
+		t.Fatal("Should not build a station in a rioting city")
+	}
+	if err := gs.MoveStation("b", "c"); err == nil {
+		t.Fatal("Should not move a station from a city without one")
+	}
+	if err := gs.MoveStation("a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if stations := gs.Cities.ResearchStations(); len(stations) != 1 || stations[0] != "b" {
+		t.Fatalf("Expected the station to have moved to b, got %v", stations)
+	}
+	for _, city := range []CityName{"a", "c", "d", "e", "f"} {
+		if err := gs.BuildStation(nil, city); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if gs.ResearchStationsLeft() != 0 {
+		t.Fatalf("Expected all %v stations to be built, %v left", MaxResearchStations, gs.ResearchStationsLeft())
+	}
+	g, _ := gs.GetCity("g")
+	g.PanicLevel = Nothing
+	if err := gs.BuildStation(nil, "g"); err == nil {
+		t.Fatal("Should not build a seventh station")
+	}
+	if err := gs.MoveStation("a", "g"); err != nil {
+		t.Fatalf("Expected a station to be moved once all are built, got %v", err)
+	}
+}
+
+func TestMovePawn(t *testing.T) {
+	gs, player := routeTestState()
+	deck, err := gs.Cities.GenerateCityDeck(1, []*FundedEvent{}, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs.CityDeck = &deck
+	for _, card := range []CardName{"a", "d"} {
+		if err := gs.DrawCardTo(card, player); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := gs.MovePawn(player, Drive, "c"); err == nil {
+		t.Fatal("Should not be able to drive from a to c")
+	}
+	if _, err := gs.MovePawn(player, Drive, "b"); err != nil || player.Location != "b" {
+		t.Fatalf("Expected to drive to b, got %v in %v", err, player.Location)
+	}
+	if _, err := gs.MovePawn(player, CharterFlight, "e"); err == nil {
+		t.Fatal("A charter flight from b needs the b card")
+	}
+	move, err := gs.MovePawn(player, DirectFlight, "d")
+	if err != nil || player.Location != "d" || move.Card != "d" || player.HasCard("d") {
+		t.Fatalf("Expected a direct flight to d to spend the d card, got %v, %v", move, err)
+	}
+	if _, err := gs.MovePawn(player, ShuttleFlight, "a"); err == nil {
+		t.Fatal("Shuttle flights need research stations at both ends")
+	}
+	for _, name := range []CityName{"a", "d"} {
+		city, _ := gs.GetCity(name)
+		city.ResearchStation = true
+	}
+	if _, err := gs.MovePawn(player, ShuttleFlight, "a"); err != nil || player.Location != "a" {
+		t.Fatalf("Expected a shuttle flight back to a, got %v in %v", err, player.Location)
+	}
+}
+
+func TestShareKnowledge(t *testing.T) {
+	gs, giver := routeTestState(&CityCard{CityName: "a"}, &CityCard{CityName: "c"})
+	taker := gs.GameTurns.PlayerOrder[1]
+
+	if _, unplaced := gs.ShareKnowledge("a", giver, taker, false).(*SharingPositionError); !unplaced {
+		t.Fatal("Should not share a card with a player whose pawn isn't on the board")
+	}
+	taker.Location = "b"
+	if err := gs.ShareKnowledge("a", giver, taker, false); err == nil {
+		t.Fatal("Should not share a card with a player in another city")
+	}
+	taker.Location = "a"
+	if err := gs.ShareKnowledge("c", giver, taker, false); err == nil {
+		t.Fatal("Only the card of the city they are in can be shared")
+	}
+	if err := gs.ShareKnowledge("a", giver, taker, false); err != nil || giver.HasCard("a") || !taker.HasCard("a") {
+		t.Fatalf("Expected a to be given, got %v", err)
+	}
+
+	giver.Character = &Character{Type: Researcher}
+	if err := gs.ShareKnowledge("c", giver, taker, false); err != nil || !taker.HasCard("c") {
+		t.Fatalf("Expected the Researcher to give any city card, got %v", err)
+	}
+
+	// a table that doesn't track pawns can force the share
+	giver.Character = nil
+	taker.Location = ""
+	if err := gs.ShareKnowledge("c", taker, giver, true); err != nil || !giver.HasCard("c") {
+		t.Fatalf("Expected the forced share to hand c back, got %v", err)
+	}
+	if err := gs.ShareKnowledge("c", giver, giver, true); err == nil {
+		t.Fatal("Forcing a share should not let a player give a card to themselves")
+	}
+}
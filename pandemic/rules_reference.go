@@ -0,0 +1,83 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// A RuleEntry is one topic of the rules summary bundled in data/rules.json.
+// An entry only applies to games that have unlocked it: those played with
+// its ruleset or module, from its month of the campaign on, and with its
+// spoiler in the game's unlocks. Entries without any of these always apply.
+type RuleEntry struct {
+	Topic    string   `json:"topic"`
+	Keywords []string `json:"keywords,omitempty"`
+	Text     []string `json:"text"`
+	Ruleset  string   `json:"ruleset,omitempty"`
+	Module   string   `json:"module,omitempty"`
+	From     string   `json:"from,omitempty"`
+	Unlock   string   `json:"unlock,omitempty"`
+}
+
+func LoadRuleEntries(file string) ([]RuleEntry, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read the rules summary at %v: %v", file, err)
+	}
+	var entries []RuleEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("Invalid rules summary at %v: %v", file, err)
+	}
+	return entries, nil
+}
+
+// RulesInPlay keeps the entries that apply to this game. Games that aren't
+// named after a campaign month see every month's entries.
+func (gs GameState) RulesInPlay(entries []RuleEntry) []RuleEntry {
+	playing, _ := monthIndex(gs.GameName)
+	inPlay := []RuleEntry{}
+	for _, entry := range entries {
+		if entry.Ruleset != "" && entry.Ruleset != gs.rules().Name() {
+			continue
+		}
+		if entry.Module != "" && !gs.HasModule(entry.Module) {
+			continue
+		}
+		if from, _ := monthIndex(entry.From); entry.From != "" && playing < from {
+			continue
+		}
+		if entry.Unlock != "" && !gs.Unlocked(entry.Unlock) {
+			continue
+		}
+		inPlay = append(inPlay, entry)
+	}
+	return inPlay
+}
+
+// SearchRules finds the entries mentioning every word of the query in their
+// topic, keywords or text. Entries whose topic or keywords match come first.
+func SearchRules(entries []RuleEntry, query string) []RuleEntry {
+	words := strings.Fields(strings.ToLower(query))
+	titled, mentioned := []RuleEntry{}, []RuleEntry{}
+	for _, entry := range entries {
+		heading := strings.ToLower(entry.Topic + " " + strings.Join(entry.Keywords, " "))
+		body := strings.ToLower(strings.Join(entry.Text, " "))
+		inHeading, inBody := true, true
+		for _, word := range words {
+			if !strings.Contains(heading, word) {
+				inHeading = false
+			}
+			if !strings.Contains(heading, word) && !strings.Contains(body, word) {
+				inBody = false
+			}
+		}
+		if inHeading {
+			titled = append(titled, entry)
+		} else if inBody {
+			mentioned = append(mentioned, entry)
+		}
+	}
+	return append(titled, mentioned...)
+}
@@ -0,0 +1,42 @@
+package pandemic
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestRulesInPlay(t *testing.T) {
+	entries, err := LoadRuleEntries(filepath.Join("..", "data", "rules.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries = append(entries, RuleEntry{Topic: "Late rule", Text: []string{"Unlocked in March"}, From: "mar"})
+	gs := GameState{GameName: "february-attempt-1", Modules: []string{RailroadModuleName}}
+	topics := Set{}
+	for _, entry := range gs.RulesInPlay(entries) {
+		topics.Add(stringer(entry.Topic))
+	}
+	for _, topic := range []string{"Outbreaks", "Panic levels", "Railroads"} {
+		if !topics.Contains(stringer(topic)) {
+			t.Errorf("Expected %v to apply to a Season 1 railroad game, got %v", topic, topics.Members())
+		}
+	}
+	for _, topic := range []string{"Supply and plague cubes", "Purification", "Late rule", "Faded outbreaks"} {
+		if topics.Contains(stringer(topic)) {
+			t.Errorf("Expected %v not to apply yet", topic)
+		}
+	}
+	gs.GameName = "mar2"
+	if found := SearchRules(gs.RulesInPlay(entries), "late"); len(found) != 1 {
+		t.Fatalf("Expected the March rule in March, got %v", found)
+	}
+	gs.Unlocks = []string{FadedUnlock}
+	if found := SearchRules(gs.RulesInPlay(entries), "faded outbreak"); len(found) != 1 {
+		t.Fatalf("Expected the Faded's rules once unlocked, got %v", found)
+	}
+	// the topic match beats entries that only mention epidemics
+	found := SearchRules(entries, "double epidemic")
+	if len(found) == 0 || found[0].Topic != "Double epidemics" {
+		t.Fatalf("Expected the double epidemic rules first, got %+v", found)
+	}
+}
@@ -0,0 +1,113 @@
+package pandemic
+
+import (
+	"fmt"
+	"sort"
+)
+
+// A Ruleset captures what differs between seasons of Pandemic Legacy. The
+// city and infection decks are drawn the same way in every season, so the
+// probability engine doesn't depend on it: only setup, the limits checked
+// when correcting the game by hand, and what an infection does to a city.
+type Ruleset interface {
+	Name() string
+	Diseases() []DiseaseData
+	EpidemicsPerGame() int
+	InfectionRates() []int
+	InfectionRateTrack() []int
+	MaxOutbreaks() int
+	// QuarantineMarkers is how many cities can be quarantined at once.
+	QuarantineMarkers() int
+	// InfectCity places the given number of cubes on a city, returning true
+	// if that causes an outbreak.
+	InfectCity(gs GameState, city *City, cubes int) bool
+	// WinConditions are what a game of the ruleset has to do to be won,
+	// unless its new game file sets its own.
+	WinConditions() []WinCondition
+}
+
+const Season1Name = "season1"
+
+var rulesets = map[string]Ruleset{}
+
+func RegisterRuleset(rules Ruleset) {
+	rulesets[rules.Name()] = rules
+}
+
+func init() {
+	RegisterRuleset(Season1{})
+	RegisterRuleset(Season2{})
+}
+
+// GetRuleset finds a ruleset by name. Games saved before rulesets existed
+// have no name and were all Season 1 games.
+func GetRuleset(name string) (Ruleset, error) {
+	if name == "" {
+		name = Season1Name
+	}
+	rules, ok := rulesets[name]
+	if !ok {
+		return nil, fmt.Errorf("Unknown ruleset %q, expected one of %v", name, RulesetNames())
+	}
+	return rules, nil
+}
+
+func RulesetNames() []string {
+	names := []string{}
+	for name := range rulesets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Rules returns the ruleset the game was started with, or an error for a
+// save naming a ruleset this version doesn't know.
+func (gs GameState) Rules() (Ruleset, error) {
+	return GetRuleset(gs.Ruleset)
+}
+
+// rules is the game's ruleset for the engine's own use. A game with an
+// unknown ruleset can be read as a Season 1 game, but CheckPlaying refuses
+// to change it.
+func (gs GameState) rules() Ruleset {
+	if rules, err := gs.Rules(); err == nil {
+		return rules
+	}
+	return Season1{}
+}
+
+type Season1 struct{}
+
+func (Season1) Name() string { return Season1Name }
+
+func (Season1) Diseases() []DiseaseData {
+	return []DiseaseData{Yellow, Red, Black, Blue, Faded}
+}
+
+func (Season1) EpidemicsPerGame() int { return EpidemicsPerGame }
+
+func (Season1) InfectionRates() []int { return InfectionRates }
+
+func (Season1) InfectionRateTrack() []int { return InfectionRateTrack }
+
+func (Season1) MaxOutbreaks() int { return MaxOutbreaks }
+
+func (Season1) QuarantineMarkers() int { return MaxQuarantineMarkers }
+
+func (Season1) WinConditions() []WinCondition {
+	return []WinCondition{{Kind: "cures", Description: "Cure every curable disease"}}
+}
+
+// InfectCity raises the city's panic level by one when it outbreaks, up to
+// fallen.
+func (Season1) InfectCity(gs GameState, city *City, cubes int) bool {
+	outbreak := false
+	for i := 0; i < cubes; i++ {
+		outbreak = city.Infect() || outbreak
+	}
+	if outbreak && city.PanicLevel < Fallen {
+		city.PanicLevel++
+	}
+	return outbreak
+}
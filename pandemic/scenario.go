@@ -0,0 +1,206 @@
+package pandemic
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// A Scenario is a rule test written as a short script instead of Go, so
+// that anyone can describe how the rules should play out, e.g. to attach
+// to a bug report. Each line is one of:
+//
+//	# a comment
+//	name <what the scenario checks>
+//	game <new game file, relative to the scenario>
+//	cubes <city> <n>
+//	rate <n>
+//	outbreaks <n>
+//	<command> <args...>
+//	expect <assertion> <args...>
+//
+// The game line comes first. cubes, rate and outbreaks set the game up
+// without drawing any cards. Commands are the ones a fixture can replay,
+// e.g. infect, epidemic, city-draw and next-turn, taking full city names.
+// A command that fails stops the scenario unless the next line is an
+// expect error, which checks the message contains the given text.
+type Scenario struct {
+	Name  string
+	Path  string
+	lines []scenarioLine
+}
+
+type scenarioLine struct {
+	number int
+	words  []string
+}
+
+func LoadScenario(path string) (*Scenario, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scenario := &Scenario{Name: filepath.Base(path), Path: path}
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words := strings.Fields(line)
+		if words[0] == "name" {
+			scenario.Name = strings.TrimSpace(strings.TrimPrefix(line, "name"))
+			continue
+		}
+		scenario.lines = append(scenario.lines, scenarioLine{number: number, words: words})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(scenario.lines) == 0 || scenario.lines[0].words[0] != "game" {
+		return nil, fmt.Errorf("%v: A scenario must start with a game line", path)
+	}
+	return scenario, nil
+}
+
+// Run plays the scenario and reports the first line that fails.
+func (s *Scenario) Run() error {
+	var gs *GameState
+	var failed error
+	for i, line := range s.lines {
+		if failed != nil && line.words[0] != "expect" {
+			return s.lineError(s.lines[i-1], failed)
+		}
+		var err error
+		switch line.words[0] {
+		case "game":
+			gs, err = s.startGame(line.words[1:])
+		case "cubes", "rate", "outbreaks":
+			err = gs.setUpScenario(line.words)
+		case "expect":
+			err = gs.checkScenario(line.words[1:], failed)
+			failed = nil
+		default:
+			failed = gs.applyFixtureStep(FixtureStep{Command: line.words[0], Args: line.words[1:]})
+			if failed != nil {
+				continue
+			}
+		}
+		if err != nil {
+			return s.lineError(line, err)
+		}
+	}
+	if failed != nil {
+		return s.lineError(s.lines[len(s.lines)-1], failed)
+	}
+	return nil
+}
+
+func (s *Scenario) lineError(line scenarioLine, err error) error {
+	return fmt.Errorf("%v line %v (%v): %v", s.Path, line.number, strings.Join(line.words, " "), err)
+}
+
+func (s *Scenario) startGame(args []string) (*GameState, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("Usage: game <new game file>")
+	}
+	return NewGame(filepath.Join(filepath.Dir(s.Path), args[0]), s.Name)
+}
+
+func (gs *GameState) setUpScenario(words []string) error {
+	n, err := strconv.Atoi(words[len(words)-1])
+	if err != nil {
+		return err
+	}
+	switch {
+	case words[0] == "cubes" && len(words) == 3:
+		city, err := gs.GetCity(CityName(words[1]))
+		if err != nil {
+			return err
+		}
+		city.SetInfections(n)
+	case words[0] == "rate" && len(words) == 2:
+		gs.InfectionRate = n
+	case words[0] == "outbreaks" && len(words) == 2:
+		gs.Outbreaks = n
+	default:
+		return fmt.Errorf("Usage: cubes <city> <n>, rate <n> or outbreaks <n>")
+	}
+	return nil
+}
+
+// checkScenario checks one expect line. failed is the error of the command
+// before it, if it failed.
+func (gs *GameState) checkScenario(args []string, failed error) error {
+	if len(args) == 0 {
+		return fmt.Errorf("Nothing to expect")
+	}
+	if args[0] == "error" {
+		want := strings.Join(args[1:], " ")
+		if failed == nil {
+			return fmt.Errorf("Expected the command to fail with %q", want)
+		}
+		if !strings.Contains(failed.Error(), want) {
+			return fmt.Errorf("Expected an error containing %q, got %q", want, failed)
+		}
+		return nil
+	}
+	if failed != nil {
+		return failed
+	}
+	switch {
+	case args[0] == "cubes" && len(args) == 3:
+		city, err := gs.GetCity(CityName(args[1]))
+		if err != nil {
+			return err
+		}
+		return expectCount("cubes in "+args[1], args[2], city.NumInfections)
+	case args[0] == "rate" && len(args) == 2:
+		return expectCount("infection rate", args[1], gs.InfectionRate)
+	case args[0] == "outbreaks" && len(args) == 2:
+		return expectCount("outbreaks", args[1], gs.Outbreaks)
+	case args[0] == "epidemics" && len(args) == 2:
+		return expectCount("epidemics drawn", args[1], gs.CityDeck.EpidemicsDrawn())
+	case args[0] == "discarded" && len(args) == 2:
+		if !gs.InfectionDeck.Drawn.Contains(CityName(args[1])) {
+			return fmt.Errorf("Expected %v in the infection discard pile", args[1])
+		}
+	case args[0] == "quarantined" && len(args) == 2:
+		city, err := gs.GetCity(CityName(args[1]))
+		if err != nil {
+			return err
+		}
+		if !city.Quarantined {
+			return fmt.Errorf("Expected %v to be quarantined", args[1])
+		}
+	case args[0] == "top" && len(args) == 2:
+		if p := gs.InfectionDeck.ProbabilityOfDrawing(CityName(args[1]), 1); p != 1.0 {
+			return fmt.Errorf("Expected %v to be the next infection card, it has a %.3f chance", args[1], p)
+		}
+	case args[0] == "event" && len(args) == 3:
+		for _, event := range gs.Log.Events {
+			if event.Kind == EventKind(args[1]) && event.City == CityName(args[2]) {
+				return nil
+			}
+		}
+		return fmt.Errorf("Expected a %v event for %v", args[1], args[2])
+	default:
+		return fmt.Errorf("Unknown expectation %v", strings.Join(args, " "))
+	}
+	return nil
+}
+
+func expectCount(what, want string, got int) error {
+	n, err := strconv.Atoi(want)
+	if err != nil {
+		return err
+	}
+	if n != got {
+		return fmt.Errorf("Expected %v %v, got %v", n, what, got)
+	}
+	return nil
+}
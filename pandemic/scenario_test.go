@@ -0,0 +1,27 @@
+package pandemic
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestScenarios runs every scenario in testdata/scenarios. See Scenario for
+// the format.
+func TestScenarios(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "scenarios", "*.scenario"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("Expected at least one scenario")
+	}
+	for _, path := range paths {
+		scenario, err := LoadScenario(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := scenario.Run(); err != nil {
+			t.Errorf("%v: %v", scenario.Name, err)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package pandemic
+
+import (
+	"fmt"
+)
+
+const Season2Name = "season2"
+
+// Season 2 has no cures: the plague is tracked with a single cube color,
+// while the city colors only matter for the player cards.
+var Plague = DiseaseData{
+	Type:        DiseaseType("Plague"),
+	Incurable:   true,
+	Untreatable: true,
+}
+
+const MaxSupplyCubes = 3
+
+// Season2 plays on a map that is explored over the campaign. Cities hold
+// supply cubes which are used up before any plague cubes are placed, and
+// the game is lost when the plague cube supply runs out.
+type Season2 struct{}
+
+func (Season2) Name() string { return Season2Name }
+
+func (Season2) Diseases() []DiseaseData {
+	return []DiseaseData{Yellow, Blue, Black, Plague}
+}
+
+func (Season2) EpidemicsPerGame() int { return EpidemicsPerGame }
+
+func (Season2) InfectionRates() []int { return []int{2, 3, 4, 5} }
+
+func (Season2) InfectionRateTrack() []int { return []int{2, 2, 2, 3, 3, 4, 4, 5} }
+
+// MaxOutbreaks is the number of incidents that lose the game.
+func (Season2) MaxOutbreaks() int { return MaxOutbreaks }
+
+func (Season2) QuarantineMarkers() int { return MaxQuarantineMarkers }
+
+// WinConditions are left to each month's new game file, since every month
+// of Season 2 has its own objectives.
+func (Season2) WinConditions() []WinCondition { return nil }
+
+func (Season2) InfectCity(gs GameState, city *City, cubes int) bool {
+	for ; cubes > 0 && city.SupplyCubes > 0; cubes-- {
+		city.SupplyCubes--
+	}
+	outbreak := false
+	for i := 0; i < cubes; i++ {
+		if city.Infect() {
+			outbreak = true
+		} else if gs.Supply != nil {
+			gs.Supply.PlagueCubes--
+		}
+	}
+	return outbreak
+}
+
+// Supply is the pool of Season 2 cubes that haven't been placed on the map.
+type Supply struct {
+	SupplyCubes int `json:"supply_cubes"`
+	PlagueCubes int `json:"plague_cubes"`
+}
+
+func (gs GameState) season2() error {
+	if gs.Ruleset != Season2Name || gs.Supply == nil {
+		return fmt.Errorf("Supply cubes, havens and exploration are only part of Season 2 games")
+	}
+	return nil
+}
+
+// PlaceSupplies moves supply cubes from the supply onto a city.
+func (gs GameState) PlaceSupplies(cn CityName, cubes int) error {
+	if err := gs.season2(); err != nil {
+		return err
+	}
+	city, err := gs.Cities.GetCity(cn)
+	if err != nil {
+		return err
+	}
+	if city.Unexplored {
+		return fmt.Errorf("%v has not been explored yet", cn)
+	}
+	if cubes > gs.Supply.SupplyCubes {
+		return fmt.Errorf("Only %v supply cubes are left", gs.Supply.SupplyCubes)
+	}
+	if city.SupplyCubes+cubes > MaxSupplyCubes {
+		return fmt.Errorf("%v can hold at most %v supply cubes", cn, MaxSupplyCubes)
+	}
+	gs.Supply.SupplyCubes -= cubes
+	city.SupplyCubes += cubes
+	gs.changed("%v supply cubes placed in %v", cubes, cn)
+	return nil
+}
+
+func (gs GameState) BuildHaven(cn CityName) error {
+	if err := gs.season2(); err != nil {
+		return err
+	}
+	city, err := gs.Cities.GetCity(cn)
+	if err != nil {
+		return err
+	}
+	if city.Unexplored {
+		return fmt.Errorf("%v has not been explored yet", cn)
+	}
+	if city.Haven {
+		return fmt.Errorf("%v already has a haven", cn)
+	}
+	city.Haven = true
+	gs.changed("haven built in %v", cn)
+	return nil
+}
+
+// Explore puts a city on the map. From then on supplies, havens, stations
+// and quarantines can be placed there, and the city stays explored for the
+// rest of the campaign. Its cards join the city and infection decks from
+// the next game on, since the decks of this one are already built.
+func (gs GameState) Explore(cn CityName) error {
+	if err := gs.season2(); err != nil {
+		return err
+	}
+	city, err := gs.Cities.GetCity(cn)
+	if err != nil {
+		return err
+	}
+	if !city.Unexplored {
+		return fmt.Errorf("%v has already been explored", cn)
+	}
+	city.Unexplored = false
+	gs.recordCity(CityExplored, cn, "%v was explored", cn)
+	return nil
+}
@@ -0,0 +1,101 @@
+package pandemic
+
+import (
+	"testing"
+)
+
+func TestGetRuleset(t *testing.T) {
+	rules, err := GetRuleset("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules.Name() != Season1Name {
+		t.Fatalf("Games without a ruleset should be Season 1 games, got %v", rules.Name())
+	}
+	if _, err := GetRuleset("season3"); err == nil {
+		t.Fatal("Should not find a ruleset for season 3")
+	}
+}
+
+func TestSeason2Infection(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", SupplyCubes: 2},
+		{Name: "b", Unexplored: true},
+	})
+	gs := &GameState{
+		Cities:  &cities,
+		Ruleset: Season2Name,
+		Supply:  &Supply{SupplyCubes: 4, PlagueCubes: 10},
+	}
+	if explored := cities.Explored(); len(explored) != 1 || explored[0].Name != "a" {
+		t.Fatalf("Only a should be explored, got %v", explored.CityNames())
+	}
+
+	a, _ := cities.GetCity("a")
+	rules, err := gs.Rules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules.InfectCity(*gs, a, 3) {
+		t.Fatal("Should not outbreak a city with supply cubes")
+	}
+	if a.SupplyCubes != 0 || a.NumInfections != 1 || gs.Supply.PlagueCubes != 9 {
+		t.Fatalf("Expected supplies to absorb 2 of the 3 cubes, got %+v and %+v", a, gs.Supply)
+	}
+
+	if err := gs.PlaceSupplies("b", 1); err == nil {
+		t.Fatal("Should not be able to supply an unexplored city")
+	}
+	if err := gs.PlaceSupplies("a", 5); err == nil {
+		t.Fatal("Should not be able to place more supply cubes than are left")
+	}
+	if err := gs.PlaceSupplies("a", 3); err != nil {
+		t.Fatal(err)
+	}
+	if a.SupplyCubes != 3 || gs.Supply.SupplyCubes != 1 {
+		t.Fatalf("Expected 3 supply cubes in a and 1 left, got %+v and %+v", a, gs.Supply)
+	}
+	if err := gs.BuildHaven("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.BuildHaven("a"); err == nil {
+		t.Fatal("Should not be able to build a second haven in a")
+	}
+}
+
+func TestSeason2Exploration(t *testing.T) {
+	cities := Cities([]*City{{Name: "a"}, {Name: "b", Unexplored: true}})
+	gs := &GameState{
+		Cities:  &cities,
+		Ruleset: Season2Name,
+		Supply:  &Supply{SupplyCubes: 4, PlagueCubes: 10},
+		Log:     &EventLog{},
+	}
+	if err := gs.Explore("a"); err == nil {
+		t.Fatal("Should not explore a city twice")
+	}
+	if err := gs.Explore("b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.PlaceSupplies("b", 1); err != nil {
+		t.Fatalf("Expected an explored city to take supplies: %v", err)
+	}
+	template := NewGameSettings{Cities: Cities{{Name: "a"}, {Name: "b", Unexplored: true}}}
+	next, err := gs.NextGameSettings(template, MonthTransition{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := next.Cities.GetCity("b"); b.Unexplored {
+		t.Fatal("Expected b to stay explored in the next game")
+	}
+}
+
+func TestUnknownRuleset(t *testing.T) {
+	gs := GameState{Ruleset: "season3"}
+	if _, err := gs.Rules(); err == nil {
+		t.Fatal("Expected an error for an unknown ruleset")
+	}
+	if err := gs.CheckPlaying(); err == nil {
+		t.Fatal("Expected a game of an unknown ruleset not to be played on")
+	}
+}
@@ -0,0 +1,118 @@
+package pandemic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InitialInfectionCubes are the cubes placed on each of the cities drawn
+// for the initial infection, in the order they are drawn.
+var InitialInfectionCubes = []int{3, 3, 3, 2, 2, 2, 1, 1, 1}
+
+// StartingHandSize is how many cards each player is dealt before the
+// epidemics are shuffled in: 4 each for 2 players, 3 for 3 and 2 for 4.
+func StartingHandSize(players int) int {
+	return 6 - players
+}
+
+// InitialInfection draws the cities infected before the first turn, in
+// order, placing 3 cubes on the first three, 2 on the next three and 1 on
+// the last three.
+func (gs *GameState) InitialInfection(cities []CityName) error {
+	if len(cities) != len(InitialInfectionCubes) {
+		return fmt.Errorf("The initial infection draws %v cities, got %v", len(InitialInfectionCubes), len(cities))
+	}
+	if gs.InfectionDeck.Drawn.Size() > 0 {
+		return fmt.Errorf("The initial infection was already drawn")
+	}
+	seen := Set{}
+	for _, cn := range cities {
+		if seen.Contains(cn) {
+			return fmt.Errorf("%v was drawn twice", cn)
+		}
+		seen.Add(cn)
+		if _, err := gs.GetCity(cn); err != nil {
+			return err
+		}
+	}
+	for i, cn := range cities {
+		if err := gs.InfectionDeck.Draw(cn); err != nil {
+			return err
+		}
+		city, _ := gs.GetCity(cn)
+		gs.infectCity(city, InitialInfectionCubes[i])
+	}
+	gs.record(ManualOverride, "Drew the initial infection")
+	return nil
+}
+
+// DealStartingHand records the cards the player was dealt. Hands can only
+// be dealt before anyone draws, since the epidemics are shuffled into the
+// piles left after dealing: the odds of an epidemic are worked out again
+// from the cards left.
+func (gs GameState) DealStartingHand(player *Player, cards []CardName) error {
+	deck := gs.CityDeck
+	if len(deck.Drawn) != len(deck.StartCities) || deck.UnknownDraws > 0 {
+		return fmt.Errorf("Cards were already drawn, it's too late to deal starting hands")
+	}
+	if len(player.Cards) > 0 {
+		return fmt.Errorf("%v was already dealt a starting hand", player.HumanName)
+	}
+	if size := StartingHandSize(len(gs.GameTurns.PlayerOrder)); len(cards) != size {
+		return fmt.Errorf("Each of %v players is dealt %v cards, got %v", len(gs.GameTurns.PlayerOrder), size, len(cards))
+	}
+	dealt := []*CityCard{}
+	for _, name := range cards {
+		card, ok := deck.copyIn(name, InDeck)
+		if !ok || card.IsEpidemic {
+			return fmt.Errorf("%v can't be dealt, it is in the %v", name, deck.Location(name))
+		}
+		deck.Drawn = append(deck.Drawn, *card)
+		deck.StartCities = append(deck.StartCities, *card)
+		dealt = append(dealt, card)
+	}
+	player.Cards = dealt
+	player.StartCards = cards
+	model := generateProbabilityModel(len(deck.All)-len(deck.StartCities), deck.NumEpidemics())
+	deck.ProbabilityModel = &model
+	names := []string{}
+	for _, name := range cards {
+		names = append(names, string(name))
+	}
+	gs.record(ManualOverride, "Dealt %v %v", player.HumanName, strings.Join(names, ", "))
+	return nil
+}
+
+// EpidemicPiles is the size of the piles the epidemics are shuffled into,
+// each with one epidemic: the cards left after dealing split as evenly as
+// they go.
+func (gs GameState) EpidemicPiles() []int {
+	epidemics := gs.CityDeck.NumEpidemics()
+	if epidemics == 0 {
+		return nil
+	}
+	cards := len(gs.CityDeck.All) - len(gs.CityDeck.StartCities)
+	piles := []int{}
+	for i := 0; i < epidemics; i++ {
+		size := cards / epidemics
+		if i < cards%epidemics {
+			size++
+		}
+		piles = append(piles, size)
+	}
+	return piles
+}
+
+// SetupLeft lists what is still to be done before the first turn.
+func (gs GameState) SetupLeft() []string {
+	left := []string{}
+	if gs.InfectionDeck.Drawn.Size() == 0 {
+		left = append(left, fmt.Sprintf("draw the initial infection of %v cities", len(InitialInfectionCubes)))
+	}
+	for _, player := range gs.GameTurns.PlayerOrder {
+		if len(player.Cards) == 0 && len(player.StartCards) == 0 {
+			left = append(left, fmt.Sprintf("deal %v's starting hand", player.HumanName))
+		}
+	}
+	return left
+}
@@ -0,0 +1,67 @@
+package pandemic
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetup(t *testing.T) {
+	cities := Cities{}
+	for _, name := range []CityName{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		cities = append(cities, &City{Name: name, Disease: Yellow.Type})
+	}
+	deck, err := cities.GenerateCityDeck(2, []*FundedEvent{}, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, second := &Player{HumanName: "p1"}, &Player{HumanName: "p2"}
+	gs := &GameState{
+		Cities:        &cities,
+		CityDeck:      &deck,
+		InfectionDeck: NewInfectionDeck(cities.CityNames()),
+		DiseaseData:   []DiseaseData{Yellow},
+		GameTurns:     InitGameTurns(first, second),
+	}
+	if left := gs.SetupLeft(); len(left) != 3 {
+		t.Fatalf("Expected the infection and two hands left to set up, got %v", left)
+	}
+
+	if err := gs.InitialInfection([]CityName{"a", "b", "c"}); err == nil {
+		t.Fatal("The initial infection should need 9 cities")
+	}
+	drawn := []CityName{"a", "b", "c", "d", "e", "f", "g", "h", "i"}
+	if err := gs.InitialInfection(drawn); err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range drawn {
+		if city, _ := gs.GetCity(name); city.NumInfections != InitialInfectionCubes[i] {
+			t.Fatalf("Expected %v to have %v cubes, got %v", name, InitialInfectionCubes[i], city.NumInfections)
+		}
+	}
+	if err := gs.InitialInfection(drawn); err == nil {
+		t.Fatal("The initial infection should only be drawn once")
+	}
+
+	if err := gs.DealStartingHand(first, []CardName{"a", "b"}); err == nil {
+		t.Fatal("Each of 2 players should be dealt 4 cards")
+	}
+	if err := gs.DealStartingHand(first, []CardName{"a", "b", "c", "d"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.DealStartingHand(second, []CardName{"d", "e", "f", "g"}); err == nil {
+		t.Fatal("d was already dealt")
+	}
+	if err := gs.DealStartingHand(second, []CardName{"e", "f", "g", "h"}); err != nil {
+		t.Fatal(err)
+	}
+	// 12 cities and 2 epidemics less the 8 cards dealt
+	if piles := gs.EpidemicPiles(); !reflect.DeepEqual(piles, []int{3, 3}) {
+		t.Fatalf("Expected two piles of 3, got %v", piles)
+	}
+	if p := deck.EpidemicAnalysis().FirstCardProbability; p < 0.33 || p > 0.34 {
+		t.Fatalf("Expected a 1 in 3 chance of an epidemic on the first card, got %v", p)
+	}
+	if left := gs.SetupLeft(); len(left) != 0 {
+		t.Fatalf("Expected setup to be done, got %v", left)
+	}
+}
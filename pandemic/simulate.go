@@ -0,0 +1,152 @@
+package pandemic
+
+import (
+	"encoding/json"
+	"math/rand"
+)
+
+// Clone makes a deep copy of the game that can be changed freely, e.g. to
+// try out a plan or simulate what might happen next.
+func (gs GameState) Clone() (*GameState, error) {
+	data, err := json.Marshal(gs)
+	if err != nil {
+		return nil, err
+	}
+	var clone GameState
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, err
+	}
+	if clone.Log == nil {
+		clone.Log = &EventLog{}
+	}
+	return &clone, nil
+}
+
+// InfectOutcomes summarizes many simulated infection phases.
+type InfectOutcomes struct {
+	Trials int
+	// Outbreaks counts the trials by how many outbreaks happened in them.
+	Outbreaks map[int]int
+	Cubes     int // placed across all trials
+	// CampaignCost adds up the panic, fallen cities and scars of every
+	// outbreak across all trials.
+	CampaignCost int
+	// CityOutbreaks counts the trials each city outbroke in.
+	CityOutbreaks map[CityName]int
+	// CityCubes adds up, across all trials, the cubes placed on the board
+	// by drawing each city's card: its own cube, or those its outbreak
+	// spilled onto its neighbors.
+	CityCubes map[CityName]int
+}
+
+func (o InfectOutcomes) ExpectedOutbreaks() float64 {
+	var total int
+	for outbreaks, trials := range o.Outbreaks {
+		total += outbreaks * trials
+	}
+	return float64(total) / float64(o.Trials)
+}
+
+func (o InfectOutcomes) ExpectedCubes() float64 {
+	return float64(o.Cubes) / float64(o.Trials)
+}
+
+func (o InfectOutcomes) ExpectedCampaignCost() float64 {
+	return float64(o.CampaignCost) / float64(o.Trials)
+}
+
+// ProbabilityOfOutbreak is the chance the city outbreaks in the infection
+// phase, whether drawn or set off by a neighbor.
+func (o InfectOutcomes) ProbabilityOfOutbreak(cn CityName) float64 {
+	return float64(o.CityOutbreaks[cn]) / float64(o.Trials)
+}
+
+// ExpectedCubesFrom is the number of cubes the city's infection card is
+// expected to add to the board in the infection phase. A likely draw of an
+// empty city can matter less than an unlikely one that sets off an outbreak.
+func (o InfectOutcomes) ExpectedCubesFrom(cn CityName) float64 {
+	return float64(o.CityCubes[cn]) / float64(o.Trials)
+}
+
+// RiskiestCity is the city that outbroke in the most trials, if any did.
+func (o InfectOutcomes) RiskiestCity() (CityName, bool) {
+	var riskiest CityName
+	most := 0
+	for city, trials := range o.CityOutbreaks {
+		if trials > most || (trials == most && city < riskiest) {
+			riskiest, most = city, trials
+		}
+	}
+	return riskiest, most > 0
+}
+
+func (o InfectOutcomes) ProbabilityOfNoOutbreaks() float64 {
+	return float64(o.Outbreaks[0]) / float64(o.Trials)
+}
+
+func (o InfectOutcomes) WorstOutbreaks() int {
+	worst := 0
+	for outbreaks := range o.Outbreaks {
+		if outbreaks > worst {
+			worst = outbreaks
+		}
+	}
+	return worst
+}
+
+// SimulateInfectPhase plays the next infection phase many times on copies
+// of the game, drawing each card at random from the top striation, with
+// weakly shuffled striations favouring some cards.
+func (gs GameState) SimulateInfectPhase(trials int, rng *rand.Rand) (InfectOutcomes, error) {
+	outcomes := InfectOutcomes{Trials: trials, Outbreaks: map[int]int{}, CityOutbreaks: map[CityName]int{}, CityCubes: map[CityName]int{}}
+	for i := 0; i < trials; i++ {
+		sandbox, err := gs.Clone()
+		if err != nil {
+			return outcomes, err
+		}
+		before := sandbox.cubesOnBoard()
+		outbreaks := 0
+		outbroke := map[CityName]bool{}
+		for draw := 0; draw < sandbox.InfectionsThisTurn() && sandbox.InfectionDeck.Size() > 0; draw++ {
+			sandbox.InfectionDeck.dropEmptyStriations()
+			top := sandbox.InfectionDeck.CitiesInStriation(0)
+			drawn := sandbox.InfectionDeck.pick(top, rng)
+			placed := sandbox.cubesOnBoard()
+			panicked := sandbox.panicLevels()
+			chain, err := sandbox.infect(drawn)
+			if err != nil {
+				return outcomes, err
+			}
+			outcomes.CityCubes[drawn] += sandbox.cubesOnBoard() - placed
+			for _, city := range chain.Cities() {
+				outbreaks++
+				outcomes.CampaignCost += sandbox.campaignCost(city, panicked[city])
+				outbroke[city] = true
+			}
+		}
+		outcomes.Outbreaks[outbreaks]++
+		for city := range outbroke {
+			outcomes.CityOutbreaks[city]++
+		}
+		outcomes.Cubes += sandbox.cubesOnBoard() - before
+	}
+	return outcomes, nil
+}
+
+// panicLevels is each city's panic level, to price outbreaks by the panic
+// they had before the draw that set them off.
+func (gs GameState) panicLevels() map[CityName]PanicLevel {
+	levels := map[CityName]PanicLevel{}
+	for _, city := range *gs.Cities {
+		levels[city.Name] = city.PanicLevel
+	}
+	return levels
+}
+
+func (gs GameState) cubesOnBoard() int {
+	var cubes int
+	for _, city := range *gs.Cities {
+		cubes += city.NumInfections
+	}
+	return cubes
+}
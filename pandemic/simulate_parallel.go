@@ -0,0 +1,125 @@
+package pandemic
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+// simulationBatch is how many trials a worker runs before reporting back.
+const simulationBatch = 100
+
+// minTrialsBeforeStopping keeps a simulation from stopping early on a run
+// of luck in the first few batches.
+const minTrialsBeforeStopping = 1000
+
+// SimulateOptions tune SimulateInfectPhaseParallel.
+type SimulateOptions struct {
+	Trials int
+	// Workers defaults to one per CPU.
+	Workers int
+	Seed    int64
+	// Tolerance stops the simulation early once the 95% confidence interval
+	// of the expected outbreaks is within this either side. 0 runs every
+	// trial.
+	Tolerance float64
+	// Progress, if set, is called from the simulation's goroutine as each
+	// batch of trials finishes.
+	Progress func(done, total int)
+}
+
+// SimulateInfectPhaseParallel is SimulateInfectPhase spread over several
+// goroutines. The game must not change while it runs, so simulate a clone
+// if commands may still be entered.
+func (gs GameState) SimulateInfectPhaseParallel(opts SimulateOptions) (InfectOutcomes, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	jobs := make(chan int, opts.Trials/simulationBatch+1)
+	for left := opts.Trials; left > 0; left -= simulationBatch {
+		jobs <- int(math.Min(float64(left), simulationBatch))
+	}
+	close(jobs)
+
+	type batch struct {
+		outcomes InfectOutcomes
+		err      error
+	}
+	results := make(chan batch)
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(rng *rand.Rand) {
+			defer wg.Done()
+			for trials := range jobs {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				outcomes, err := gs.SimulateInfectPhase(trials, rng)
+				results <- batch{outcomes, err}
+			}
+		}(rand.New(rand.NewSource(opts.Seed + int64(w))))
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	total := InfectOutcomes{Outbreaks: map[int]int{}, CityOutbreaks: map[CityName]int{}, CityCubes: map[CityName]int{}}
+	var failed error
+	stopped := false
+	for result := range results {
+		if result.err != nil {
+			failed = result.err
+		} else {
+			total.add(result.outcomes)
+		}
+		if opts.Progress != nil {
+			opts.Progress(total.Trials, opts.Trials)
+		}
+		if !stopped && (failed != nil || total.confident(opts.Tolerance)) {
+			stopped = true
+			close(stop)
+		}
+	}
+	return total, failed
+}
+
+func (o *InfectOutcomes) add(other InfectOutcomes) {
+	o.Trials += other.Trials
+	for outbreaks, trials := range other.Outbreaks {
+		o.Outbreaks[outbreaks] += trials
+	}
+	o.Cubes += other.Cubes
+	o.CampaignCost += other.CampaignCost
+	for city, trials := range other.CityOutbreaks {
+		o.CityOutbreaks[city] += trials
+	}
+	for city, cubes := range other.CityCubes {
+		o.CityCubes[city] += cubes
+	}
+}
+
+// ConfidenceInterval is how far either side of the expected outbreaks the
+// true answer is likely to be, with 95% confidence.
+func (o InfectOutcomes) ConfidenceInterval() float64 {
+	if o.Trials == 0 {
+		return math.Inf(1)
+	}
+	mean := o.ExpectedOutbreaks()
+	var squares float64
+	for outbreaks, trials := range o.Outbreaks {
+		squares += float64(outbreaks*outbreaks) * float64(trials)
+	}
+	variance := squares/float64(o.Trials) - mean*mean
+	return 1.96 * math.Sqrt(math.Max(variance, 0)/float64(o.Trials))
+}
+
+func (o InfectOutcomes) confident(tolerance float64) bool {
+	return tolerance > 0 && o.Trials >= minTrialsBeforeStopping && o.ConfidenceInterval() <= tolerance
+}
@@ -0,0 +1,175 @@
+package pandemic
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSimulateInfectPhase(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", NumInfections: 3},
+		{Name: "b"},
+		{Name: "c"},
+		{Name: "d"},
+	})
+	gs := GameState{
+		Cities:        &cities,
+		InfectionDeck: NewInfectionDeck(cities.CityNames()),
+		InfectionRate: 2,
+		GameTurns:     InitGameTurns(&Player{HumanName: "Will"}),
+	}
+	outcomes, err := gs.SimulateInfectPhase(2000, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a is drawn in half of all infection phases: 1 - (3/4 * 2/3)
+	if p := outcomes.ProbabilityOfNoOutbreaks(); p < 0.45 || p > 0.55 {
+		t.Fatalf("Expected no outbreak about half the time, got %v", p)
+	}
+	if outcomes.WorstOutbreaks() != 1 {
+		t.Fatalf("Expected at most one outbreak, got %v", outcomes.Outbreaks)
+	}
+	if city, ok := outcomes.RiskiestCity(); !ok || city != "a" {
+		t.Fatalf("Expected a to be the riskiest city, got %v", city)
+	}
+	if p := outcomes.ProbabilityOfOutbreak("a"); p < 0.45 || p > 0.55 {
+		t.Fatalf("Expected a to outbreak about half the time, got %v", p)
+	}
+	if a, _ := cities.GetCity("a"); a.NumInfections != 3 || gs.InfectionDeck.Size() != 4 {
+		t.Fatal("Simulating should not change the game")
+	}
+}
+
+func TestSimulateCubesPerCity(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", NumInfections: 3, Neighbors: []string{"b", "c"}},
+		{Name: "b", Neighbors: []string{"a"}},
+		{Name: "c", Neighbors: []string{"a"}},
+		{Name: "d"},
+	})
+	gs := GameState{
+		Cities:           &cities,
+		InfectionDeck:    NewInfectionDeck(cities.CityNames()),
+		InfectionRate:    2,
+		GameTurns:        InitGameTurns(&Player{HumanName: "Will"}),
+		ResolveOutbreaks: true,
+	}
+	outcomes, err := gs.SimulateInfectPhase(2000, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every card is drawn half the time, and a's outbreak puts 2 cubes out
+	if cubes := outcomes.ExpectedCubesFrom("a"); cubes < 0.9 || cubes > 1.1 {
+		t.Fatalf("Expected a's outbreak to add about one cube, got %v", cubes)
+	}
+	if cubes := outcomes.ExpectedCubesFrom("d"); cubes < 0.45 || cubes > 0.55 {
+		t.Fatalf("Expected d to add about half a cube, got %v", cubes)
+	}
+}
+
+func TestSimulateInfectPhaseParallel(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", NumInfections: 3},
+		{Name: "b"},
+		{Name: "c"},
+		{Name: "d"},
+	})
+	gs := GameState{
+		Cities:        &cities,
+		InfectionDeck: NewInfectionDeck(cities.CityNames()),
+		InfectionRate: 2,
+		GameTurns:     InitGameTurns(&Player{HumanName: "Will"}),
+	}
+	progress := 0
+	outcomes, err := gs.SimulateInfectPhaseParallel(SimulateOptions{Trials: 2050, Workers: 4, Seed: 1, Progress: func(done, total int) {
+		progress = done
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outcomes.Trials != 2050 || progress != 2050 {
+		t.Fatalf("Expected every trial to run and be reported, got %v and %v", outcomes.Trials, progress)
+	}
+	if p := outcomes.ProbabilityOfNoOutbreaks(); p < 0.45 || p > 0.55 {
+		t.Fatalf("Expected no outbreak about half the time, got %v", p)
+	}
+
+	// a coin flip's 95% interval is within 0.05 after about 400 trials
+	outcomes, err = gs.SimulateInfectPhaseParallel(SimulateOptions{Trials: 100000, Workers: 4, Seed: 1, Tolerance: 0.05})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outcomes.Trials >= 100000 || outcomes.ConfidenceInterval() > 0.05 {
+		t.Fatalf("Expected the simulation to stop early once confident, ran %v trials to within %v", outcomes.Trials, outcomes.ConfidenceInterval())
+	}
+}
+
+func TestFadedOutbreaks(t *testing.T) {
+	fadedGame := func(unlocks ...string) *GameState {
+		cities := Cities([]*City{
+			{Name: "a", Disease: Faded.Type, NumInfections: 3, Neighbors: []string{"b", "c"}},
+			{Name: "b", Disease: Blue.Type, NumInfections: 3, Neighbors: []string{"a"}},
+			{Name: "c", Disease: Yellow.Type, NumInfections: 3, Neighbors: []string{"a"}},
+			{Name: "z", Disease: Yellow.Type},
+		})
+		return &GameState{
+			GameName:         "jul",
+			Unlocks:          unlocks,
+			Cities:           &cities,
+			DiseaseData:      []DiseaseData{Yellow, Blue, Faded},
+			InfectionDeck:    NewInfectionDeck([]CityName{"a", "z"}),
+			InfectionRate:    1,
+			GameTurns:        InitGameTurns(&Player{HumanName: "p1"}, &Player{HumanName: "p2"}),
+			ResolveOutbreaks: true,
+		}
+	}
+
+	// before the Faded are revealed they outbreak like cubes, whatever the
+	// month
+	gs := fadedGame()
+	outcomes, err := gs.SimulateInfectPhase(2000, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outcomes.WorstOutbreaks() != 1 {
+		t.Fatalf("Expected only a to outbreak before the Faded are unlocked, got %v", outcomes.Outbreaks)
+	}
+	chain, err := gs.Infect("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chain) != 1 || len(chain[0].OtherDisease) != 2 {
+		t.Fatalf("Expected b and c to take a cube of another disease before the Faded are unlocked, got %+v", chain)
+	}
+
+	gs = fadedGame(FadedUnlock)
+	outcomes, err = gs.SimulateInfectPhase(2000, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outcomes.WorstOutbreaks() != 2 || outcomes.ProbabilityOfOutbreak("c") != 0 {
+		t.Fatalf("Expected the Faded to take b and pass c by once unlocked, got %v", outcomes.Outbreaks)
+	}
+	chain, err = gs.Infect("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := gs.GetCity("b")
+	if len(chain) != 2 || len(chain[0].Faded) != 1 || len(chain[0].Spared) != 1 || b.Disease != Faded.Type {
+		t.Fatalf("Expected b to turn Faded and outbreak in turn, got %+v", chain)
+	}
+	c, _ := gs.GetCity("c")
+	if c.NumInfections != 3 || gs.NeighborSpillover("c") != 0 {
+		t.Fatalf("Expected c to be out of the Faded's reach, got %+v", c)
+	}
+
+	// which diseases the Faded can take is the game's to say
+	gs = fadedGame(FadedUnlock)
+	gs.DiseaseData[0].BecomingFaded = true
+	if chain, err = gs.Infect("a"); err != nil {
+		t.Fatal(err)
+	}
+	if c, _ = gs.GetCity("c"); len(chain[0].Faded) != 2 || c.Disease != Faded.Type {
+		t.Fatalf("Expected a game whose yellow is becoming faded to let the Faded take c, got %+v", chain)
+	}
+}
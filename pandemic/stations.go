@@ -0,0 +1,78 @@
+package pandemic
+
+import (
+	"fmt"
+)
+
+// MaxResearchStations is the number of research stations in the box.
+const MaxResearchStations = 6
+
+// ResearchStationsLeft is the number of research stations not yet built.
+func (gs GameState) ResearchStationsLeft() int {
+	return MaxResearchStations - len(gs.Cities.ResearchStations())
+}
+
+// BuildStation places a research station in the city, the player
+// discarding the card StationCost names for it. A nil player builds it
+// without discarding anything, for stations entered by hand. Once all of
+// them are on the map, one has to be moved instead.
+func (gs GameState) BuildStation(player *Player, cn CityName) error {
+	city, err := gs.canHoldStation(cn)
+	if err != nil {
+		return err
+	}
+	if gs.ResearchStationsLeft() <= 0 {
+		return fmt.Errorf("All %v research stations are built, move one with move-station instead", MaxResearchStations)
+	}
+	if player != nil {
+		card, err := gs.StationCost(player, cn)
+		if err != nil {
+			return err
+		}
+		if !card.Empty() {
+			if err := gs.Discard(player, card); err != nil {
+				return err
+			}
+		}
+	}
+	city.ResearchStation = true
+	gs.recordCity(StationBuilt, cn, "research station built in %v", cn)
+	return nil
+}
+
+// MoveStation takes the research station in one city and builds it in
+// another.
+func (gs GameState) MoveStation(from, to CityName) error {
+	old, err := gs.GetCity(from)
+	if err != nil {
+		return err
+	}
+	if !old.ResearchStation {
+		return fmt.Errorf("%v has no research station to move", from)
+	}
+	city, err := gs.canHoldStation(to)
+	if err != nil {
+		return err
+	}
+	old.ResearchStation = false
+	city.ResearchStation = true
+	gs.recordCity(StationBuilt, to, "research station moved from %v to %v", from, to)
+	return nil
+}
+
+func (gs GameState) canHoldStation(cn CityName) (*City, error) {
+	city, err := gs.GetCity(cn)
+	if err != nil {
+		return nil, err
+	}
+	if city.ResearchStation {
+		return nil, fmt.Errorf("%v already has a research station", cn)
+	}
+	if city.Unexplored {
+		return nil, fmt.Errorf("%v has not been explored yet", cn)
+	}
+	if !city.PanicLevel.CanBuildResearchStations() {
+		return nil, fmt.Errorf("%v is too panicked to build a research station", cn)
+	}
+	return city, nil
+}
@@ -0,0 +1,110 @@
+package pandemic
+
+import (
+	"fmt"
+)
+
+// A MonthTransition is what the group decides between one month's game and
+// the next.
+type MonthTransition struct {
+	Won bool
+	// Upgrades are the end of game upgrades chosen, as written on them.
+	Upgrades []string
+	// Scars are the scars each player's character took, by player name.
+	Scars map[string][]string
+	// Stickers are the stickers put on each city.
+	Stickers map[CityName][]string
+	// FundedEvents for the next game, or nil to keep the ones it had.
+	FundedEvents []FundedEventName
+	// StartCards for each player in the next game, or none to keep theirs.
+	StartCards map[string][]CardName
+}
+
+// RecordTransition stores the game's result and the upgrades chosen after
+// it in the campaign.
+func (c *Campaign) RecordTransition(gs *GameState, t MonthTransition) *MonthResult {
+	result := c.RecordResult(gs, t.Won)
+	for _, upgrade := range t.Upgrades {
+		c.Upgrades = append(c.Upgrades, &Upgrade{Month: result.Month, Attempt: result.Attempt, Description: upgrade})
+	}
+	return result
+}
+
+// NextGameSettings builds the setup of the next game from the finished one:
+// cities keep their panic levels, stickers and exploration, characters keep
+// their scars, and the transition's new stickers, scars, funded events and
+// start cards are added. Everything else comes from the settings the game
+// was started with.
+func (gs GameState) NextGameSettings(template NewGameSettings, t MonthTransition) (NewGameSettings, error) {
+	next := template
+	next.Cities = Cities{}
+	for _, city := range template.Cities {
+		carried := *city
+		if played, err := gs.GetCity(city.Name); err == nil {
+			carried.PanicLevel = played.PanicLevel
+			// exploring a city is permanent
+			carried.Unexplored = carried.Unexplored && played.Unexplored
+			carried.Stickers = append([]string{}, played.Stickers...)
+		}
+		carried.Stickers = append(carried.Stickers, t.Stickers[city.Name]...)
+		next.Cities = append(next.Cities, &carried)
+	}
+	for name := range t.Stickers {
+		if _, err := next.Cities.GetCity(name); err != nil {
+			return next, fmt.Errorf("Cannot put a sticker on %v: %v", name, err)
+		}
+	}
+
+	next.Players = []*Player{}
+	named := map[string]bool{}
+	for _, player := range template.Players {
+		carried := &Player{HumanName: player.HumanName, StartCards: player.StartCards}
+		if player.Character != nil {
+			character := *player.Character
+			scars := character.Scars
+			if played := gs.player(player.HumanName); played != nil && played.Character != nil {
+				scars = played.Character.Scars
+			}
+			character.Scars = append(append([]string{}, scars...), t.Scars[player.HumanName]...)
+			carried.Character = &character
+		} else if len(t.Scars[player.HumanName]) > 0 {
+			return next, fmt.Errorf("%v has no character to scar", player.HumanName)
+		}
+		if cards, ok := t.StartCards[player.HumanName]; ok {
+			carried.StartCards = cards
+		}
+		named[player.HumanName] = true
+		next.Players = append(next.Players, carried)
+	}
+	for name := range t.Scars {
+		if !named[name] {
+			return next, fmt.Errorf("No player named %v", name)
+		}
+	}
+	for name := range t.StartCards {
+		if !named[name] {
+			return next, fmt.Errorf("No player named %v", name)
+		}
+	}
+
+	if t.FundedEvents != nil {
+		next.FundedEvents = []*FundedEvent{}
+		for _, name := range t.FundedEvents {
+			next.FundedEvents = append(next.FundedEvents, &FundedEvent{Name: name})
+		}
+	}
+	return next, nil
+}
+
+// player finds the player with the given name, if they are in the game.
+func (gs GameState) player(name string) *Player {
+	if gs.GameTurns == nil {
+		return nil
+	}
+	for _, player := range gs.GameTurns.PlayerOrder {
+		if player.HumanName == name {
+			return player
+		}
+	}
+	return nil
+}
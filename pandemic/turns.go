@@ -2,6 +2,7 @@ package pandemic
 
 import (
 	"fmt"
+	"strings"
 )
 
 type GameTurns struct {
@@ -13,6 +14,33 @@ type GameTurns struct {
 type Turn struct {
 	Player     *Player     `json:"player"`
 	DrawnCards []*CityCard `json:"drawn_cards"`
+	Infected   []CityName  `json:"infected,omitempty"`
+	// Actions are the actions entered this turn, e.g. "cure" or "build".
+	Actions []string `json:"actions,omitempty"`
+	// QuietNight is set when One Quiet Night skips the turn's infection
+	// step.
+	QuietNight bool `json:"quiet_night,omitempty"`
+}
+
+type Phase string
+
+const (
+	ActionPhase = Phase("actions")
+	DrawPhase   = Phase("draw")
+	InfectPhase = Phase("infect")
+)
+
+// Phase is derived from what has been recorded so far this turn: nothing
+// drawn means players are still taking actions, the turn moves to the infect
+// phase once both city cards (including epidemics) have been drawn.
+func (t *Turn) Phase() Phase {
+	if len(t.Infected) > 0 || len(t.DrawnCards) >= CityCardsPerTurn {
+		return InfectPhase
+	}
+	if len(t.DrawnCards) > 0 {
+		return DrawPhase
+	}
+	return ActionPhase
 }
 
 func (t *GameTurns) AddPlayer(p *Player) error {
@@ -107,3 +135,85 @@ func InitGameTurns(ps ...*Player) *GameTurns {
 	}
 	return turns
 }
+
+// InfectionsLeft is how many more cities the current turn's infection step
+// should infect. It is negative once more cities have been infected than
+// the infection rate.
+func (gs GameState) InfectionsLeft() int {
+	turn := gs.currentTurn()
+	if turn == nil {
+		return 0
+	}
+	return gs.InfectionsThisTurn() - len(turn.Infected)
+}
+
+// CheckInfections warns when the current turn infected fewer or more cities
+// than the infection rate, which usually means one was missed or entered
+// twice.
+func (gs GameState) CheckInfections() error {
+	turn := gs.currentTurn()
+	if turn == nil {
+		return nil
+	}
+	left := gs.InfectionsLeft()
+	if left > 0 {
+		return fmt.Errorf("%v has infected %v of %v cities this turn", turn.Player.HumanName, len(turn.Infected), gs.InfectionsThisTurn())
+	}
+	if left < 0 {
+		return fmt.Errorf("%v has infected %v cities this turn, more than the infection rate of %v", turn.Player.HumanName, len(turn.Infected), gs.InfectionsThisTurn())
+	}
+	return nil
+}
+
+// TurnComplete is true once the current player has drawn their city cards
+// and infected as many cities as the infection rate, with every hand within
+// the hand limit.
+func (gs GameState) TurnComplete() bool {
+	turn := gs.currentTurn()
+	if turn == nil || len(turn.DrawnCards) < CityCardsPerTurn {
+		return false
+	}
+	return gs.InfectionsLeft() == 0 && gs.CheckHandLimits() == nil
+}
+
+// AdvanceTurn moves on to the next player once the current turn is
+// complete, returning their turn, or nil while the turn isn't over.
+func (gs GameState) AdvanceTurn() (*Turn, error) {
+	if !gs.TurnComplete() || gs.CheckPlaying() != nil {
+		return nil, nil
+	}
+	return gs.NextTurn()
+}
+
+// TakeAction counts one of the current player's actions. Going over the
+// actions a turn has is allowed, since the table may have played a rule
+// the tracker doesn't know about, but CheckActions warns about it.
+func (gs GameState) TakeAction(action string) error {
+	turn := gs.currentTurn()
+	if turn == nil {
+		return fmt.Errorf("No turn to take %v on", action)
+	}
+	turn.Actions = append(turn.Actions, action)
+	gs.changed("%v took a %v action", turn.Player.HumanName, action)
+	return nil
+}
+
+// ActionsLeft is how many actions the current player has left this turn.
+// It is negative once more actions were entered than a turn has.
+func (gs GameState) ActionsLeft() int {
+	turn := gs.currentTurn()
+	if turn == nil {
+		return 0
+	}
+	return ActionsPerTurn - len(turn.Actions)
+}
+
+// CheckActions warns when the current player has taken more actions than
+// a turn has, which means the board and the tracker disagree.
+func (gs GameState) CheckActions() error {
+	turn := gs.currentTurn()
+	if turn == nil || gs.ActionsLeft() >= 0 {
+		return nil
+	}
+	return fmt.Errorf("%v has taken %v actions this turn, only %v are allowed: %v", turn.Player.HumanName, len(turn.Actions), ActionsPerTurn, strings.Join(turn.Actions, ", "))
+}
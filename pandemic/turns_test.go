@@ -49,3 +49,94 @@ func TestRemainingTurns(t *testing.T) {
 		}
 	}
 }
+
+func TestTurnPhase(t *testing.T) {
+	turns := InitGameTurns(&Player{HumanName: "a"}, &Player{HumanName: "b"})
+	turn, _ := turns.CurrentTurn()
+	if turn.Phase() != ActionPhase {
+		t.Fatalf("Expected a new turn to be in the action phase, was %v", turn.Phase())
+	}
+	turns.AddDrawnToCurrent(&CityCard{CityName: "a"})
+	if turn.Phase() != DrawPhase {
+		t.Fatalf("Expected the draw phase after one draw, was %v", turn.Phase())
+	}
+	turns.AddDrawnToCurrent(&CityCard{IsEpidemic: true})
+	if turn.Phase() != InfectPhase {
+		t.Fatalf("Expected the infect phase after two draws, was %v", turn.Phase())
+	}
+	turn, _ = turns.NextTurn()
+	turn.Infected = append(turn.Infected, "a")
+	if turn.Phase() != InfectPhase {
+		t.Fatalf("Expected any infection to put the turn in the infect phase, was %v", turn.Phase())
+	}
+}
+
+func TestAdvanceTurn(t *testing.T) {
+	cities := Cities([]*City{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}, {Name: "f"}})
+	gs := &GameState{
+		Cities:        &cities,
+		InfectionDeck: NewInfectionDeck(cities.CityNames()),
+		InfectionRate: 2,
+		GameTurns:     InitGameTurns(&Player{HumanName: "p1"}, &Player{HumanName: "p2"}),
+	}
+	gs.GameTurns.AddDrawnToCurrent(&CityCard{CityName: "a"})
+	gs.GameTurns.AddDrawnToCurrent(&CityCard{CityName: "b"})
+	if _, err := gs.Infect("a"); err != nil {
+		t.Fatal(err)
+	}
+	if gs.InfectionsLeft() != 1 || gs.CheckInfections() == nil {
+		t.Fatalf("Expected one infection to be missing, %v left", gs.InfectionsLeft())
+	}
+	if turn, err := gs.AdvanceTurn(); err != nil || turn != nil {
+		t.Fatalf("Should not advance before the infection step is done, got %v %v", turn, err)
+	}
+	if _, err := gs.Infect("b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.CheckInfections(); err != nil {
+		t.Fatal(err)
+	}
+	turn, err := gs.AdvanceTurn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if turn == nil || turn.Player.HumanName != "p2" {
+		t.Fatalf("Expected p2's turn once p1's was complete, got %v", turn)
+	}
+	gs.GameTurns.AddDrawnToCurrent(&CityCard{CityName: "c"})
+	gs.GameTurns.AddDrawnToCurrent(&CityCard{CityName: "d"})
+	for _, city := range []CityName{"c", "d", "e"} {
+		if _, err := gs.Infect(city); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if gs.InfectionsLeft() != -1 || gs.CheckInfections() == nil {
+		t.Fatalf("Expected a warning for infecting more cities than the rate, %v left", gs.InfectionsLeft())
+	}
+	if turn, err := gs.AdvanceTurn(); err != nil || turn != nil {
+		t.Fatalf("Should not advance after too many infections, got %v %v", turn, err)
+	}
+}
+
+func TestActionCounter(t *testing.T) {
+	gs := &GameState{GameTurns: InitGameTurns(&Player{HumanName: "p1"}, &Player{HumanName: "p2"})}
+	for _, action := range []string{"move", "treat", "build", "cure"} {
+		if err := gs.TakeAction(action); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if gs.ActionsLeft() != 0 {
+		t.Fatalf("Expected no actions left, had %v", gs.ActionsLeft())
+	}
+	if err := gs.CheckActions(); err != nil {
+		t.Fatalf("Four actions should be allowed: %v", err)
+	}
+	gs.TakeAction("move")
+	if gs.ActionsLeft() != -1 || gs.CheckActions() == nil {
+		t.Fatalf("Expected a warning for a fifth action, %v left", gs.ActionsLeft())
+	}
+	turn, _ := gs.NextTurn()
+	if len(turn.Actions) != 0 || gs.ActionsLeft() != ActionsPerTurn {
+		t.Fatalf("Expected the next turn to start with %v actions, had %v", ActionsPerTurn, gs.ActionsLeft())
+	}
+}
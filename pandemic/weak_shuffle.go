@@ -0,0 +1,150 @@
+package pandemic
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// WeakShuffleBias is how much likelier the oldest card of a weakly shuffled
+// striation is to be drawn first than the newest one. The discard pile is
+// face up with the newest card on top, so turning it over without a proper
+// shuffle leaves the oldest cards near the top of the deck.
+const WeakShuffleBias = 1.0
+
+// MarkWeaklyShuffled weights the cards of a striation by where they sat in
+// the discard pile when it was shuffled back onto the deck.
+func (d *InfectionDeck) MarkWeaklyShuffled(striation int) error {
+	return d.MarkCut(striation, 0.0)
+}
+
+// MarkCut weights a weakly shuffled striation that was then cut, with
+// roughly the given fraction of it taken off the top and put underneath.
+// Cards the cut moved are weighted as the newest discards, and the ones
+// left behind move up accordingly.
+func (d *InfectionDeck) MarkCut(striation int, fraction float64) error {
+	if striation < 0 || striation >= len(d.Striations) {
+		return fmt.Errorf("There is no striation %v", striation)
+	}
+	if fraction < 0.0 || fraction >= 1.0 {
+		return fmt.Errorf("A cut must take less than the whole striation, not %v", fraction)
+	}
+	members := d.CitiesInStriation(striation)
+	deepest := 0
+	for _, city := range members {
+		pos, ok := d.ShuffledAt[city]
+		if !ok {
+			return fmt.Errorf("The discard order of striation %v is not known", striation)
+		}
+		if pos > deepest {
+			deepest = pos
+		}
+	}
+	size := deepest + 1
+	moved := int(fraction*float64(size) + 0.5)
+	if d.Weights == nil {
+		d.Weights = map[CityName]float64{}
+	}
+	for _, city := range members {
+		rank := d.ShuffledAt[city] + moved
+		if rank >= size {
+			rank -= size
+		}
+		weight := 1.0
+		if deepest > 0 {
+			weight += WeakShuffleBias * float64(rank) / float64(deepest)
+		}
+		d.Weights[city] = weight
+	}
+	return nil
+}
+
+// MarkFairlyShuffled goes back to treating every card in the striation as
+// equally likely.
+func (d *InfectionDeck) MarkFairlyShuffled(striation int) error {
+	if striation < 0 || striation >= len(d.Striations) {
+		return fmt.Errorf("There is no striation %v", striation)
+	}
+	for _, city := range d.CitiesInStriation(striation) {
+		delete(d.Weights, city)
+	}
+	return nil
+}
+
+func (d *InfectionDeck) WeaklyShuffled(striation int) bool {
+	for _, city := range d.CitiesInStriation(striation) {
+		if _, ok := d.Weights[city]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *InfectionDeck) weight(city CityName) float64 {
+	if weight, ok := d.Weights[city]; ok {
+		return weight
+	}
+	return 1.0
+}
+
+// weightedDrawProbability is the chance that the city is among the first
+// draws cards taken from the given cards, when each draw picks a remaining
+// card in proportion to its weight. There are few enough cards in a
+// striation and draws in a turn to try every order.
+func (d *InfectionDeck) weightedDrawProbability(cards []CityName, city CityName, draws int) float64 {
+	if draws == 0 {
+		return 0.0
+	}
+	var total float64
+	for _, card := range cards {
+		total += d.weight(card)
+	}
+	var probability float64
+	for i, card := range cards {
+		p := d.weight(card) / total
+		if card == city {
+			probability += p
+			continue
+		}
+		rest := append(append([]CityName{}, cards[:i]...), cards[i+1:]...)
+		probability += p * d.weightedDrawProbability(rest, city, draws-1)
+	}
+	return probability
+}
+
+// pick draws one of the cards at random, in proportion to their weights.
+func (d *InfectionDeck) pick(cards []CityName, rng *rand.Rand) CityName {
+	var total float64
+	for _, card := range cards {
+		total += d.weight(card)
+	}
+	roll := rng.Float64() * total
+	for _, card := range cards {
+		if roll < d.weight(card) {
+			return card
+		}
+		roll -= d.weight(card)
+	}
+	return cards[len(cards)-1]
+}
+
+// MarkWeaklyShuffled, MarkCut and MarkFairlyShuffled mark how the game's
+// infection deck striation was shuffled, as the deck's methods do.
+func (gs GameState) MarkWeaklyShuffled(striation int) error {
+	return gs.MarkCut(striation, 0.0)
+}
+
+func (gs GameState) MarkCut(striation int, fraction float64) error {
+	if err := gs.InfectionDeck.MarkCut(striation, fraction); err != nil {
+		return err
+	}
+	gs.changed("infection %v marked as weakly shuffled", striation)
+	return nil
+}
+
+func (gs GameState) MarkFairlyShuffled(striation int) error {
+	if err := gs.InfectionDeck.MarkFairlyShuffled(striation); err != nil {
+		return err
+	}
+	gs.changed("infection %v marked as fairly shuffled", striation)
+	return nil
+}
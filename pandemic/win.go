@@ -0,0 +1,173 @@
+package pandemic
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A WinCondition is one thing the team has to do to win. A game takes its
+// conditions from the new game file, so a month or variant can set its own,
+// and otherwise from its ruleset. Each kind is measured by the WinCheck
+// registered for it.
+type WinCondition struct {
+	Kind        string `json:"kind"`
+	Description string `json:"description,omitempty"`
+	// Count is how many are needed, or 0 for all of them.
+	Count int `json:"count,omitempty"`
+	// Diseases limits cures and eradications to these diseases, otherwise
+	// every curable disease in the game counts.
+	Diseases []DiseaseType `json:"diseases,omitempty"`
+}
+
+func (c WinCondition) String() string {
+	if c.Description != "" {
+		return c.Description
+	}
+	if c.Count > 0 {
+		return fmt.Sprintf("%v %v", c.Count, c.Kind)
+	}
+	return fmt.Sprintf("all %v", c.Kind)
+}
+
+// A WinCheck measures a game's progress towards a condition: how much is
+// done and how much is needed.
+type WinCheck func(gs GameState, condition WinCondition) (done, needed int)
+
+var winChecks = map[string]WinCheck{}
+
+func RegisterWinCheck(kind string, check WinCheck) {
+	winChecks[kind] = check
+}
+
+func init() {
+	RegisterWinCheck("cures", countDiseases(func(data DiseaseData) bool { return data.Cured }))
+	RegisterWinCheck("eradications", countDiseases(func(data DiseaseData) bool { return data.Eradicated }))
+	RegisterWinCheck("stations", func(gs GameState, condition WinCondition) (int, int) {
+		return len(gs.Cities.ResearchStations()), condition.Count
+	})
+	// marked conditions are ones the tracker can't see, e.g. a search, so
+	// the table marks them done
+	RegisterWinCheck("marked", func(gs GameState, condition WinCondition) (int, int) {
+		for _, marked := range gs.Marked {
+			if marked == condition.Description {
+				return 1, 1
+			}
+		}
+		return 0, 1
+	})
+}
+
+func countDiseases(counts func(DiseaseData) bool) WinCheck {
+	return func(gs GameState, condition WinCondition) (int, int) {
+		wanted := map[DiseaseType]bool{}
+		for _, dt := range condition.Diseases {
+			wanted[dt] = true
+		}
+		done, total := 0, 0
+		for _, data := range gs.DiseaseData {
+			if (len(wanted) > 0 && !wanted[data.Type]) || (len(wanted) == 0 && data.Incurable) {
+				continue
+			}
+			total++
+			if counts(data) {
+				done++
+			}
+		}
+		if condition.Count > 0 {
+			return done, condition.Count
+		}
+		return done, total
+	}
+}
+
+// CheckWinConditions refuses conditions of a kind nobody registered a check
+// for, and ones that can't be told apart or measured.
+func CheckWinConditions(conditions []WinCondition) error {
+	for _, condition := range conditions {
+		if _, ok := winChecks[condition.Kind]; !ok {
+			return fmt.Errorf("Unknown win condition %q", condition.Kind)
+		}
+		if condition.Kind == "marked" && condition.Description == "" {
+			return fmt.Errorf("A marked win condition needs a description to mark it by")
+		}
+		if condition.Kind == "stations" && condition.Count <= 0 {
+			return fmt.Errorf("A stations win condition needs a count")
+		}
+	}
+	return nil
+}
+
+// WinStatus is how far the game is towards one of its win conditions.
+type WinStatus struct {
+	Condition WinCondition
+	Done      int
+	Needed    int
+}
+
+// Met is true once enough is done. A condition with nothing to do, e.g.
+// cures in a game without curable diseases, is never met.
+func (s WinStatus) Met() bool {
+	return s.Needed > 0 && s.Done >= s.Needed
+}
+
+func (s WinStatus) String() string {
+	return fmt.Sprintf("%v: %v of %v", s.Condition, s.Done, s.Needed)
+}
+
+// Conditions are the game's own win conditions, or its ruleset's.
+func (gs GameState) Conditions() []WinCondition {
+	if len(gs.WinConditions) > 0 {
+		return gs.WinConditions
+	}
+	return gs.rules().WinConditions()
+}
+
+// WinProgress measures every win condition of the game.
+func (gs GameState) WinProgress() []WinStatus {
+	statuses := []WinStatus{}
+	for _, condition := range gs.Conditions() {
+		check, ok := winChecks[condition.Kind]
+		if !ok {
+			continue
+		}
+		done, needed := check(gs, condition)
+		statuses = append(statuses, WinStatus{Condition: condition, Done: done, Needed: needed})
+	}
+	return statuses
+}
+
+// Won is true once the game has win conditions and every one is met.
+func (gs GameState) Won() bool {
+	statuses := gs.WinProgress()
+	for _, status := range statuses {
+		if !status.Met() {
+			return false
+		}
+	}
+	return len(statuses) > 0
+}
+
+// Mark records a win condition the tracker can't check for itself as done,
+// found by the start of its description.
+func (gs *GameState) Mark(prefix string) error {
+	found := []string{}
+	for _, condition := range gs.Conditions() {
+		if condition.Kind == "marked" && strings.HasPrefix(strings.ToLower(condition.Description), strings.ToLower(prefix)) {
+			found = append(found, condition.Description)
+		}
+	}
+	if len(found) == 0 {
+		return fmt.Errorf("No win condition to mark starts with %v", prefix)
+	}
+	if len(found) > 1 {
+		return fmt.Errorf("%v could be any of %v", prefix, strings.Join(found, ", "))
+	}
+	for _, marked := range gs.Marked {
+		if marked == found[0] {
+			return fmt.Errorf("%v is already marked done", found[0])
+		}
+	}
+	gs.Marked = append(gs.Marked, found[0])
+	gs.record(ManualOverride, "marked %v done", found[0])
+	return nil
+}
@@ -0,0 +1,70 @@
+package pandemic
+
+import (
+	"testing"
+)
+
+func TestWinConditions(t *testing.T) {
+	cities := Cities([]*City{{Name: "a", Disease: Yellow.Type}, {Name: "b", Disease: Red.Type}})
+	gs := &GameState{
+		Cities:      &cities,
+		DiseaseData: []DiseaseData{Yellow, Red, Blue},
+		GameTurns:   InitGameTurns(&Player{HumanName: "p1"}, &Player{HumanName: "p2"}),
+		Log:         &EventLog{},
+	}
+	// Season 1 is won by curing every curable disease, which leaves out blue
+	if err := gs.Cure(Yellow.Type); err != nil {
+		t.Fatal(err)
+	}
+	if gs.Won() {
+		t.Fatal("Should not win with red still uncured")
+	}
+	if err := gs.Cure(Red.Type); err != nil {
+		t.Fatal(err)
+	}
+	if !gs.Won() {
+		t.Fatalf("Expected to win with every curable disease cured, got %v", gs.WinProgress())
+	}
+
+	gs.WinConditions = []WinCondition{
+		{Kind: "eradications", Count: 1, Diseases: []DiseaseType{Yellow.Type}},
+		{Kind: "stations", Count: 1},
+		{Kind: "marked", Description: "Search for the source"},
+	}
+	if err := CheckWinConditions(gs.WinConditions); err != nil {
+		t.Fatal(err)
+	}
+	if progress := gs.WinProgress(); len(progress) != 3 || progress[0].Met() || progress[1].Met() || progress[2].Met() {
+		t.Fatalf("Expected the game's own conditions to replace the ruleset's, got %v", progress)
+	}
+	if err := gs.Eradicate(Yellow.Type); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.BuildStation(nil, "b"); err != nil {
+		t.Fatal(err)
+	}
+	if gs.Won() {
+		t.Fatal("Should not win before the search is marked done")
+	}
+	if err := gs.Mark("nothing"); err == nil {
+		t.Fatal("Should not mark a condition that isn't there")
+	}
+	if err := gs.Mark("search"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gs.Mark("search"); err == nil {
+		t.Fatal("Should not mark a condition twice")
+	}
+	if !gs.Won() {
+		t.Fatalf("Expected to win with every condition met, got %v", gs.WinProgress())
+	}
+	if err := gs.EndGame(false); err != nil || gs.Ended != "won" {
+		t.Fatalf("Expected a won game to end without conceding, got %v", err)
+	}
+
+	for _, bad := range []WinCondition{{Kind: "vaccinate"}, {Kind: "marked"}, {Kind: "stations"}} {
+		if err := CheckWinConditions([]WinCondition{bad}); err == nil {
+			t.Fatalf("Expected %+v to be refused", bad)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package pandemic
+
+import (
+	"sort"
+)
+
+// WorstCase is the worst that can legally happen before the next turn: the
+// rest of this turn's city draws, including an epidemic if one can still be
+// drawn, followed by the infection phase. Each card is played out on a copy
+// of the board, so outbreak chains and the cubes they spread count. Each
+// figure is maximized on its own, so the worst outbreaks and the worst
+// cubes of each color may come from different sequences.
+type WorstCase struct {
+	EpidemicPossible bool
+	Outbreaks        int
+	OutbreakCities   []CityName // one sequence of draws reaching Outbreaks
+	Cubes            map[DiseaseType]int
+}
+
+// infectionEffect is what drawing an infection card does to the board: the
+// outbreaks it sets off and the cubes of each disease it takes from the
+// supply, those the outbreaks spread to the neighbors included.
+type infectionEffect struct {
+	Outbreaks int
+	Cubes     map[DiseaseType]int
+}
+
+// boardClone is a copy of the game to play infections out on. The city
+// deck and the event log play no part in them, so they are left out to
+// keep the copy cheap.
+func (gs GameState) boardClone() (*GameState, error) {
+	gs.CityDeck = nil
+	gs.Log = nil
+	return gs.Clone()
+}
+
+// playOut places cubes on the city, resolving the outbreaks they set off,
+// on a copy of the game and measures what that did. The copy is returned
+// for more to be played out on.
+func (gs GameState) playOut(cn CityName, cubes int) (infectionEffect, *GameState) {
+	effect := infectionEffect{Cubes: map[DiseaseType]int{}}
+	sandbox, err := gs.boardClone()
+	if err != nil {
+		return effect, &gs
+	}
+	city, err := sandbox.GetCity(cn)
+	if err != nil {
+		return effect, sandbox
+	}
+	before := sandbox.cubesTaken()
+	chain, _ := sandbox.placeInfection(city, cubes)
+	effect.Outbreaks = len(chain)
+	for dt, taken := range sandbox.cubesTaken() {
+		if placed := taken - before[dt]; placed > 0 {
+			effect.Cubes[dt] = placed
+		}
+	}
+	return effect, sandbox
+}
+
+// cubesTaken counts the cubes of each disease taken from the supply. Only
+// the difference between two counts means anything: Season 2 keeps the
+// plague cubes left rather than those on the board.
+func (gs GameState) cubesTaken() map[DiseaseType]int {
+	if gs.Supply != nil {
+		return map[DiseaseType]int{Plague.Type: -gs.Supply.PlagueCubes}
+	}
+	onBoard := map[DiseaseType]int{}
+	for _, city := range *gs.Cities {
+		onBoard[city.Disease] += city.NumInfections
+		for dt, cubes := range city.OtherCubes {
+			onBoard[dt] += cubes
+		}
+	}
+	return onBoard
+}
+
+// infectionEffects works out the effect of each city's infection card on
+// the game as it is, playing each one out only when first asked for.
+func (gs GameState) infectionEffects() func(CityName) infectionEffect {
+	effects := map[CityName]infectionEffect{}
+	return func(cn CityName) infectionEffect {
+		effect, ok := effects[cn]
+		if !ok {
+			effect, _ = gs.playOut(cn, 1)
+			effects[cn] = effect
+		}
+		return effect
+	}
+}
+
+// worstDraw picks the n cards that score highest, taking every card of a
+// striation before any from the striation below it. Each card is scored on
+// its own, as if the others hadn't been drawn, so the greedy choice is the
+// worst draw of cards whose outbreak chains don't overlap.
+func worstDraw(striations []Set, n int, score func(CityName) int) (int, []CityName) {
+	total := 0
+	picked := []CityName{}
+	for _, striation := range striations {
+		if n == 0 {
+			break
+		}
+		cities := []CityName{}
+		for _, member := range striation.Members() {
+			cities = append(cities, CityName(member))
+		}
+		sort.Stable(byScore{cities, score})
+		for _, city := range cities {
+			if n == 0 {
+				break
+			}
+			total += score(city)
+			picked = append(picked, city)
+			n--
+		}
+	}
+	return total, picked
+}
+
+type byScore struct {
+	cities []CityName
+	score  func(CityName) int
+}
+
+func (b byScore) Len() int           { return len(b.cities) }
+func (b byScore) Swap(i, j int)      { b.cities[i], b.cities[j] = b.cities[j], b.cities[i] }
+func (b byScore) Less(i, j int) bool { return b.score(b.cities[i]) > b.score(b.cities[j]) }
+
+func (gs GameState) WorstCase() WorstCase {
+	worst := WorstCase{Cubes: map[DiseaseType]int{}}
+	diseases := []DiseaseType{}
+	for _, city := range *gs.Cities {
+		if _, ok := worst.Cubes[city.Disease]; !ok {
+			worst.Cubes[city.Disease] = 0
+			diseases = append(diseases, city.Disease)
+		}
+	}
+
+	// consider the worst draws of the infection phase played on the board,
+	// after the epidemic if there was one
+	consider := func(board GameState, striations []Set, rate int, epidemicCity CityName, epidemic infectionEffect) {
+		effect := board.infectionEffects()
+		outbreaks, picked := worstDraw(striations, rate, func(cn CityName) int {
+			return effect(cn).Outbreaks
+		})
+		if epidemic.Outbreaks > 0 {
+			outbreaks += epidemic.Outbreaks
+			picked = append([]CityName{epidemicCity}, picked...)
+		}
+		if outbreaks > worst.Outbreaks || worst.OutbreakCities == nil {
+			worst.Outbreaks = outbreaks
+			worst.OutbreakCities = picked
+		}
+		for _, dt := range diseases {
+			cubes, _ := worstDraw(striations, rate, func(cn CityName) int {
+				return effect(cn).Cubes[dt]
+			})
+			cubes += epidemic.Cubes[dt]
+			if cubes > worst.Cubes[dt] {
+				worst.Cubes[dt] = cubes
+			}
+		}
+	}
+
+	consider(gs, gs.InfectionDeck.Striations, gs.InfectionsThisTurn(), "", infectionEffect{})
+
+	analysis := gs.CityDeck.EpidemicAnalysis()
+	worst.EpidemicPossible = analysis.FirstCardProbability+analysis.SecondCardProbability > 0
+	if worst.EpidemicPossible {
+		rate := gs.NextInfectionRate()
+		if gs.QuietNight() {
+			rate = 0
+		}
+		for _, member := range gs.InfectionDeck.BottomStriation().Members() {
+			bottom := CityName(member)
+			if _, err := gs.GetCity(bottom); err != nil {
+				continue
+			}
+			epidemic, board := gs.playOut(bottom, MaxInfections)
+			consider(*board, gs.InfectionDeck.intensified(bottom), rate, bottom, epidemic)
+		}
+	}
+	return worst
+}
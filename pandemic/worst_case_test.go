@@ -0,0 +1,92 @@
+package pandemic
+
+import (
+	"testing"
+)
+
+func TestWorstCase(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", Disease: Yellow.Type, NumInfections: 3},
+		{Name: "b", Disease: Yellow.Type, NumInfections: 3},
+		{Name: "c", Disease: Blue.Type, NumInfections: 1},
+		{Name: "d", Disease: Blue.Type},
+		{Name: "e", Disease: Blue.Type},
+	})
+	deck := NewInfectionDeck(cities.CityNames())
+	deck.Striations = []Set{
+		Set{}.Add(CityName("a")).Add(CityName("c")).Add(CityName("d")),
+		Set{}.Add(CityName("b")).Add(CityName("e")),
+	}
+	cityDeck, err := cities.GenerateCityDeck(1, nil, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cityDeck.DrawEpidemic()
+	gs := GameState{
+		Cities:        &cities,
+		CityDeck:      &cityDeck,
+		InfectionDeck: deck,
+		InfectionRate: 2,
+	}
+
+	worst := gs.WorstCase()
+	if worst.EpidemicPossible {
+		t.Fatal("The only epidemic has already been drawn")
+	}
+	// b is below the top striation, so only a can outbreak
+	if worst.Outbreaks != 1 || worst.OutbreakCities[0] != "a" {
+		t.Fatalf("Expected a single outbreak in a, got %+v", worst)
+	}
+	if worst.Cubes[Blue.Type] != 2 || worst.Cubes[Yellow.Type] != 0 {
+		t.Fatalf("Expected at most 2 blue cubes and no yellow ones, got %v", worst.Cubes)
+	}
+
+	gs.InfectionRate = 4
+	if worst := gs.WorstCase(); worst.Outbreaks != 2 {
+		t.Fatalf("Drawing past the top striation should reach b, got %+v", worst)
+	}
+
+	// an epidemic in b outbreaks it, puts it back on top to be drawn and
+	// outbreak again, and raises the infection rate to 3 which reaches a
+	gs.InfectionRate = 2
+	freshDeck, _ := cities.GenerateCityDeck(1, nil, Set{})
+	gs.CityDeck = &freshDeck
+	worst = gs.WorstCase()
+	if !worst.EpidemicPossible || worst.Outbreaks != 3 {
+		t.Fatalf("Expected 3 outbreaks after an epidemic in b, got %+v", worst)
+	}
+}
+
+func TestWorstCaseOutbreakChain(t *testing.T) {
+	cities := Cities([]*City{
+		{Name: "a", Disease: Yellow.Type, NumInfections: 3, Neighbors: []string{"b", "c"}},
+		{Name: "b", Disease: Yellow.Type, NumInfections: 3, Neighbors: []string{"a"}},
+		{Name: "c", Disease: Yellow.Type, Neighbors: []string{"a"}},
+	})
+	deck := NewInfectionDeck(cities.CityNames())
+	deck.Striations = []Set{Set{}.Add(CityName("a"))}
+	cityDeck, err := cities.GenerateCityDeck(1, nil, Set{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cityDeck.DrawEpidemic()
+	gs := GameState{
+		Cities:           &cities,
+		CityDeck:         &cityDeck,
+		InfectionDeck:    deck,
+		InfectionRate:    1,
+		ResolveOutbreaks: true,
+	}
+
+	// a outbreaks into b, which outbreaks in turn, and spreads a cube to c
+	worst := gs.WorstCase()
+	if worst.Outbreaks != 2 {
+		t.Fatalf("Expected a chain of 2 outbreaks, got %+v", worst)
+	}
+	if worst.Cubes[Yellow.Type] != 1 {
+		t.Fatalf("Expected the cube spread to c, got %v", worst.Cubes)
+	}
+	if city, _ := gs.GetCity("b"); city.NumInfections != 3 {
+		t.Fatal("Playing out the worst case should not change the board")
+	}
+}
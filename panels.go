@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+	"github.com/jroimartin/gocui"
+)
+
+// Panels can be hidden to make room on a small terminal, e.g. to give the
+// striations the whole screen during the infection phase. The commands
+// line is always shown.
+var panelViews = map[string][]string{
+	"striations": {"Drawn"},
+	"cities":     {"Cities", "Turns"},
+	"cures":      {"Cures", "Composition"},
+	"console":    {"Console"},
+}
+
+func (p *PandemicView) panelShown(name string) bool {
+	for _, hidden := range p.config.HiddenPanels {
+		if hidden == name {
+			return false
+		}
+	}
+	return true
+}
+
+// layoutPanels places the shown panels below the commands line. Hidden
+// panels give their space to their neighbours.
+func (p *PandemicView) layoutPanels(game *pandemic.GameState, gui *gocui.Gui, width, height int) {
+	showCities := p.panelShown("cities")
+	showRight := p.panelShown("cures") || p.panelShown("console")
+	split := height / 2
+	if !showCities && !showRight {
+		split = height - 1
+	}
+	if p.panelShown("striations") {
+		p.renderStriations(game, gui, 2, split, width)
+	} else {
+		split = 3
+	}
+	rightX := width / 2
+	if showCities {
+		cityWidth := width
+		if showRight {
+			cityWidth = width / 2
+		}
+		p.renderCityDeckAndTurns(game, gui, 0, split, cityWidth, height)
+	} else {
+		rightX = 0
+	}
+	top := split
+	if p.panelShown("cures") {
+		p.renderCureProgress(game, gui, rightX, top, width, top+cureProgressHeight())
+		top += cureProgressHeight()
+		p.renderComposition(game, gui, rightX, top, width, top+compositionHeight(game))
+		top += compositionHeight(game)
+	}
+	if p.panelShown("console") {
+		p.renderConsoleArea(game, gui, rightX, top, width, height)
+	}
+}
+
+func (p *PandemicView) togglePanel(name string) func(*gocui.Gui, *gocui.View) error {
+	return func(gui *gocui.Gui, view *gocui.View) error {
+		if p.panelShown(name) {
+			p.config.HiddenPanels = append(p.config.HiddenPanels, name)
+			for _, view := range panelViews[name] {
+				gui.DeleteView(view)
+			}
+			if name == "striations" {
+				for i := 0; gui.DeleteView(fmt.Sprintf("Infection %v", i)) == nil; i++ {
+				}
+			}
+		} else {
+			shown := []string{}
+			for _, hidden := range p.config.HiddenPanels {
+				if hidden != name {
+					shown = append(shown, hidden)
+				}
+			}
+			p.config.HiddenPanels = shown
+		}
+		if err := p.config.saveUserSetting("hidden_panels", p.config.HiddenPanels); err != nil {
+			p.logger.Errorf("Could not save the layout: %v", err)
+		}
+		return nil
+	}
+}
+
+// saveUserSetting writes one setting to the user's config file, keeping
+// everything else in it as it was.
+func (c *Config) saveUserSetting(key string, value interface{}) error {
+	if c.userFile == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(c.userFile)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if data, err = setTOMLSetting(data, key, value); err != nil {
+		return fmt.Errorf("Could not write %v to the config file at %v: %v", key, c.userFile, err)
+	}
+	return ioutil.WriteFile(c.userFile, data, 0644)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+)
+
+const defaultForecastPhases = 3
+
+// nextEpidemicTurn is the first turn, counting from this one, whose city
+// card draws are still to come.
+func nextEpidemicTurn(gs *pandemic.GameState) int {
+	turn, err := gs.GameTurns.CurrentTurn()
+	if err != nil || turn.Phase() == pandemic.InfectPhase {
+		return 1
+	}
+	return 0
+}
+
+// phaseDraws describes where a phase's cards come from, e.g.
+// "1 reshuffled, 2 from striation 0".
+func phaseDraws(forecast pandemic.PhaseForecast) string {
+	if forecast.Skipped {
+		return "skipped by One Quiet Night"
+	}
+	parts := []string{}
+	if forecast.Reshuffled > 0 {
+		parts = append(parts, fmt.Sprintf("%v reshuffled", forecast.Reshuffled))
+	}
+	for i, draws := range forecast.Draws {
+		if draws > 0 {
+			parts = append(parts, fmt.Sprintf("%v from striation %v", draws, i))
+		}
+	}
+	if len(parts) == 0 {
+		return "done"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func phaseTurn(turn int) string {
+	if turn == 0 {
+		return "this turn"
+	}
+	return fmt.Sprintf("turn +%v", turn)
+}
+
+// printPhases forecasts the striations the next infection phases draw
+// from, both without an epidemic and with one in the next city card draws,
+// since its rate change and reshuffle move every later phase.
+func printPhases(out io.Writer, t translator, gs *pandemic.GameState, phases int) {
+	fmt.Fprint(out, t("Without an epidemic, at rate %v:\n", gs.InfectionRate))
+	for _, forecast := range gs.ForecastPhases(phases, -1) {
+		fmt.Fprintf(out, "  %v: %v\n", phaseTurn(forecast.Turn), phaseDraws(forecast))
+	}
+	epidemic := nextEpidemicTurn(gs)
+	when := "this turn's"
+	if epidemic == 1 {
+		when = "next turn's"
+	}
+	fmt.Fprint(out, t("With an epidemic in %v city cards, at rate %v after it:\n", when, gs.NextInfectionRate()))
+	for _, forecast := range gs.ForecastPhases(phases, epidemic) {
+		fmt.Fprintf(out, "  %v: %v\n", phaseTurn(forecast.Turn), phaseDraws(forecast))
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+)
+
+const plainThreatCount = 5
+
+// RunPlain is a line based alternative to Start for screen readers and for
+// piping to a file. Commands are read one per line and every command is
+// followed by the labeled facts about the game that it changed, without any
+// layout or color. "status" repeats every fact.
+func (p *PandemicView) RunPlain(game *pandemic.GameState, in io.Reader, out io.Writer) {
+	plain := func(format string, args ...interface{}) string {
+		return fmt.Sprintf(format, args...)
+	}
+	p.colorWhiteHighlight, p.colorAllGood, p.colorWarning, p.colorHighlight, p.colorOhFuck = plain, plain, plain, plain, plain
+
+	facts := describe(game)
+	fmt.Fprintln(out, strings.Join(facts, "\n"))
+	scanner := bufio.NewScanner(in)
+	fmt.Fprintln(out, p.t("Command:"))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch line {
+		case "quit", "exit":
+			return
+		case "status":
+			fmt.Fprintln(out, strings.Join(facts, "\n"))
+		case "":
+		default:
+			if err := p.execute(game, out, line); err != nil {
+				fmt.Fprint(out, p.t("Error: %v\n", err))
+			}
+			previous := map[string]bool{}
+			for _, fact := range facts {
+				previous[fact] = true
+			}
+			facts = describe(game)
+			for _, fact := range facts {
+				if !previous[fact] {
+					fmt.Fprintln(out, fact)
+				}
+			}
+		}
+		fmt.Fprintln(out, p.t("Command:"))
+	}
+}
+
+// describe lists the state of the game as one labeled line per fact.
+func describe(game *pandemic.GameState) []string {
+	facts := []string{}
+	if turn, err := game.GameTurns.CurrentTurn(); err == nil {
+		facts = append(facts, fmt.Sprintf("Turn: %v, %v phase.", turn.Player.HumanName, turn.Phase()))
+	}
+	analysis := game.CityDeck.EpidemicAnalysis()
+	epidemic := analysis.FirstCardProbability + analysis.SecondCardProbability
+	facts = append(facts, fmt.Sprintf("Epidemic chance this turn: %.0f percent.", epidemic*100))
+	facts = append(facts, fmt.Sprintf("Infection rate: %v. Outbreaks: %v.", game.InfectionRate, game.Outbreaks))
+
+	threats := []string{}
+	cities := game.SortByProbability(game.InfectionDeck.CitiesInStriation(0))
+	for i := 0; i < len(cities) && len(threats) < plainThreatCount; i++ {
+		city, err := game.GetCity(cities[i])
+		if err != nil {
+			continue
+		}
+		threats = append(threats, fmt.Sprintf("%v %.0f percent with %v cubes", city.Name, game.ProbabilityOfCity(city.Name)*100, city.NumInfections))
+	}
+	if len(threats) > 0 {
+		facts = append(facts, fmt.Sprintf("Most likely infections: %v.", strings.Join(threats, "; ")))
+	}
+
+	diseases := pandemic.CurableDiseases()
+	sort.Sort(byCurability{dts: diseases})
+	for _, dt := range diseases {
+		progress := game.CureProgress(dt)
+		if progress.Curer == nil {
+			continue
+		}
+		facts = append(facts, fmt.Sprintf("%v cure: team holds %v of %v cards, %v holds %v.", dt, progress.TeamHolds, progress.Needed, progress.Curer.HumanName, progress.CurerHolds))
+	}
+	return facts
+}
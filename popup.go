@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+	"github.com/jroimartin/gocui"
+)
+
+const cityPopupDuration = 4 * time.Second
+
+// cityPopup shows a city's key numbers for a moment next to where it was
+// picked, leaving the command box focused.
+type cityPopup struct {
+	city  pandemic.CityName
+	x, y  int
+	until time.Time
+}
+
+// showCityPopup puts up the popup for the city at x, y on the screen.
+func (p *PandemicView) showCityPopup(city pandemic.CityName, x, y int) {
+	p.popup = &cityPopup{city: city, x: x, y: y, until: time.Now().Add(cityPopupDuration)}
+	if p.redraw != nil {
+		time.AfterFunc(cityPopupDuration, p.redraw)
+	}
+}
+
+// pickCity is the mouse handler for the panels: it shows the popup for the
+// city on the line that was clicked, if there is one.
+func (p *PandemicView) pickCity(game *pandemic.GameState) func(*gocui.Gui, *gocui.View) error {
+	return func(gui *gocui.Gui, view *gocui.View) error {
+		if view == nil || view.Name() == "Commands" {
+			return nil
+		}
+		_, y := view.Cursor()
+		line, err := view.Line(y)
+		if err != nil {
+			return nil
+		}
+		city, ok := p.cityInLine(game, line)
+		if !ok {
+			return nil
+		}
+		x0, y0, _, _, err := gui.ViewPosition(view.Name())
+		if err != nil {
+			return nil
+		}
+		p.showCityPopup(city, x0+1, y0+y+2)
+		return nil
+	}
+}
+
+// typedCityStats shows the popup for the city being typed in the command
+// box, the same way tab completes it.
+func (p *PandemicView) typedCityStats(game *pandemic.GameState) func(*gocui.Gui, *gocui.View) error {
+	return func(gui *gocui.Gui, view *gocui.View) error {
+		words := strings.Fields(view.Buffer())
+		if len(words) == 0 {
+			return nil
+		}
+		city, err := getCityByPrefix(words[len(words)-1], game)
+		if err != nil {
+			return nil
+		}
+		p.showCityPopup(city, 0, 3)
+		return nil
+	}
+}
+
+// cityInLine finds the city a panel line is about by its label, preferring
+// the longest label so that a full name beats the four letter one.
+func (p *PandemicView) cityInLine(game *pandemic.GameState, line string) (pandemic.CityName, bool) {
+	var found pandemic.CityName
+	longest := 0
+	for _, city := range game.Cities.CityNames() {
+		for _, label := range []string{p.cityLabel(city), p.shortCityLabel(city)} {
+			if len(label) > longest && strings.Contains(line, label) {
+				found = city
+				longest = len(label)
+			}
+		}
+	}
+	return found, longest > 0
+}
+
+func (p *PandemicView) cityStatsLines(game *pandemic.GameState, cn pandemic.CityName) []string {
+	city, err := game.GetCity(cn)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	atThree := []string{}
+	for _, neighbor := range city.Neighbors {
+		data, err := game.GetCity(pandemic.CityName(neighbor))
+		if err == nil && data.NumInfections == pandemic.MaxInfections {
+			atThree = append(atThree, p.cityLabel(data.Name))
+		}
+	}
+	if len(atThree) == 0 {
+		atThree = append(atThree, "none")
+	}
+	lines := []string{
+		p.t("Cubes %v (%v)", city.NumInfections, city.Disease),
+		p.t("Infected next turn %.3f", game.ProbabilityOfCity(cn)),
+		p.t("Panic %v", city.PanicLevel),
+		p.t("Neighbors at %v: %v", pandemic.MaxInfections, strings.Join(atThree, ", ")),
+	}
+	if player := game.ProtectedBy(cn); player != nil {
+		lines = append(lines, p.t("Protected by %v's %v", player.HumanName, player.Character.Type))
+	}
+	pawns := []string{}
+	for _, player := range game.GameTurns.PlayerOrder {
+		if player.Location == cn {
+			pawns = append(pawns, player.HumanName)
+		}
+	}
+	if len(pawns) > 0 {
+		lines = append(lines, p.t("Pawns %v", strings.Join(pawns, ", ")))
+	}
+	return lines
+}
+
+func (p *PandemicView) renderCityPopup(game *pandemic.GameState, gui *gocui.Gui, width, height int) {
+	if p.popup == nil || time.Now().After(p.popup.until) {
+		p.popup = nil
+		gui.DeleteView("City")
+		return
+	}
+	lines := p.cityStatsLines(game, p.popup.city)
+	wide := 0
+	for _, line := range lines {
+		if len(line) > wide {
+			wide = len(line)
+		}
+	}
+	x0, y0 := p.popup.x, p.popup.y
+	if x0+wide+2 >= width {
+		x0 = width - wide - 3
+	}
+	if y0+len(lines)+1 >= height {
+		y0 = height - len(lines) - 2
+	}
+	if x0 < 0 {
+		x0 = 0
+	}
+	if y0 < 0 {
+		y0 = 0
+	}
+	view, err := gui.SetView("City", x0, y0, x0+wide+1, y0+len(lines)+1)
+	if err != nil && err != gocui.ErrUnknownView {
+		p.logger.Errorf("Could not show the city popup: %v", err)
+		return
+	}
+	view.Clear()
+	view.Title = p.cityLabel(p.popup.city)
+	for _, line := range lines {
+		fmt.Fprintln(view, line)
+	}
+}
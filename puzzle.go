@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+)
+
+const puzzleTrials = 1000
+
+// RunPuzzle loads a crafted game state and lets the team try plans against
+// it. Plan steps are ordinary commands played on a copy of the puzzle;
+// "go" then simulates the next infection phase and scores the outcomes,
+// and "reset" starts over from the puzzle.
+func (p *PandemicView) RunPuzzle(puzzle *pandemic.GameState, in io.Reader, out io.Writer) error {
+	p.sandbox = true
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	plan, err := puzzle.Clone()
+	if err != nil {
+		return err
+	}
+	p.subscribe(plan)
+	fmt.Fprintln(out, strings.Join(describe(puzzle), "\n"))
+	fmt.Fprintln(out, p.t("Enter your plan one command at a time, then go, reset or quit."))
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch line {
+		case "":
+		case "quit", "exit":
+			return nil
+		case "reset":
+			if plan, err = puzzle.Clone(); err != nil {
+				return err
+			}
+			p.subscribe(plan)
+			fmt.Fprintln(out, p.t("Back to the start of the puzzle"))
+		case "go":
+			outcomes, err := plan.SimulateInfectPhase(puzzleTrials, rng)
+			if err != nil {
+				return err
+			}
+			p.printOutcomes(out, outcomes)
+		default:
+			if err := p.execute(plan, out, line); err != nil {
+				fmt.Fprint(out, p.t("Error: %v\n", err))
+			}
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+	"github.com/jroimartin/gocui"
+)
+
+const rewindCitiesShown = 8
+
+// rewindPanel steps through the game's saves, one per command, to show the
+// board and the odds as they were at any point. The saves are only read, so
+// the game in play is untouched and esc returns to it.
+type rewindPanel struct {
+	saves []string
+	at    int
+	games map[int]*pandemic.GameState
+}
+
+// gameSaves lists the saves in the game's folder, oldest first.
+func gameSaves(dir string) []string {
+	paths, _ := filepath.Glob(filepath.Join(dir, "game_*_*.json"))
+	sort.Sort(bySaveNanos(paths))
+	return paths
+}
+
+func saveNanos(path string) int64 {
+	parts := strings.SplitN(filepath.Base(path), "_", 3)
+	if len(parts) != 3 {
+		return 0
+	}
+	nanos, _ := strconv.ParseInt(parts[1], 10, 64)
+	return nanos
+}
+
+type bySaveNanos []string
+
+func (b bySaveNanos) Len() int           { return len(b) }
+func (b bySaveNanos) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b bySaveNanos) Less(i, j int) bool { return saveNanos(b[i]) < saveNanos(b[j]) }
+
+// openRewind opens the panel on the latest save.
+func (p *PandemicView) openRewind(game *pandemic.GameState) error {
+	if p.redraw == nil {
+		return errors.New(p.t("Rewind needs the full screen, it can't run in plain mode"))
+	}
+	saves := gameSaves(filepath.Join(p.config.SaveDir, game.GameName))
+	if len(saves) == 0 {
+		return errors.New(p.t("No saves of %v to rewind through", game.GameName))
+	}
+	p.rewind = &rewindPanel{saves: saves, at: len(saves) - 1, games: map[int]*pandemic.GameState{}}
+	return nil
+}
+
+func (r *rewindPanel) game(at int) (*pandemic.GameState, error) {
+	if game, ok := r.games[at]; ok {
+		return game, nil
+	}
+	game, err := loadSave(r.saves[at])
+	if err != nil {
+		return nil, err
+	}
+	r.games[at] = game
+	return game, nil
+}
+
+// command is what was entered to make the save.
+func (r *rewindPanel) command(at int) string {
+	parts := strings.SplitN(filepath.Base(r.saves[at]), "_", 3)
+	if len(parts) != 3 {
+		return "?"
+	}
+	return strings.TrimSuffix(parts[2], ".json")
+}
+
+func (p *PandemicView) stepRewind(steps int) func(*gocui.Gui, *gocui.View) error {
+	return func(*gocui.Gui, *gocui.View) error {
+		if p.rewind == nil {
+			return nil
+		}
+		at := p.rewind.at + steps
+		if at < 0 {
+			at = 0
+		}
+		if at >= len(p.rewind.saves) {
+			at = len(p.rewind.saves) - 1
+		}
+		p.rewind.at = at
+		return nil
+	}
+}
+
+func (p *PandemicView) closeRewind(*gocui.Gui, *gocui.View) error {
+	p.rewind = nil
+	return nil
+}
+
+func (p *PandemicView) renderRewind(gui *gocui.Gui, width, height int) {
+	if p.rewind == nil {
+		gui.DeleteView("Rewind")
+		return
+	}
+	view, err := gui.SetView("Rewind", width/8, height/8, width*7/8, height*7/8)
+	if err != nil && err != gocui.ErrUnknownView {
+		p.logger.Errorf("Could not show the rewind panel: %v", err)
+		return
+	}
+	view.Clear()
+	view.Wrap = true
+	r := p.rewind
+	view.Title = p.t("Rewind: save %v of %v (%v/%v to step, esc to return to now)", r.at+1, len(r.saves), p.config.Keys.Back, p.config.Keys.Forward)
+	game, err := r.game(r.at)
+	if err != nil {
+		fmt.Fprintln(view, p.colorWarning("%v", err))
+		return
+	}
+	p.printRewind(view, game, r)
+}
+
+// printRewind shows one save: when it was made and by what, the events
+// that command logged, and the board and odds right after it.
+func (p *PandemicView) printRewind(out io.Writer, game *pandemic.GameState, r *rewindPanel) {
+	when := time.Unix(0, saveNanos(r.saves[r.at])).Format("15:04:05")
+	fmt.Fprint(out, p.t("After %v at %v\n", r.command(r.at), when))
+	if turn, err := game.GameTurns.CurrentTurn(); err == nil {
+		fmt.Fprint(out, p.t("Turn %v, %v; %v outbreaks, infection rate %v\n", game.GameTurns.CurTurn+1, turn.Player.HumanName, game.Outbreaks, game.InfectionRate))
+	}
+
+	logged := 0
+	if r.at > 0 {
+		if before, err := r.game(r.at - 1); err == nil && before.Log != nil {
+			logged = len(before.Log.Events)
+		}
+	}
+	if game.Log != nil && logged < len(game.Log.Events) {
+		fmt.Fprintln(out, p.t("\nLogged"))
+		for _, event := range game.Log.Events[logged:] {
+			fmt.Fprintf(out, "  %v\n", event.Message)
+		}
+	}
+
+	fmt.Fprintln(out, p.t("\nMost likely infected next"))
+	for i, city := range game.SortByProbability(game.Cities.CityNames()) {
+		if i == rewindCitiesShown {
+			break
+		}
+		data, err := game.GetCity(city)
+		if err != nil {
+			continue
+		}
+		fmt.Fprint(out, p.t("  %v %v cubes %.2f\n", p.cityLabel(city), data.NumInfections, game.ProbabilityOfCity(city)))
+	}
+}
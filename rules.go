@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+	"github.com/jroimartin/gocui"
+)
+
+const rulesFile = "data/rules.json"
+
+// rulesOverlay shows the rules matching a lookup until it is closed, and
+// can be scrolled since an entry may not fit on screen.
+type rulesOverlay struct {
+	query   string
+	entries []pandemic.RuleEntry
+}
+
+// lookUpRules lists the topics in play, or with a topic opens the overlay
+// on the entries about it.
+func (p *PandemicView) lookUpRules(out io.Writer, game *pandemic.GameState, args []string) error {
+	entries, err := pandemic.LoadRuleEntries(rulesFile)
+	if err != nil {
+		return err
+	}
+	entries = game.RulesInPlay(entries)
+	if len(args) == 0 {
+		topics := []string{}
+		for _, entry := range entries {
+			topics = append(topics, entry.Topic)
+		}
+		fmt.Fprint(out, p.t("Rules for this game: %v\n", strings.Join(topics, ", ")))
+		return nil
+	}
+	query := strings.Join(args, " ")
+	found := pandemic.SearchRules(entries, query)
+	if len(found) == 0 {
+		return errors.New(p.t("No rules about %q in this game", query))
+	}
+	if p.redraw == nil {
+		// no overlay outside the GUI, e.g. in plain mode
+		printRules(out, found)
+		return nil
+	}
+	p.rules = &rulesOverlay{query: query, entries: found}
+	return nil
+}
+
+func printRules(out io.Writer, entries []pandemic.RuleEntry) {
+	for _, entry := range entries {
+		fmt.Fprintln(out, entry.Topic)
+		for _, paragraph := range entry.Text {
+			fmt.Fprintln(out, paragraph)
+		}
+		fmt.Fprintln(out)
+	}
+}
+
+func (p *PandemicView) renderRules(gui *gocui.Gui, width, height int) {
+	if p.rules == nil {
+		gui.DeleteView("Rules")
+		return
+	}
+	view, err := gui.SetView("Rules", width/8, height/8, width*7/8, height*7/8)
+	if err != nil && err != gocui.ErrUnknownView {
+		p.logger.Errorf("Could not show the rules: %v", err)
+		return
+	}
+	view.Clear()
+	view.Wrap = true
+	view.Title = p.t("Rules: %v (pgup/pgdn to scroll, esc to close)", p.rules.query)
+	printRules(view, p.rules.entries)
+}
+
+func (p *PandemicView) scrollRules(lines int) func(*gocui.Gui, *gocui.View) error {
+	return func(gui *gocui.Gui, _ *gocui.View) error {
+		view, err := gui.View("Rules")
+		if err != nil {
+			return nil
+		}
+		x, y := view.Origin()
+		if y+lines < 0 {
+			lines = -y
+		}
+		return view.SetOrigin(x, y+lines)
+	}
+}
+
+func (p *PandemicView) closeRules(gui *gocui.Gui, _ *gocui.View) error {
+	p.rules = nil
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+)
+
+const setupUsage = "Usage: setup | setup infect <9 city prefixes in draw order> | setup deal <human-prefix> <card prefixes>"
+
+// setup records the table's setup before the first turn: the initial
+// infection and the starting hands. With no arguments it lists what is left
+// to do and the epidemic piles the deck is split into.
+func (p *PandemicView) setup(out io.Writer, gs *pandemic.GameState, args []string) error {
+	if len(args) == 0 {
+		p.printSetup(out, gs)
+		return nil
+	}
+	switch args[0] {
+	case "infect":
+		cities := []pandemic.CityName{}
+		for _, prefix := range args[1:] {
+			city, err := getCityByPrefix(prefix, gs)
+			if err != nil {
+				return err
+			}
+			cities = append(cities, city)
+		}
+		if err := gs.InitialInfection(cities); err != nil {
+			return err
+		}
+		for i, city := range cities {
+			fmt.Fprint(out, p.t("%v %v cubes\n", p.cityLabel(city), pandemic.InitialInfectionCubes[i]))
+		}
+	case "deal":
+		if len(args) < 2 {
+			return fmt.Errorf("%v", setupUsage)
+		}
+		player, err := getPlayerByPrefix(args[1], gs)
+		if err != nil {
+			return err
+		}
+		if player == nil {
+			return errors.New(p.t("No player's name starts with %v", args[1]))
+		}
+		cards := []pandemic.CardName{}
+		for _, prefix := range args[2:] {
+			card, err := getCardByPrefix(prefix, gs)
+			if err != nil {
+				return err
+			}
+			cards = append(cards, card)
+		}
+		if err := gs.DealStartingHand(player, cards); err != nil {
+			return err
+		}
+		labels := []string{}
+		for _, card := range cards {
+			labels = append(labels, p.cityLabel(pandemic.CityName(card)))
+		}
+		fmt.Fprint(out, p.t("Dealt %v %v\n", player.HumanName, strings.Join(labels, ", ")))
+	default:
+		return fmt.Errorf("%v", setupUsage)
+	}
+	p.printSetup(out, gs)
+	return nil
+}
+
+func (p *PandemicView) printSetup(out io.Writer, gs *pandemic.GameState) {
+	for _, left := range gs.SetupLeft() {
+		fmt.Fprint(out, p.t("Still to %v\n", left))
+	}
+	piles := []string{}
+	for _, size := range gs.EpidemicPiles() {
+		piles = append(piles, fmt.Sprint(size))
+	}
+	fmt.Fprint(out, p.t("Shuffle one epidemic into each of %v piles, making piles of %v cards, and stack them\n", len(piles), strings.Join(piles, ", ")))
+	fmt.Fprint(out, p.t("The first city card has a %.1f%% chance of being an epidemic\n", gs.CityDeck.EpidemicAnalysis().FirstCardProbability*100))
+}
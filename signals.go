@@ -0,0 +1,48 @@
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+	"github.com/jroimartin/gocui"
+	"github.com/nsf/termbox-go"
+)
+
+// handleSignals keeps the terminal usable when the tracker is suspended and
+// saves the game before it is killed. Everything runs on the GUI's main
+// loop so it never races a command that is halfway through.
+func (p *PandemicView) handleSignals(game *pandemic.GameState, gui *gocui.Gui) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTSTP, syscall.SIGTERM)
+	go func() {
+		for sig := range signals {
+			switch sig {
+			case syscall.SIGTSTP:
+				gui.Execute(func(gui *gocui.Gui) error {
+					// hand the terminal back, then stop for real. Kill
+					// returns once the shell continues us with SIGCONT.
+					termbox.Close()
+					signal.Reset(syscall.SIGTSTP)
+					syscall.Kill(os.Getpid(), syscall.SIGTSTP)
+					signal.Notify(signals, syscall.SIGTSTP)
+					if err := termbox.Init(); err != nil {
+						return err
+					}
+					return termbox.Sync()
+				})
+			case syscall.SIGTERM:
+				gui.Execute(func(gui *gocui.Gui) error {
+					if err := p.saveGame(game, "sigterm"); err != nil {
+						p.logger.Errorf("Could not save before exiting: %v", err)
+					}
+					return gocui.ErrQuit
+				})
+			}
+		}
+	}()
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+	"github.com/jroimartin/gocui"
+)
+
+// handleSignals saves the game before the tracker is killed. Windows has no
+// job control, so there is no suspend to handle.
+func (p *PandemicView) handleSignals(game *pandemic.GameState, gui *gocui.Gui) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM)
+	go func() {
+		for range signals {
+			gui.Execute(func(gui *gocui.Gui) error {
+				if err := p.saveGame(game, "sigterm"); err != nil {
+					p.logger.Errorf("Could not save before exiting: %v", err)
+				}
+				return gocui.ErrQuit
+			})
+		}
+	}()
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+	"github.com/jroimartin/gocui"
+)
+
+const (
+	simulateTrials = 10000
+	// simulateTolerance stops a simulation once the expected outbreaks are
+	// known to within this either side.
+	simulateTolerance = 0.01
+)
+
+// simulation is a simulate command running in the background, so that the
+// GUI keeps taking commands while it works.
+type simulation struct {
+	sync.Mutex
+	done, total int
+	revision    int
+	outcomes    pandemic.InfectOutcomes
+	err         error
+	finished    bool
+}
+
+// simulationCache keeps the last default simulation so that panels can show
+// it on every render, only simulating again once a command changes the game.
+type simulationCache struct {
+	sync.Mutex
+	revision int
+	outcomes *pandemic.InfectOutcomes
+	running  bool
+}
+
+func (c *simulationCache) store(revision int, outcomes pandemic.InfectOutcomes) {
+	c.Lock()
+	defer c.Unlock()
+	if c.outcomes == nil || revision >= c.revision {
+		c.revision, c.outcomes = revision, &outcomes
+	}
+}
+
+// cachedOutcomes is the simulation of the game as it is now, if there is one.
+func (p *PandemicView) cachedOutcomes() (pandemic.InfectOutcomes, bool) {
+	p.simulated.Lock()
+	defer p.simulated.Unlock()
+	if p.simulated.outcomes == nil || p.simulated.revision != p.revision {
+		return pandemic.InfectOutcomes{}, false
+	}
+	return *p.simulated.outcomes, true
+}
+
+// panelOutcomes gives panels the latest simulation, starting a new one in
+// the background when the game has changed since. Until it finishes the
+// previous outcomes are returned, marked stale, so rendering never waits.
+func (p *PandemicView) panelOutcomes(game *pandemic.GameState) (outcomes *pandemic.InfectOutcomes, fresh bool) {
+	cache := &p.simulated
+	cache.Lock()
+	defer cache.Unlock()
+	if cache.outcomes != nil && cache.revision == p.revision {
+		return cache.outcomes, true
+	}
+	if !cache.running && p.redraw != nil {
+		clone, err := game.Clone()
+		if err != nil {
+			p.logger.Errorf("Could not simulate the game: %v", err)
+			return cache.outcomes, false
+		}
+		cache.running = true
+		revision := p.revision
+		go func() {
+			outcomes, err := clone.SimulateInfectPhaseParallel(p.defaultSimulation(simulateTrials, nil))
+			if err != nil {
+				p.logger.Errorf("Could not simulate the game: %v", err)
+			} else {
+				cache.store(revision, outcomes)
+			}
+			cache.Lock()
+			cache.running = false
+			cache.Unlock()
+			p.redraw()
+		}()
+	}
+	return cache.outcomes, false
+}
+
+func (p *PandemicView) defaultSimulation(trials int, progress func(done, total int)) pandemic.SimulateOptions {
+	return pandemic.SimulateOptions{
+		Trials:    trials,
+		Seed:      time.Now().UnixNano(),
+		Tolerance: simulateTolerance,
+		Progress:  progress,
+	}
+}
+
+// simulate plays the next infection phase many times on a copy of the game.
+// In the GUI it runs in the background, showing its progress, and prints
+// the outcomes to the console when it is done.
+func (p *PandemicView) simulate(out io.Writer, gameState *pandemic.GameState, args []string) error {
+	trials := simulateTrials
+	if len(args) > 0 {
+		var err error
+		if trials, err = strconv.Atoi(args[0]); err != nil || trials <= 0 {
+			return errors.New(p.t("Usage: simulate [playouts]"))
+		}
+	}
+	if p.simulation != nil {
+		return errors.New(p.t("A simulation is already running"))
+	}
+	if outcomes, ok := p.cachedOutcomes(); ok && trials == simulateTrials {
+		p.printOutcomes(out, outcomes)
+		return nil
+	}
+	// later commands change the game, so the simulation gets its own copy
+	game, err := gameState.Clone()
+	if err != nil {
+		return err
+	}
+	run := &simulation{total: trials, revision: p.revision}
+	opts := p.defaultSimulation(trials, func(done, total int) {
+		run.Lock()
+		run.done = done
+		run.Unlock()
+		if p.redraw != nil {
+			p.redraw()
+		}
+	})
+	if p.redraw == nil {
+		outcomes, err := game.SimulateInfectPhaseParallel(opts)
+		if err != nil {
+			return err
+		}
+		p.printOutcomes(out, outcomes)
+		return nil
+	}
+	p.simulation = run
+	go func() {
+		outcomes, err := game.SimulateInfectPhaseParallel(opts)
+		if err == nil && trials == simulateTrials {
+			p.simulated.store(run.revision, outcomes)
+		}
+		run.Lock()
+		run.outcomes, run.err, run.finished = outcomes, err, true
+		run.Unlock()
+		p.redraw()
+	}()
+	return nil
+}
+
+func (p *PandemicView) printOutcomes(out io.Writer, outcomes pandemic.InfectOutcomes) {
+	if p.config.risk().WorstCase {
+		fmt.Fprint(out, p.t("Worst case %v outbreaks, expected %.2f\n", outcomes.WorstOutbreaks(), outcomes.ExpectedOutbreaks()))
+	} else {
+		fmt.Fprint(out, p.t("Expected outbreaks %.2f (±%.2f), worst case %v\n", outcomes.ExpectedOutbreaks(), outcomes.ConfidenceInterval(), outcomes.WorstOutbreaks()))
+	}
+	fmt.Fprint(out, p.t("No outbreaks in %.0f%% of %v infection phases\n", outcomes.ProbabilityOfNoOutbreaks()*100, outcomes.Trials))
+	fmt.Fprint(out, p.t("Expected cubes placed %.2f\n", outcomes.ExpectedCubes()))
+	if heaviest := heaviestDraws(outcomes); len(heaviest) > 0 {
+		parts := []string{}
+		for _, city := range heaviest {
+			parts = append(parts, fmt.Sprintf("%v +%.2f", p.shortCityLabel(city), outcomes.ExpectedCubesFrom(city)))
+		}
+		fmt.Fprint(out, p.t("Most cubes expected from %v\n", strings.Join(parts, ", ")))
+	}
+}
+
+const heaviestDrawsShown = 3
+
+// heaviestDraws are the cities whose infection cards are expected to put the
+// most cubes on the board, outbreaks included.
+func heaviestDraws(outcomes pandemic.InfectOutcomes) []pandemic.CityName {
+	cities := []pandemic.CityName{}
+	for city, cubes := range outcomes.CityCubes {
+		if cubes > 0 {
+			cities = append(cities, city)
+		}
+	}
+	sort.Sort(byCubes{cities, outcomes.CityCubes})
+	if len(cities) > heaviestDrawsShown {
+		cities = cities[:heaviestDrawsShown]
+	}
+	return cities
+}
+
+type byCubes struct {
+	cities []pandemic.CityName
+	cubes  map[pandemic.CityName]int
+}
+
+func (b byCubes) Len() int      { return len(b.cities) }
+func (b byCubes) Swap(i, j int) { b.cities[i], b.cities[j] = b.cities[j], b.cities[i] }
+func (b byCubes) Less(i, j int) bool {
+	if b.cubes[b.cities[i]] != b.cubes[b.cities[j]] {
+		return b.cubes[b.cities[i]] > b.cubes[b.cities[j]]
+	}
+	return b.cities[i] < b.cities[j]
+}
+
+// simulatedOutbreaks sums up the latest simulation for the city deck panel.
+func (p *PandemicView) simulatedOutbreaks(game *pandemic.GameState) string {
+	outcomes, fresh := p.panelOutcomes(game)
+	if outcomes == nil {
+		return "Simulated outbreaks: simulating..."
+	}
+	line := p.t("Simulated outbreaks: %.2f", outcomes.ExpectedOutbreaks())
+	if city, ok := outcomes.RiskiestCity(); ok {
+		line += p.t(", riskiest %v %.0f%%", p.shortCityLabel(city), outcomes.ProbabilityOfOutbreak(city)*100)
+	}
+	if !fresh {
+		line += " (updating)"
+	}
+	return line
+}
+
+// renderSimulation shows the progress of a background simulation, and
+// prints its outcomes to the console once it finishes.
+func (p *PandemicView) renderSimulation(gui *gocui.Gui, width, height int) {
+	run := p.simulation
+	if run == nil {
+		return
+	}
+	run.Lock()
+	defer run.Unlock()
+	if run.finished {
+		p.simulation = nil
+		gui.DeleteView("Simulation")
+		console, err := gui.View("Console")
+		if err != nil {
+			return
+		}
+		if run.err != nil {
+			fmt.Fprintln(console, p.colorWarning("%v", p.t("Simulation failed: %v", run.err)))
+			return
+		}
+		p.printOutcomes(console, run.outcomes)
+		return
+	}
+	line := p.t("Simulating %v/%v playouts", run.done, run.total)
+	view, err := gui.SetView("Simulation", width-len(line)-3, height-3, width-1, height-1)
+	if err != nil && err != gocui.ErrUnknownView {
+		p.logger.Errorf("Could not show the simulation progress: %v", err)
+		return
+	}
+	view.Clear()
+	fmt.Fprint(view, line)
+}
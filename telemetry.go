@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+)
+
+const calibrationFile = "calibration.json"
+
+// Telemetry is opt in. When enabled the tracker tallies how often its
+// predictions came true, and telemetry send shares the tallies with the
+// endpoint. Nothing is sent without the command being typed.
+type Telemetry struct {
+	Enabled  bool   `json:"enabled"`
+	Endpoint string `json:"endpoint"`
+}
+
+func (p *PandemicView) calibrationPath() string {
+	return filepath.Join(p.config.SaveDir, calibrationFile)
+}
+
+// predict tallies predictions about the state before a draw is entered, to
+// be kept with keepPrediction once the draw succeeds. It returns nil when
+// telemetry is off or the game is a sandbox.
+func (p *PandemicView) predict(record func(*pandemic.Calibration)) *pandemic.Calibration {
+	if !p.config.Telemetry.Enabled || p.sandbox {
+		return nil
+	}
+	pending := &pandemic.Calibration{}
+	record(pending)
+	return pending
+}
+
+func (p *PandemicView) keepPrediction(pending *pandemic.Calibration) {
+	if pending == nil {
+		return
+	}
+	p.calibrationMu.Lock()
+	defer p.calibrationMu.Unlock()
+	calibration, err := pandemic.LoadCalibration(p.calibrationPath())
+	if err != nil {
+		p.logger.Errorf("Could not read the calibration tallies: %v", err)
+		return
+	}
+	calibration.Add(pending)
+	if err := calibration.Save(p.calibrationPath()); err != nil {
+		p.logger.Errorf("Could not save the calibration tallies: %v", err)
+	}
+}
+
+// telemetry shows exactly what would be shared, or with send, shares it
+// and takes what was sent out of the tallies. In the GUI the data is sent in
+// the background, so a slow endpoint doesn't hold up the table, and anything
+// tallied meanwhile is kept.
+func (p *PandemicView) telemetry(out io.Writer, args []string) error {
+	p.calibrationMu.Lock()
+	calibration, err := pandemic.LoadCalibration(p.calibrationPath())
+	p.calibrationMu.Unlock()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(calibration, "", "  ")
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		status := "off"
+		if p.config.Telemetry.Enabled {
+			status = "on"
+		}
+		fmt.Fprint(out, p.t("Telemetry is %v, %v predictions tallied. This is everything that would be sent:\n%s\n", status, calibration.Predictions(), data))
+		return nil
+	}
+	if len(args) != 1 || args[0] != "send" {
+		return errors.New(p.t("Usage: telemetry [send]"))
+	}
+	if !p.config.Telemetry.Enabled {
+		return errors.New(p.t("Telemetry is off, set telemetry.enabled in your config to share calibration data"))
+	}
+	if p.config.Telemetry.Endpoint == "" {
+		return errors.New(p.t("No telemetry endpoint is configured, set telemetry.endpoint in your config"))
+	}
+	if calibration.Predictions() == 0 {
+		return errors.New(p.t("There is nothing to send yet"))
+	}
+	sent := func(err error) error {
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(out, p.t("Sent %v predictions\n", calibration.Predictions()))
+		p.calibrationMu.Lock()
+		defer p.calibrationMu.Unlock()
+		current, err := pandemic.LoadCalibration(p.calibrationPath())
+		if err != nil {
+			return err
+		}
+		current.Subtract(calibration)
+		return current.Save(p.calibrationPath())
+	}
+	if p.inGUI == nil {
+		return sent(sendTelemetry(p.config.Telemetry.Endpoint, data))
+	}
+	fmt.Fprint(out, p.t("Sending %v predictions\n", calibration.Predictions()))
+	go func() {
+		err := sendTelemetry(p.config.Telemetry.Endpoint, data)
+		p.inGUI(func() {
+			if err := sent(err); err != nil {
+				fmt.Fprintln(out, p.colorWarning("%v", err))
+			}
+		})
+	}()
+	return nil
+}
+
+func sendTelemetry(endpoint string, data []byte) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("Could not send calibration data: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("The telemetry endpoint refused the data: %v", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The config files are TOML, but only the small part of it settings need:
+// [tables] and [nested.tables], key = value lines, and values that are
+// strings, numbers, booleans or arrays of them on one line. Like the
+// infection deck's YAML this is parsed by hand rather than pulling in a
+// library. A parsed file is turned into JSON so it can be layered onto the
+// Config the same way as the campaign file's overrides.
+
+// tomlToJSON parses a config file and returns the same settings as JSON.
+func tomlToJSON(data []byte) ([]byte, error) {
+	settings := map[string]interface{}{}
+	table := settings
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(stripTOMLComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+		var err error
+		if strings.HasPrefix(line, "[") {
+			table, err = tomlTable(settings, line)
+		} else {
+			err = tomlSetting(table, line)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Line %v: %v", lineNo, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(settings)
+}
+
+// stripTOMLComment drops a trailing comment, leaving any # inside a string.
+func stripTOMLComment(line string) string {
+	var quote rune
+	escaped := false
+	for i, c := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && c == '\\':
+			escaped = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// tomlTable returns the table a [header] line opens, making it and the
+// tables it is nested in as needed.
+func tomlTable(settings map[string]interface{}, line string) (map[string]interface{}, error) {
+	if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+		return nil, fmt.Errorf("Unexpected %q", line)
+	}
+	table := settings
+	for _, key := range strings.Split(line[1:len(line)-1], ".") {
+		key, err := tomlKey(key)
+		if err != nil {
+			return nil, err
+		}
+		next, ok := table[key]
+		if !ok {
+			next = map[string]interface{}{}
+			table[key] = next
+		}
+		if table, ok = next.(map[string]interface{}); !ok {
+			return nil, fmt.Errorf("%v is already set to a value", key)
+		}
+	}
+	return table, nil
+}
+
+func tomlSetting(table map[string]interface{}, line string) error {
+	eq := strings.Index(line, "=")
+	if eq < 0 {
+		return fmt.Errorf("Expected key = value, got %q", line)
+	}
+	key, err := tomlKey(line[:eq])
+	if err != nil {
+		return err
+	}
+	if _, ok := table[key]; ok {
+		return fmt.Errorf("%v is set twice", key)
+	}
+	value, rest, err := tomlValue(strings.TrimSpace(line[eq+1:]))
+	if err != nil {
+		return fmt.Errorf("%v: %v", key, err)
+	}
+	if strings.TrimSpace(rest) != "" {
+		return fmt.Errorf("%v: unexpected %q after the value", key, rest)
+	}
+	table[key] = value
+	return nil
+}
+
+func tomlKey(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	if strings.HasPrefix(key, `"`) {
+		value, rest, err := tomlString(key)
+		if err == nil && rest != "" {
+			err = fmt.Errorf("Unexpected %q after the key", rest)
+		}
+		return value, err
+	}
+	if key == "" {
+		return "", fmt.Errorf("Missing key")
+	}
+	for _, c := range key {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			return "", fmt.Errorf("Invalid key %q, quote it", key)
+		}
+	}
+	return key, nil
+}
+
+// tomlValue parses the value at the start of s and returns what follows it.
+func tomlValue(s string) (interface{}, string, error) {
+	switch {
+	case s == "":
+		return nil, "", fmt.Errorf("Missing value")
+	case s[0] == '"' || s[0] == '\'':
+		return tomlString(s)
+	case s[0] == '[':
+		return tomlArray(s)
+	}
+	end := strings.IndexAny(s, ", ]\t")
+	if end < 0 {
+		end = len(s)
+	}
+	word, rest := s[:end], s[end:]
+	switch word {
+	case "true":
+		return true, rest, nil
+	case "false":
+		return false, rest, nil
+	}
+	number := strings.Replace(word, "_", "", -1)
+	if i, err := strconv.ParseInt(number, 10, 64); err == nil {
+		return i, rest, nil
+	}
+	if f, err := strconv.ParseFloat(number, 64); err == nil {
+		return f, rest, nil
+	}
+	return nil, "", fmt.Errorf("Invalid value %q, quote strings", word)
+}
+
+// tomlString parses a "basic" or 'literal' string at the start of s.
+func tomlString(s string) (string, string, error) {
+	if s[0] == '\'' {
+		end := strings.Index(s[1:], "'")
+		if end < 0 {
+			return "", "", fmt.Errorf("Unterminated string %v", s)
+		}
+		return s[1 : end+1], s[end+2:], nil
+	}
+	var value bytes.Buffer
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return value.String(), s[i+1:], nil
+		case '\\':
+			if i+1 == len(s) {
+				break
+			}
+			i++
+			switch s[i] {
+			case 'n':
+				value.WriteByte('\n')
+			case 't':
+				value.WriteByte('\t')
+			case '"', '\\':
+				value.WriteByte(s[i])
+			default:
+				return "", "", fmt.Errorf("Unsupported escape \\%c", s[i])
+			}
+		default:
+			value.WriteByte(s[i])
+		}
+	}
+	return "", "", fmt.Errorf("Unterminated string %v", s)
+}
+
+func tomlArray(s string) ([]interface{}, string, error) {
+	values := []interface{}{}
+	s = strings.TrimSpace(s[1:])
+	for {
+		if strings.HasPrefix(s, "]") {
+			return values, s[1:], nil
+		}
+		value, rest, err := tomlValue(s)
+		if err != nil {
+			return nil, "", err
+		}
+		values = append(values, value)
+		s = strings.TrimSpace(rest)
+		if strings.HasPrefix(s, ",") {
+			s = strings.TrimSpace(s[1:])
+		} else if !strings.HasPrefix(s, "]") {
+			return nil, "", fmt.Errorf("Unterminated array")
+		}
+	}
+}
+
+// setTOMLSetting sets a top level key = value line in a config file,
+// replacing the key's line if it has one and otherwise adding it above the
+// first table, so the rest of the file, comments included, stays as it was.
+func setTOMLSetting(data []byte, key string, value interface{}) ([]byte, error) {
+	encoded, err := encodeTOML(value)
+	if err != nil {
+		return nil, err
+	}
+	setting := fmt.Sprintf("%v = %v", key, encoded)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(data) == 0 {
+		lines = nil
+	}
+	at := len(lines)
+	for i, line := range lines {
+		line = strings.TrimSpace(stripTOMLComment(line))
+		if strings.HasPrefix(line, "[") {
+			at = i
+			break
+		}
+		if eq := strings.Index(line, "="); eq >= 0 {
+			if name, err := tomlKey(line[:eq]); err == nil && name == key {
+				lines[i] = setting
+				return []byte(strings.Join(lines, "\n") + "\n"), nil
+			}
+		}
+	}
+	lines = append(lines[:at], append([]string{setting}, lines[at:]...)...)
+	return []byte(strings.Join(lines, "\n") + "\n"), nil
+}
+
+func encodeTOML(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case string:
+		return strconv.Quote(value), nil
+	case bool, int, int64, float64:
+		return fmt.Sprint(value), nil
+	case []string:
+		quoted := make([]string, len(value))
+		for i, s := range value {
+			quoted[i] = strconv.Quote(s)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]", nil
+	}
+	return "", fmt.Errorf("Can't write %T to the config file", value)
+}
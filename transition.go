@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
+)
+
+// transitionWizard asks the group, one step at a time, everything that
+// changes between a month's game and the next.
+type transitionWizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func (w transitionWizard) ask(prompt string) string {
+	fmt.Fprint(w.out, prompt)
+	answer, _ := w.in.ReadString('\n')
+	return strings.TrimSpace(answer)
+}
+
+// askLines reads answers until a blank line.
+func (w transitionWizard) askLines(prompt string) []string {
+	fmt.Fprintln(w.out, prompt)
+	lines := []string{}
+	for {
+		line := w.ask("> ")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+// askPairs reads "<name>: <text>" answers until a blank line.
+func (w transitionWizard) askPairs(prompt string) map[string][]string {
+	pairs := map[string][]string{}
+	for _, line := range w.askLines(prompt) {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[1]) == "" {
+			fmt.Fprintf(w.out, "Skipped %q, expected <name>: <text>\n", line)
+			continue
+		}
+		name := strings.TrimSpace(parts[0])
+		pairs[name] = append(pairs[name], strings.TrimSpace(parts[1]))
+	}
+	return pairs
+}
+
+// templatePlayer finds the player the group means by a name or prefix of it.
+func templatePlayer(template pandemic.NewGameSettings, prefix string) (string, error) {
+	for _, player := range template.Players {
+		if strings.HasPrefix(strings.ToLower(player.HumanName), strings.ToLower(prefix)) {
+			return player.HumanName, nil
+		}
+	}
+	return "", fmt.Errorf("No player named %v", prefix)
+}
+
+// runTransition walks the group through the end of the month: the result,
+// the upgrades chosen, scars and stickers taken, and the next game's funded
+// events and start cards. The result and upgrades go into the campaign, and
+// the next game's setup, with panic and everything else carried over, is
+// written to nextFile for start --new-game-file.
+func runTransition(in io.Reader, out io.Writer, campaign *pandemic.Campaign, gs *pandemic.GameState, template pandemic.NewGameSettings, nextFile string) error {
+	w := transitionWizard{in: bufio.NewReader(in), out: out}
+	month, attempt := pandemic.ParseMonth(gs.GameName)
+	fmt.Fprintf(out, "Ending %v attempt %v\n", month, attempt)
+
+	t := pandemic.MonthTransition{
+		Scars:      map[string][]string{},
+		Stickers:   map[pandemic.CityName][]string{},
+		StartCards: map[string][]pandemic.CardName{},
+	}
+	t.Won = strings.HasPrefix(strings.ToLower(w.ask("Did you win? [y/N] ")), "y")
+	t.Upgrades = w.askLines("Upgrades chosen by the group, one per line, blank to finish:")
+	for who, scars := range w.askPairs("Scars taken, as <player>: <scar>, blank to finish:") {
+		name, err := templatePlayer(template, who)
+		if err != nil {
+			return err
+		}
+		t.Scars[name] = append(t.Scars[name], scars...)
+	}
+	for city, stickers := range w.askPairs("Stickers put on cities, as <city>: <sticker>, blank to finish:") {
+		name, err := getCityByPrefix(city, gs)
+		if err != nil {
+			return err
+		}
+		t.Stickers[name] = append(t.Stickers[name], stickers...)
+	}
+
+	events := []string{}
+	for _, event := range template.FundedEvents {
+		events = append(events, string(event.Name))
+	}
+	if answer := w.ask(fmt.Sprintf("Funded events for the next game, comma separated [%v]: ", strings.Join(events, ", "))); answer != "" {
+		t.FundedEvents = []pandemic.FundedEventName{}
+		for _, event := range strings.Split(answer, ",") {
+			if event = strings.TrimSpace(event); event != "" {
+				t.FundedEvents = append(t.FundedEvents, pandemic.FundedEventName(strings.ToLower(event)))
+			}
+		}
+	}
+	for _, player := range template.Players {
+		cards := []string{}
+		for _, card := range player.StartCards {
+			cards = append(cards, string(card))
+		}
+		answer := w.ask(fmt.Sprintf("Start cards for %v [%v]: ", player.HumanName, strings.Join(cards, " ")))
+		if answer == "" {
+			continue
+		}
+		for _, prefix := range strings.Fields(answer) {
+			city, err := getCityByPrefix(prefix, gs)
+			if err != nil {
+				return err
+			}
+			t.StartCards[player.HumanName] = append(t.StartCards[player.HumanName], city.CardName())
+		}
+	}
+
+	next, err := gs.NextGameSettings(template, t)
+	if err != nil {
+		return err
+	}
+	result := campaign.RecordTransition(gs, t)
+	if err := campaign.Save(); err != nil {
+		return fmt.Errorf("Could not save campaign: %v", err)
+	}
+	if err := next.Save(nextFile); err != nil {
+		return fmt.Errorf("Could not write the next game's setup: %v", err)
+	}
+	outcome := "lost"
+	if result.Won {
+		outcome = "won"
+	}
+	fmt.Fprintf(out, "Recorded %v attempt %v as %v with %v upgrades\n", result.Month, result.Attempt, outcome, len(t.Upgrades))
+	for _, city := range next.Cities {
+		if city.PanicLevel != pandemic.Nothing {
+			fmt.Fprintf(out, "  %v stays %v\n", city.Name, city.PanicLevel)
+		}
+	}
+	fmt.Fprintf(out, "Start %v with: start --new-game-file %v\n", pandemic.GameNameFor(campaign.NextGame()), nextFile)
+	return nil
+}
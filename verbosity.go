@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+const (
+	terseOutput   = "terse"
+	normalOutput  = "normal"
+	verboseOutput = "verbose"
+)
+
+var verbosityFlags = map[string]string{
+	"-q": terseOutput,
+	"-v": verboseOutput,
+}
+
+func validVerbosity(verbosity string) bool {
+	return verbosity == terseOutput || verbosity == normalOutput || verbosity == verboseOutput
+}
+
+// commandVerbosity takes the -q and -v flags out of a command line and
+// returns the output level they ask for, or the configured one.
+func (p *PandemicView) commandVerbosity(commandArgs []string) ([]string, string) {
+	verbosity := p.config.Verbosity
+	args := []string{commandArgs[0]}
+	for _, arg := range commandArgs[1:] {
+		if flag, ok := verbosityFlags[arg]; ok {
+			verbosity = flag
+			continue
+		}
+		args = append(args, arg)
+	}
+	return args, verbosity
+}
+
+// terseWriter passes on the first line written to it and drops the rest, so
+// a command's confirmation gets through without its explanation.
+type terseWriter struct {
+	out  io.Writer
+	done bool
+}
+
+func (w *terseWriter) Write(b []byte) (int, error) {
+	if w.done {
+		return len(b), nil
+	}
+	line := b
+	if i := bytes.IndexByte(b, '\n'); i >= 0 {
+		line = b[:i+1]
+		w.done = true
+	}
+	if _, err := w.out.Write(line); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// explain prints the reasoning behind a command's result, only when the
+// output is verbose.
+func (p *PandemicView) explain(out io.Writer, format string, args ...interface{}) {
+	if p.verbosity != verboseOutput {
+		return
+	}
+	fmt.Fprint(out, p.t(format+"\n", args...))
+}
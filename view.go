@@ -2,9 +2,14 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math"
+	"os/exec"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/anthonybishopric/pandemic-nerd-hurd/pandemic"
@@ -14,22 +19,151 @@ import (
 
 type PandemicView struct {
 	logger              *logrus.Logger
+	campaign            *pandemic.Campaign
+	config              *Config
 	colorWhiteHighlight func(string, ...interface{}) string
 	colorAllGood        func(string, ...interface{}) string
 	colorWarning        func(string, ...interface{}) string
 	colorHighlight      func(string, ...interface{}) string
 	colorOhFuck         func(string, ...interface{}) string
 	fileSaveCounter     int
+	logs                *logBuffer
+	logLevel            logrus.Level
+	showLogs            bool
+	alerted             map[string]bool
+	// staged holds commands typed ahead of the table's decision, to be run
+	// in order by commit.
+	staged []string
+	// sandbox games are thrown away, so nothing is saved
+	sandbox bool
+	// lastCommand and checkpointed drive the idle checkpoint.
+	lastCommand  time.Time
+	checkpointed bool
+	overlay      *drawOverlay
+	popup        *cityPopup
+	rules        *rulesOverlay
+	rewind       *rewindPanel
+	simulation   *simulation
+	// revision counts the changes the engine published, keying cached
+	// results to the game they were worked out for.
+	revision  int
+	simulated simulationCache
+	// locked games only run read only commands until unlocked with
+	// lockPhrase.
+	locked     bool
+	lockPhrase string
+	// redraw asks the GUI to lay itself out again, e.g. when the overlay
+	// expires. It is nil outside the GUI.
+	redraw func()
+	// inGUI runs fn on the GUI's goroutine, e.g. to report on work done in
+	// the background. It is nil outside the GUI.
+	inGUI func(fn func())
+	// calibrationMu guards the calibration tallies on disk, which a
+	// telemetry send updates from the background.
+	calibrationMu sync.Mutex
+	// verbosity is the output level of the command being run.
+	verbosity string
+	// autoAdvanced is set when the last command that changed the game also
+	// started the next player's turn.
+	autoAdvanced bool
 }
 
-func NewView(logger *logrus.Logger) *PandemicView {
+// subscribe keeps the tracker up to date with the changes the engine makes
+// to the game. Every game the view works on is subscribed, and one that
+// replaces another is a change of its own.
+func (p *PandemicView) subscribe(game *pandemic.GameState) {
+	p.revision++
+	p.checkpointed = false
+	game.Subscribe(p.refresh)
+	game.Subscribe(p.autosave)
+	game.Subscribe(p.notify)
+	game.Subscribe(p.integrate)
+}
+
+// refresh marks the panels' cached simulations stale.
+func (p *PandemicView) refresh(pandemic.Change) {
+	p.revision++
+}
+
+// autosave marks the idle checkpoint stale, to be written again once the
+// table goes quiet.
+func (p *PandemicView) autosave(pandemic.Change) {
+	p.checkpointed = false
+}
+
+// notify reads epidemics and outbreaks out loud for the table.
+func (p *PandemicView) notify(change pandemic.Change) {
+	var words []string
+	switch change := change.(type) {
+	case pandemic.EpidemicDrawn:
+		words = []string{"Epidemic", "in", string(change.City)}
+	case pandemic.OutbreakChained:
+		words = []string{"Outbreak", "in", string(change.Chain[0].City)}
+		if len(change.Chain) > 1 {
+			words = append(words, "and", strconv.Itoa(len(change.Chain)-1), "more")
+		}
+	}
+	if len(words) == 0 {
+		return
+	}
+	if err := p.say(words...); err != nil {
+		p.logger.Warnf("Could not say %v out loud: %v", strings.Join(words, " "), err)
+	}
+}
+
+// integrate runs the command configured in integrations for each kind of
+// change, with a description of the change as its last argument. The
+// commands run in the background so a slow one doesn't hold up the table.
+// Sandbox games are kept to the tracker.
+func (p *PandemicView) integrate(change pandemic.Change) {
+	if p.sandbox {
+		return
+	}
+	var kind, what string
+	switch change := change.(type) {
+	case pandemic.CityInfected:
+		kind, what = "infected", fmt.Sprintf("%v infected with %v cubes", change.City, change.Cubes)
+	case pandemic.EpidemicDrawn:
+		kind, what = "epidemic", fmt.Sprintf("Epidemic in %v", change.City)
+	case pandemic.OutbreakChained:
+		kind, what = "outbreak", fmt.Sprintf("Outbreak in %v", change.Chain[0].City)
+		if len(change.Chain) > 1 {
+			what = fmt.Sprintf("%v and %v more", what, len(change.Chain)-1)
+		}
+	case pandemic.DiseaseCured:
+		kind, what = "cured", fmt.Sprintf("%v cured", change.Disease)
+	case pandemic.CityTreated:
+		kind, what = "treated", fmt.Sprintf("%v cubes treated in %v", change.Cubes, change.City)
+	case pandemic.GameChanged:
+		kind, what = "changed", change.What
+	}
+	command := strings.Fields(p.config.Integrations[kind])
+	if len(command) == 0 {
+		return
+	}
+	go func() {
+		if err := exec.Command(command[0], append(command[1:], what)...).Run(); err != nil {
+			p.logger.Warnf("The %v integration failed on %q: %v", kind, what, err)
+		}
+	}()
+}
+
+func NewView(logger *logrus.Logger, campaign *pandemic.Campaign, config *Config) *PandemicView {
+	theme := themes[config.Theme]
+	logs := &logBuffer{}
+	logger.Hooks.Add(logs)
 	return &PandemicView{
 		logger:              logger,
-		colorWhiteHighlight: color.New(color.FgBlack).Add(color.BgWhite).SprintfFunc(),
-		colorAllGood:        color.New(color.FgGreen).Add(color.BgBlack).SprintfFunc(),
-		colorWarning:        color.New(color.FgYellow).Add(color.BgBlack).SprintfFunc(),
-		colorHighlight:      color.New(color.FgRed).SprintfFunc(),
-		colorOhFuck:         color.New(color.FgBlack).Add(color.BgRed).Add(color.BlinkSlow).SprintfFunc(),
+		logs:                logs,
+		logLevel:            logger.Level,
+		alerted:             map[string]bool{},
+		campaign:            campaign,
+		config:              config,
+		colorWhiteHighlight: color.New(theme.whiteHighlight...).SprintfFunc(),
+		colorAllGood:        color.New(theme.allGood...).SprintfFunc(),
+		colorWarning:        color.New(theme.warning...).SprintfFunc(),
+		colorHighlight:      color.New(theme.highlight...).SprintfFunc(),
+		colorOhFuck:         color.New(theme.ohFuck...).SprintfFunc(),
 	}
 }
 
@@ -37,7 +171,7 @@ func (p *PandemicView) Start(game *pandemic.GameState) {
 	gui := gocui.NewGui()
 
 	if err := gui.Init(); err != nil {
-		p.logger.Errorln("Could not init GUI: %v", err)
+		p.logger.Errorf("Could not init GUI: %v", err)
 	}
 	defer gui.Close()
 
@@ -45,21 +179,43 @@ func (p *PandemicView) Start(game *pandemic.GameState) {
 		width, height := gui.Size()
 
 		p.renderCommandsView(game, gui, width)
-		p.renderStriations(game, gui, 2, height/2, width)
-		p.renderCityDeckAndTurns(game, gui, 0, height/2, width/2, height)
-		p.renderConsoleArea(game, gui, width/2, height/2, width, height)
+		p.layoutPanels(game, gui, width, height)
+		p.renderOverlay(gui, width, height)
+		p.renderCityPopup(game, gui, width, height)
+		p.renderRules(gui, width, height)
+		p.renderRewind(gui, width, height)
+		p.renderSimulation(gui, width, height)
+		p.renderLogs(gui, width, height)
 
 		p.setUpKeyBindings(game, gui, "Commands")
 		gui.Cursor = true
+		gui.Mouse = true
 		gui.SetCurrentView("Commands")
 		gui.Editor = gocui.DefaultEditor
 		return nil
 	})
 
+	p.redraw = func() {
+		gui.Execute(func(*gocui.Gui) error { return nil })
+	}
+	p.inGUI = func(fn func()) {
+		gui.Execute(func(*gocui.Gui) error {
+			fn()
+			return nil
+		})
+	}
+	p.handleSignals(game, gui)
+	if p.config.IdleSnapshotMinutes > 0 {
+		if err := p.writeCheckpoint(game); err != nil {
+			p.logger.Errorf("Could not write checkpoint: %v", err)
+		}
+		p.watchIdle(game, gui)
+	}
 	if err := gui.MainLoop(); err != nil && err != gocui.ErrQuit {
 		gui.Close()
 		p.logger.Fatalf("Error in game main loop: %v", err)
 	}
+	p.removeCheckpoint(game)
 }
 
 func (p *PandemicView) renderCommandsView(game *pandemic.GameState, gui *gocui.Gui, maxX int) {
@@ -70,7 +226,16 @@ func (p *PandemicView) renderCommandsView(game *pandemic.GameState, gui *gocui.G
 	}
 	commandView.Editable = true
 	commandView.Autoscroll = false
-	commandView.Title = "Commands"
+	commandView.Title = p.phaseIndicator(game)
+	if p.config.HotSeat {
+		commandView.Title = p.hotSeatPrompt(game)
+	}
+	if p.locked {
+		commandView.Title = "Locked, type unlock to continue"
+	}
+	if len(p.staged) > 0 {
+		commandView.Title += p.t(" (%v staged, commit or unstage)", len(p.staged))
+	}
 }
 
 func (p *PandemicView) renderCityDeckAndTurns(game *pandemic.GameState, gui *gocui.Gui, topX, topY, bottomX, bottomY int) {
@@ -87,19 +252,23 @@ func (p *PandemicView) renderCityDeckAndTurns(game *pandemic.GameState, gui *goc
 	total := analysis.FirstCardProbability + analysis.SecondCardProbability
 
 	fmt.Fprintf(cityView, "\U0001F912 \U0001F4A5  %.2f (%v)\n", total, p.fractionalize(total))
-	scenarioGuarantee := fmt.Sprintf("%v of %v Scenarios Guarantee Epidemic", analysis.ScenariosWith100, analysis.PossibleScenarios)
+	scenarioGuarantee := p.t("%v of %v Scenarios Guarantee Epidemic", analysis.ScenariosWith100, analysis.PossibleScenarios)
 	if analysis.ScenariosWith100 > 0 {
 		scenarioGuarantee = p.colorOhFuck(scenarioGuarantee)
 	}
 	fmt.Fprintln(cityView, scenarioGuarantee)
+	fmt.Fprintln(cityView, p.pileBoundaries(game.CityDeck.PilePosition()))
 
-	fmt.Fprintf(cityView, "Epidemic on First City: %v\n", p.colorEpidemicPercent(analysis.FirstCardProbability))
-	fmt.Fprintf(cityView, "Epidemic on Second City: %v\n", p.colorEpidemicPercent(analysis.SecondCardProbability))
-	fmt.Fprintf(cityView, " -> After First City Epidemic: %v\n", p.colorEpidemicPercent(analysis.SecondCardEpiAfterFirstEpi))
+	fmt.Fprint(cityView, p.t("Epidemic on First City: %v\n", p.colorEpidemicPercent(analysis.FirstCardProbability)))
+	fmt.Fprint(cityView, p.t("Epidemic on Second City: %v\n", p.colorEpidemicPercent(analysis.SecondCardProbability)))
+	fmt.Fprint(cityView, p.t(" -> After First City Epidemic: %v\n", p.colorEpidemicPercent(analysis.SecondCardEpiAfterFirstEpi)))
 
-	fmt.Fprintf(cityView, "Upcoming Draws Guaranteed Safe: %v\n", p.colorUpcomingSafeCount(analysis.ComingDrawsWith0))
+	fmt.Fprint(cityView, p.t("Upcoming Draws Guaranteed Safe: %v\n", p.colorUpcomingSafeCount(analysis.ComingDrawsWith0)))
+	fmt.Fprintln(cityView, p.simulatedOutbreaks(game))
+	fmt.Fprint(cityView, p.t("Infection Rate: %v\n", p.infectionRateTrack(game)))
+	fmt.Fprint(cityView, p.t("Research Stations: %v\n", p.researchStations(game)))
 
-	fmt.Fprintf(cityView, "Card counts %v  %v  ", p.iconFor(pandemic.Black.Type), game.CityDeck.RemainingCardsWith(pandemic.Black.Type, game.Cities))
+	fmt.Fprint(cityView, p.t("Card counts %v  %v  ", p.iconFor(pandemic.Black.Type), game.CityDeck.RemainingCardsWith(pandemic.Black.Type, game.Cities)))
 	fmt.Fprintf(cityView, "%v  %v  ", p.iconFor(pandemic.Red.Type), game.CityDeck.RemainingCardsWith(pandemic.Red.Type, game.Cities))
 	fmt.Fprintf(cityView, "%v  %v  ", p.iconFor(pandemic.Blue.Type), game.CityDeck.RemainingCardsWith(pandemic.Blue.Type, game.Cities))
 	fmt.Fprintf(cityView, "%v  %v  ", p.iconFor(pandemic.Yellow.Type), game.CityDeck.RemainingCardsWith(pandemic.Yellow.Type, game.Cities))
@@ -127,22 +296,28 @@ func (p *PandemicView) renderCityDeckAndTurns(game *pandemic.GameState, gui *goc
 		fmt.Fprint(turnView, " ")
 	}
 	fmt.Fprintln(turnView)
-	fmt.Fprintf(turnView, "%v has %v turns left\n", cur.Player.HumanName, game.GameTurns.RemainingTurnsFor(game.CityDeck.RemainingCards(), cur.Player.HumanName))
+	fmt.Fprint(turnView, p.t("%v has %v turns left\n", cur.Player.HumanName, game.GameTurns.RemainingTurnsFor(game.CityDeck.CardsLeftInDeck(), cur.Player.HumanName)))
 	if cur.Player.Character != nil && cur.Player.Character.TurnMessage != "" {
 		fmt.Fprintln(turnView, p.colorAllGood(cur.Player.Character.TurnMessage))
 	}
 
 	// print all cards
-	fmt.Fprint(turnView, "Cards: ")
+	fmt.Fprint(turnView, p.t("Cards: "))
 	for _, card := range cur.Player.Cards {
 		if card.IsCity() {
 			city, _ := game.Cities.GetCity(card.CityName)
-			fmt.Fprintf(turnView, "%v  %v ", p.iconFor(city.Disease), card.CityName[:4])
+			fmt.Fprintf(turnView, "%v  %v ", p.iconFor(city.Disease), p.shortCityLabel(card.CityName))
 		} else if card.IsFundedEvent() {
 			fmt.Fprintf(turnView, "\U0001F4B8  %v ", card.FundedEventName)
 		}
 	}
-	fmt.Fprintln(turnView, "\nCure Likelihood: ")
+	if cur.Player.UnknownCards > 0 {
+		fmt.Fprint(turnView, p.t("+%v unknown", cur.Player.UnknownCards))
+	}
+	if cur.Player.StoredEvent != nil {
+		fmt.Fprint(turnView, p.t("\nStored: \U0001F4B8  %v", cur.Player.StoredEvent.FundedEventName))
+	}
+	fmt.Fprintln(turnView, p.t("\nCure Likelihood: "))
 
 	// print curability stats
 	curability := byCurability{pandemic.CurableDiseases(), make(map[pandemic.DiseaseType]float64), make(map[pandemic.DiseaseType]maxCurability)}
@@ -171,6 +346,29 @@ func (p *PandemicView) renderCityDeckAndTurns(game *pandemic.GameState, gui *goc
 	}
 }
 
+// pileBoundaries renders how deep into the current epidemic pile we are, e.g.
+// "Pile 2/5 [####|.....?] 4 of 9-10". A '?' marks a card whose pile depends
+// on which scenarios are still possible.
+func (p *PandemicView) pileBoundaries(pos pandemic.PilePosition) string {
+	if pos.NumPiles == 0 || pos.Exhausted() {
+		return "No epidemic piles remaining"
+	}
+	if pos.MinPile != pos.MaxPile {
+		return p.colorWarning("%v", p.t("Pile %v-%v/%v: at an uncertain pile boundary", pos.MinPile+1, pos.MaxPile+1, pos.NumPiles))
+	}
+	remaining := int(math.Max(0, float64(pos.MinSize-pos.MaxDepth)))
+	bar := strings.Repeat("#", pos.MinDepth) + strings.Repeat("?", pos.MaxDepth-pos.MinDepth) + "|" +
+		strings.Repeat(".", remaining) + strings.Repeat("?", pos.MaxSize-pos.MinSize)
+	return p.t("Pile %v/%v [%v] %v of %v", pos.MinPile+1, pos.NumPiles, bar, rangeString(pos.MinDepth, pos.MaxDepth), rangeString(pos.MinSize, pos.MaxSize))
+}
+
+func rangeString(min, max int) string {
+	if min == max {
+		return fmt.Sprintf("%v", min)
+	}
+	return fmt.Sprintf("%v-%v", min, max)
+}
+
 func (p *PandemicView) iconFor(dt pandemic.DiseaseType) string {
 	var diseaseEmoji string
 	switch dt {
@@ -184,14 +382,34 @@ func (p *PandemicView) iconFor(dt pandemic.DiseaseType) string {
 		diseaseEmoji = "\u26ab"
 	case pandemic.Faded.Type:
 		diseaseEmoji = "\U0001f608"
+	case pandemic.Plague.Type:
+		diseaseEmoji = "\u2620"
 	default:
 		diseaseEmoji = string(dt)
 	}
 	return diseaseEmoji
 }
 
+// infectionRateTrack shows the track with the marker's space highlighted.
+func (p *PandemicView) infectionRateTrack(game *pandemic.GameState) string {
+	rules, err := game.Rules()
+	if err != nil {
+		return fmt.Sprintf("%v", game.InfectionRate)
+	}
+	spaces := []string{}
+	position := game.InfectionRatePosition()
+	for i, rate := range rules.InfectionRateTrack() {
+		if i == position {
+			spaces = append(spaces, p.colorWhiteHighlight("%v", game.InfectionRate))
+		} else {
+			spaces = append(spaces, fmt.Sprintf("%v", rate))
+		}
+	}
+	return strings.Join(spaces, " ")
+}
+
 func (p *PandemicView) colorUpcomingSafeCount(safe int) string {
-	if safe > 2 {
+	if safe > p.config.Thresholds.SafeDraws {
 		return p.colorAllGood(fmt.Sprintf("%v", safe))
 	} else if safe > 0 {
 		return p.colorWarning(fmt.Sprintf("%v", safe))
@@ -204,7 +422,7 @@ func (p *PandemicView) colorEpidemicPercent(total float64) string {
 	var outStr string
 	if total == 0.0 {
 		outStr = p.colorAllGood(fmt.Sprintf("%.3f", total))
-	} else if total > 0.5 {
+	} else if total > p.config.Thresholds.EpidemicDanger {
 		outStr = p.colorOhFuck(fmt.Sprintf("%.3f", total))
 	} else {
 		outStr = p.colorWarning(fmt.Sprintf("%.3f", total))
@@ -220,7 +438,7 @@ func (p *PandemicView) fractionalize(decimalRep float64) string {
 			dem = dem / divisor
 		}
 	}
-	return fmt.Sprintf("about %d out of %d", num, dem)
+	return p.t("about %d out of %d", num, dem)
 }
 
 func (p *PandemicView) terminateIfErr(err error, msg string, gui *gocui.Gui) {
@@ -231,14 +449,48 @@ func (p *PandemicView) terminateIfErr(err error, msg string, gui *gocui.Gui) {
 }
 
 func (p *PandemicView) setUpKeyBindings(game *pandemic.GameState, gui *gocui.Gui, commandView string) {
-	err := gui.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, func(gui *gocui.Gui, view *gocui.View) error {
+	err := gui.SetKeybinding("", p.config.key(p.config.Keys.Quit), gocui.ModNone, func(gui *gocui.Gui, view *gocui.View) error {
 		// when we get a ctrl-C we exit the game
 		gui.Close()
 		p.logger.Fatalf("Buh bye") // TODO: save
 		return nil
 	})
 	p.terminateIfErr(err, "could not establish graceful termination keybinding", gui)
+	err = gui.SetKeybinding("", p.config.key(p.config.Keys.Logs), gocui.ModNone, p.toggleLogs)
+	p.terminateIfErr(err, "could not establish log viewer keybinding", gui)
+	for panel, key := range p.config.Keys.Panels {
+		err = gui.SetKeybinding("", p.config.key(key), gocui.ModNone, p.togglePanel(panel))
+		p.terminateIfErr(err, "could not establish panel keybinding", gui)
+	}
+	err = gui.SetKeybinding("", gocui.MouseLeft, gocui.ModNone, p.pickCity(game))
+	p.terminateIfErr(err, "could not establish city popup keybinding", gui)
+	err = gui.SetKeybinding(commandView, p.config.key(p.config.Keys.Stats), gocui.ModNone, p.typedCityStats(game))
+	p.terminateIfErr(err, "could not establish city stats keybinding", gui)
+	err = gui.SetKeybinding("", p.config.key(p.config.Keys.Audit), gocui.ModNone, func(gui *gocui.Gui, view *gocui.View) error {
+		consoleView, err := gui.View("Console")
+		if err != nil {
+			return nil
+		}
+		return p.execute(game, consoleView, "audit")
+	})
+	p.terminateIfErr(err, "could not establish audit keybinding", gui)
+	err = gui.SetKeybinding("", gocui.KeyPgup, gocui.ModNone, p.scrollRules(-5))
+	p.terminateIfErr(err, "could not establish rules scrolling keybinding", gui)
+	err = gui.SetKeybinding("", gocui.KeyPgdn, gocui.ModNone, p.scrollRules(5))
+	p.terminateIfErr(err, "could not establish rules scrolling keybinding", gui)
+	err = gui.SetKeybinding("", gocui.KeyEsc, gocui.ModNone, p.closeRules)
+	p.terminateIfErr(err, "could not establish rules closing keybinding", gui)
+	err = gui.SetKeybinding("", p.config.key(p.config.Keys.Back), gocui.ModNone, p.stepRewind(-1))
+	p.terminateIfErr(err, "could not establish rewind keybinding", gui)
+	err = gui.SetKeybinding("", p.config.key(p.config.Keys.Forward), gocui.ModNone, p.stepRewind(1))
+	p.terminateIfErr(err, "could not establish rewind keybinding", gui)
+	err = gui.SetKeybinding("", gocui.KeyEsc, gocui.ModNone, p.closeRewind)
+	p.terminateIfErr(err, "could not establish rewind closing keybinding", gui)
 	err = gui.SetKeybinding(commandView, gocui.KeyEnter, gocui.ModNone, func(gui *gocui.Gui, view *gocui.View) error {
+		if !p.panelShown("console") {
+			// the command still runs, there is just nowhere to show it
+			return p.runCommand(game, ioutil.Discard, view)
+		}
 		consoleView, err := gui.View("Console")
 		if err != nil {
 			gui.Close()
@@ -247,7 +499,7 @@ func (p *PandemicView) setUpKeyBindings(game *pandemic.GameState, gui *gocui.Gui
 		}
 		return p.runCommand(game, consoleView, view)
 	})
-	err = gui.SetKeybinding(commandView, gocui.KeyTab, gocui.ModNone, func(gui *gocui.Gui, view *gocui.View) error {
+	err = gui.SetKeybinding(commandView, p.config.key(p.config.Keys.Complete), gocui.ModNone, func(gui *gocui.Gui, view *gocui.View) error {
 		cleanBuffer := strings.Trim(view.Buffer(), "\n\t\r ")
 		if cleanBuffer == "" {
 			return nil
@@ -275,8 +527,8 @@ func (p *PandemicView) renderConsoleArea(game *pandemic.GameState, gui *gocui.Gu
 	view.Wrap = true
 	view.Autoscroll = true
 	if err == gocui.ErrUnknownView {
-		fmt.Fprintf(view, "~ %v %v %v ~\n", p.colorAllGood("Pandemic Legacy"), p.colorHighlight("NeRd hUrD"), p.colorWarning("Assist-o-tron"))
-		fmt.Fprintf(view, "Starting %v, %v City Cards, %v Epidemics, %v Funded Events\n", game.GameName, game.CityDeck.Total(), game.CityDeck.NumEpidemics(), game.CityDeck.NumFundedEvents())
+		fmt.Fprintf(view, "~ %v %v %v ~\n", p.colorAllGood("%v", p.t("Pandemic Legacy")), p.colorHighlight("%v", p.t("NeRd hUrD")), p.colorWarning("%v", p.t("Assist-o-tron")))
+		fmt.Fprint(view, p.t("Starting %v, %v City Cards, %v Epidemics, %v Funded Events\n", game.GameName, game.CityDeck.Total(), game.CityDeck.NumEpidemics(), game.CityDeck.NumFundedEvents()))
 	}
 }
 
@@ -288,6 +540,8 @@ func (p *PandemicView) renderStriations(game *pandemic.GameState, gui *gocui.Gui
 	// Divide the horizontal space by 5 and make striations that width. The 5th
 	// column will be the drawn column
 	strWidth := int(math.Floor(float64(maxX) / 5.0))
+	// this turn's infection phase, at the rate after any epidemic drawn
+	phase := game.ForecastPhases(1, -1)
 
 	for i := len(game.InfectionDeck.Striations) - 1; i >= 0; i-- {
 		widthMultiplier := len(game.InfectionDeck.Striations) - i - 1
@@ -298,8 +552,18 @@ func (p *PandemicView) renderStriations(game *pandemic.GameState, gui *gocui.Gui
 			return err
 		}
 		strView.Clear()
-		strView.Title = strName
-		cityNames = game.SortBySeverity(cityNames)
+		strView.Title = p.t("Infection %v", i)
+		if game.InfectionDeck.WeaklyShuffled(i) {
+			strView.Title += p.t(" (weak)")
+		}
+		if len(phase) > 0 && phase[0].Draws[i] > 0 {
+			strView.Title += p.t(" · %v drawn", phase[0].Draws[i])
+		}
+		if p.config.risk().SortThreatsByProbability {
+			cityNames = game.SortByProbability(cityNames)
+		} else {
+			cityNames = game.SortBySeverity(cityNames)
+		}
 		for _, city := range cityNames {
 			p.terminateIfErr(p.printCityWithProb(game, strView, city), "Could not render city", gui)
 		}
@@ -310,13 +574,62 @@ func (p *PandemicView) renderStriations(game *pandemic.GameState, gui *gocui.Gui
 		return err
 	}
 	drawnView.Clear()
-	drawnView.Title = "Infection Drawn"
-	for _, city := range game.InfectionDeck.CitiesInDrawn() {
+	drawnView.Title = "Infection Discard (newest first)"
+	for _, city := range game.InfectionDeck.DiscardPile() {
 		p.terminateIfErr(p.printCityWithProb(game, drawnView, city), "Could not render drawn card", gui)
 	}
+	if removed := game.InfectionDeck.Removed.Members(); len(removed) > 0 {
+		fmt.Fprint(drawnView, p.t("\nRemoved: %v\n", strings.Join(removed, ", ")))
+	}
 	return nil
 }
 
+// researchStations lists the cities with a station and how many are left
+// to build.
+func (p *PandemicView) researchStations(game *pandemic.GameState) string {
+	stations := []string{}
+	for _, city := range game.Cities.ResearchStations() {
+		stations = append(stations, p.shortCityLabel(city))
+	}
+	if len(stations) == 0 {
+		stations = append(stations, "none")
+	}
+	return p.t("%v (%v left)", strings.Join(stations, ", "), game.ResearchStationsLeft())
+}
+
+// positionBadge marks cities whose exact place in the infection deck is
+// known, since that is a certainty rather than a probability, and the range
+// of places of those a Forecast put back in no known order.
+func positionBadge(game *pandemic.GameState, city pandemic.CityName) string {
+	pos, known := game.InfectionDeck.KnownPosition(city)
+	if !known {
+		return forecastBadge(game, city)
+	}
+	switch pos {
+	case 0:
+		return " [top]"
+	case 1:
+		return " [next]"
+	case game.InfectionDeck.Size() - 1:
+		return " [bottom]"
+	}
+	return fmt.Sprintf(" [#%v]", pos+1)
+}
+
+// forecastBadge gives the range of places a card put back by a Forecast,
+// in an order nobody noted, can be in, e.g. [#2-3?].
+func forecastBadge(game *pandemic.GameState, city pandemic.CityName) string {
+	if !game.InfectionDeck.FromForecast(city) {
+		return ""
+	}
+	for _, card := range game.InfectionDeck.Cards() {
+		if card.City == city && card.Location == pandemic.InStriation {
+			return fmt.Sprintf(" [#%v-%v?]", card.Position+1, card.Position+card.Candidates)
+		}
+	}
+	return ""
+}
+
 func (p *PandemicView) printCityWithProb(game *pandemic.GameState, view *gocui.View, city pandemic.CityName) error {
 	cityData, err := game.GetCity(city)
 	if err != nil {
@@ -334,14 +647,41 @@ func (p *PandemicView) printCityWithProb(game *pandemic.GameState, view *gocui.V
 	for i := 0; i < cityData.NumInfections; i++ {
 		infectionRateEmojis += "•"
 	}
+	for i := 0; i < cityData.SupplyCubes; i++ {
+		infectionRateEmojis += "▫"
+	}
 
-	quarantinedEmoji := ""
+	markerEmoji := ""
 	if cityData.Quarantined {
-		quarantinedEmoji = "\u26d4"
+		markerEmoji = "\u26d4"
+	}
+	if cityData.ResearchStation {
+		markerEmoji += "\u2302"
+	}
+	if game.ProtectedBy(city) != nil {
+		markerEmoji += "\u26e8"
+	}
+	eradicated := ""
+	if game.IsEradicated(cityData.Disease) {
+		eradicated = " [eradicated]"
+	}
+
+	// cubes already there mean a draw can spill over, so the chance alone
+	// undersells the city
+	expectedCubes := ""
+	if outcomes, _ := p.panelOutcomes(game); outcomes != nil && cityData.NumInfections > 0 {
+		expectedCubes = fmt.Sprintf(" +%.1f", outcomes.ExpectedCubesFrom(city))
+	}
+
+	// cubes can arrive from a neighbor's outbreak even when the city is safe
+	spillover := game.NeighborSpillover(city)
+	spilloverColumn := ""
+	if spillover > 0.0 {
+		spilloverColumn = fmt.Sprintf(" ~%.2f", spillover)
 	}
 
-	text := fmt.Sprintf("%v %s  %s  %s  %.2f", city[:4], diseaseEmoji, infectionRateEmojis, quarantinedEmoji, probability)
-	if probability == 0.0 {
+	text := fmt.Sprintf("%v %s  %s  %s  %.2f%v%v%v%v", p.shortCityLabel(city), diseaseEmoji, infectionRateEmojis, markerEmoji, probability, expectedCubes, spilloverColumn, positionBadge(game, city), eradicated)
+	if probability == 0.0 && spillover == 0.0 {
 		fmt.Fprintln(view, p.colorAllGood(text))
 	} else if game.CanOutbreak(city) {
 		fmt.Fprintln(view, p.colorOhFuck(text))
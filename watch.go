@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jroimartin/gocui"
+)
+
+const watchInterval = time.Second
+
+// latestSave finds the most recent save file in a game folder. Save files
+// are named game_<unix nanos>_<command>.json by the operator's terminal.
+func latestSave(dir string) (string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	var latest string
+	var latestTime int64
+	for _, file := range files {
+		parts := strings.SplitN(file.Name(), "_", 3)
+		if len(parts) != 3 || parts[0] != "game" {
+			continue
+		}
+		nanos, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		if nanos > latestTime {
+			latest = filepath.Join(dir, file.Name())
+			latestTime = nanos
+		}
+	}
+	if latest == "" {
+		return "", fmt.Errorf("No saved games in %v", dir)
+	}
+	return latest, nil
+}
+
+// Watch renders only the analysis panels, reloading whenever the operator's
+// terminal saves a new state into the game folder. It is meant for a second
+// screen that the whole table can see.
+func (p *PandemicView) Watch(dir string) {
+	file, err := latestSave(dir)
+	if err != nil {
+		p.logger.Fatalln(err)
+	}
+	game, err := loadSave(file)
+	if err != nil {
+		p.logger.Fatalln(err)
+	}
+	p.subscribe(game)
+
+	gui := gocui.NewGui()
+	if err := gui.Init(); err != nil {
+		p.logger.Errorf("Could not init GUI: %v", err)
+	}
+	defer gui.Close()
+
+	gui.SetLayout(func(gui *gocui.Gui) error {
+		width, height := gui.Size()
+		p.renderStriations(game, gui, 0, height/2, width)
+		p.renderCityDeckAndTurns(game, gui, 0, height/2, width/2, height)
+		p.renderCureProgress(game, gui, width/2, height/2, width, height/2+cureProgressHeight())
+		compositionTop := height/2 + cureProgressHeight()
+		p.renderComposition(game, gui, width/2, compositionTop, width, compositionTop+compositionHeight(game))
+		return nil
+	})
+	err = gui.SetKeybinding("", p.config.key(p.config.Keys.Quit), gocui.ModNone, func(gui *gocui.Gui, view *gocui.View) error {
+		return gocui.ErrQuit
+	})
+	p.terminateIfErr(err, "could not establish graceful termination keybinding", gui)
+
+	go func(loadedFile string) {
+		for range time.Tick(watchInterval) {
+			latest, err := latestSave(dir)
+			if err != nil || latest == loadedFile {
+				continue
+			}
+			loaded, err := loadSave(latest)
+			if err != nil {
+				p.logger.Warnf("Could not load %v: %v", latest, err)
+				continue
+			}
+			loadedFile = latest
+			gui.Execute(func(gui *gocui.Gui) error {
+				p.subscribe(loaded)
+				game = loaded
+				return nil
+			})
+		}
+	}(file)
+
+	if err := gui.MainLoop(); err != nil && err != gocui.ErrQuit {
+		gui.Close()
+		p.logger.Fatalf("Error in watch main loop: %v", err)
+	}
+}